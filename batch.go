@@ -0,0 +1,184 @@
+package rst
+
+import (
+	"bytes"
+	gocontext "context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// BatchRequest is a sub-request of a batch. Body is sent as is in the body
+// of the sub-request, with the application/json content type unless Header
+// says otherwise.
+type BatchRequest struct {
+	ID     string            `json:"id,omitempty"`
+	Method string            `json:"method"`
+	URL    string            `json:"url"`
+	Header map[string]string `json:"headers,omitempty"`
+	Body   json.RawMessage   `json:"body,omitempty"`
+}
+
+// BatchResponse is the response to a sub-request of a batch, identified by
+// the ID of the sub-request. JSON payloads are embedded in Body as they are,
+// and other text payloads are encoded as a JSON string. Payloads which aren't
+// valid UTF-8 are set in Base64Body instead, and encoded in base64.
+type BatchResponse struct {
+	ID         string            `json:"id,omitempty"`
+	Status     int               `json:"status"`
+	Header     map[string]string `json:"headers,omitempty"`
+	Body       json.RawMessage   `json:"body,omitempty"`
+	Base64Body []byte            `json:"base64Body,omitempty"`
+}
+
+// batchHeaders are the headers of a batch request which are not inherited by
+// its sub-requests, since they describe the body of the batch, or apply to
+// the representation of a single resource.
+var batchHeaders = map[string]bool{
+	"Accept-Encoding":     true,
+	"Content-Encoding":    true,
+	"Content-Length":      true,
+	"Content-Type":        true,
+	"If-Match":            true,
+	"If-Modified-Since":   true,
+	"If-None-Match":       true,
+	"If-Range":            true,
+	"If-Unmodified-Since": true,
+	"Idempotency-Key":     true,
+	"Range":               true,
+}
+
+// MaxBatchRequests is the maximum number of sub-requests in a batch served by
+// BatchHandler. Larger batches are rejected with a 413 Request Entity Too Large
+// error. A value of 0 disables the limit.
+var MaxBatchRequests = 100
+
+// batchKey marks the context of sub-requests, which can't be batches.
+type batchKey struct{}
+
+/*
+BatchHandler returns a handler serving a list of sub-requests in a single
+request, by dispatching each one of them through mux.
+
+	mux.Handle("/batch", rst.BatchHandler(mux))
+
+The body of the request is a JSON array of BatchRequest, and the response a
+JSON array of BatchResponse in the same order:
+
+	POST /batch HTTP/1.1
+	Content-Type: application/json
+
+	[
+		{"id": "me", "method": "GET", "url": "/people/42"},
+		{"id": "post", "method": "POST", "url": "/posts", "body": {"title": "rst"}}
+	]
+
+	HTTP/1.1 200 OK
+	Content-Type: application/json; charset=utf-8
+
+	[
+		{"id": "me", "status": 200, "headers": {...}, "body": {"id": 42, ...}},
+		{"id": "post", "status": 201, "headers": {...}, "body": {...}}
+	]
+
+Sub-requests are served one after the other with the headers of the batch
+request, like Authorization, completed by their own. Accept-Encoding, Range,
+Idempotency-Key, and the conditional headers of the batch request are not
+inherited, and must be set on the sub-requests they apply to. A sub-request failing
+doesn't fail the batch, whose status code is always 200 once its body has been
+decoded: the error is found in the item of the failing sub-request.
+Sub-requests can't target a batch handler, and batches can't list more than
+MaxBatchRequests sub-requests.
+*/
+func BatchHandler(mux *Mux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Context().Value(batchKey{}) != nil {
+			writeError(BadRequest("", "Batches can't be nested."), w, r)
+			return
+		}
+		if strings.ToUpper(r.Method) != Post {
+			writeError(MethodNotAllowed(r.Method, []string{Post}), w, r)
+			return
+		}
+		var requests []*BatchRequest
+		if err := DecodeJSON(r, &requests); err != nil {
+			writeError(err, w, r)
+			return
+		}
+		if MaxBatchRequests > 0 && len(requests) > MaxBatchRequests {
+			err := RequestEntityTooLarge()
+			err.Description = fmt.Sprintf("Batches can't list more than %d sub-requests.", MaxBatchRequests)
+			writeError(err, w, r)
+			return
+		}
+
+		responses := make([]*BatchResponse, len(requests))
+		for i, item := range requests {
+			responses[i] = serveBatchRequest(mux, item, r)
+		}
+
+		b, err := JSONMarshal(responses)
+		if err != nil {
+			writeError(err, w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write(b)
+	})
+}
+
+// serveBatchRequest serves item with mux, and returns the response it
+// received. r is the batch request.
+func serveBatchRequest(mux *Mux, item *BatchRequest, r *http.Request) *BatchResponse {
+	// The response is buffered like the ones of handlers served with a
+	// timeout.
+	bw := &timeoutWriter{header: make(http.Header)}
+
+	sub, err := http.NewRequest(strings.ToUpper(item.Method), item.URL, bytes.NewReader(item.Body))
+	switch {
+	case err != nil || item.Method == "" || !strings.HasPrefix(sub.URL.Path, "/"):
+		BadRequest("", "Sub-requests must have a method and an absolute path.").ServeHTTP(bw, r)
+	default:
+		sub = sub.WithContext(gocontext.WithValue(r.Context(), batchKey{}, true))
+		sub.Host, sub.RemoteAddr, sub.TLS = r.Host, r.RemoteAddr, r.TLS
+		for key, values := range r.Header {
+			if !batchHeaders[http.CanonicalHeaderKey(key)] {
+				sub.Header[key] = values
+			}
+		}
+		if len(item.Body) > 0 {
+			sub.Header.Set("Content-Type", "application/json")
+		}
+		for key, value := range item.Header {
+			sub.Header.Set(key, value)
+		}
+		mux.ServeHTTP(bw, sub)
+	}
+
+	resp := &BatchResponse{ID: item.ID, Status: bw.code}
+	if resp.Status == 0 {
+		resp.Status = http.StatusOK
+	}
+	if len(bw.header) > 0 {
+		resp.Header = make(map[string]string)
+		for key := range bw.header {
+			resp.Header[key] = strings.Join(bw.header[key], ", ")
+		}
+	}
+	if body := bw.body.Bytes(); len(body) > 0 {
+		mediaType, _, _ := mime.ParseMediaType(bw.header.Get("Content-Type"))
+		switch {
+		case (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")) && json.Valid(body):
+			resp.Body = body
+		case utf8.Valid(body):
+			resp.Body, _ = json.Marshal(string(body))
+		default:
+			resp.Base64Body = body
+		}
+	}
+	return resp
+}
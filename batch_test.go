@@ -0,0 +1,157 @@
+package rst
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBatchHandler(t *testing.T) {
+	resource := testPeople[len(testPeople)-1]
+	mux := NewMux()
+	mux.Handle("/people/{id}", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		if vars.Get("id") != resource.ID {
+			return nil, NotFound()
+		}
+		return resource, nil
+	}))
+	mux.Handle("/batch", BatchHandler(mux))
+
+	body := `[
+		{"id": "found", "method": "GET", "url": "/people/` + resource.ID + `"},
+		{"id": "missing", "method": "GET", "url": "/people/unknown"},
+		{"id": "nested", "method": "POST", "url": "/batch", "body": []}
+	]`
+	r, _ := http.NewRequest(Post, "/batch", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatal("status code. Got:", w.Code, "Wanted:", http.StatusOK)
+	}
+
+	var responses []*BatchResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &responses); err != nil {
+		t.Fatal(err)
+	}
+	if len(responses) != 3 {
+		t.Fatal("sub-responses. Got:", len(responses), "Wanted: 3")
+	}
+	for i, expected := range []struct {
+		id     string
+		status int
+	}{
+		{"found", http.StatusOK},
+		{"missing", http.StatusNotFound},
+		{"nested", http.StatusBadRequest},
+	} {
+		if got := responses[i]; got.ID != expected.id || got.Status != expected.status {
+			t.Fatal("sub-response", i, "Got:", got.ID, got.Status, "Wanted:", expected.id, expected.status)
+		}
+	}
+
+	var p person
+	if err := json.Unmarshal(responses[0].Body, &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.ID != resource.ID {
+		t.Fatal("sub-response body. Got:", p.ID, "Wanted:", resource.ID)
+	}
+	if etag := responses[0].Header["Etag"]; etag != resource.ETag() {
+		t.Fatal("sub-response ETag. Got:", etag, "Wanted:", resource.ETag())
+	}
+}
+
+func TestBatchHandlerHeaders(t *testing.T) {
+	binary := []byte{0x1f, 0x8b, 0xff, 0x00}
+	mux := NewMux()
+	mux.Handle("/text", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return Text(strings.Repeat("rst ", CompressionThreshold)), nil
+	}))
+	mux.Handle("/binary", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return Blob("application/octet-stream", binary), nil
+	}))
+	mux.Handle("/batch", BatchHandler(mux))
+
+	body := `[
+		{"method": "GET", "url": "/text"},
+		{"method": "GET", "url": "/binary"}
+	]`
+	r, _ := http.NewRequest(Post, "/batch", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Accept-Encoding", "gzip")
+	r.Header.Set("If-None-Match", "*")
+	r.Header.Set("Range", "bytes=0-1")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+
+	var responses []*BatchResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &responses); err != nil {
+		t.Fatal(err, w.Body.String())
+	}
+	for i, resp := range responses {
+		if resp.Status != http.StatusOK {
+			t.Fatal("sub-response", i, "status code. Got:", resp.Status, "Wanted:", http.StatusOK)
+		}
+		if encoding := resp.Header["Content-Encoding"]; encoding != "" {
+			t.Fatal("sub-response", i, "Content-Encoding. Got:", encoding, "Wanted: none")
+		}
+	}
+
+	var text string
+	if err := json.Unmarshal(responses[0].Body, &text); err != nil || text != strings.Repeat("rst ", CompressionThreshold) {
+		t.Fatal("text body. Got:", len(text), err)
+	}
+	if responses[1].Body != nil || !bytes.Equal(responses[1].Base64Body, binary) {
+		t.Fatal("binary body. Got:", responses[1].Body, responses[1].Base64Body, "Wanted:", binary)
+	}
+}
+
+func TestBatchHandlerNesting(t *testing.T) {
+	count := 0
+	mux := NewMux()
+	mux.Handle("/payments", IdempotencyHandler(postFunc(func(vars RouteVars, r *http.Request) (Resource, string, error) {
+		count++
+		return Text(strconv.Itoa(count)), "/payments/" + strconv.Itoa(count), nil
+	}), NewMemoryIdempotencyStore(100), time.Minute))
+	mux.Handle("/batch", BatchHandler(mux))
+	mux.Handle("/other-batch", BatchHandler(mux))
+
+	var test = func(body string, expected int) []*BatchResponse {
+		r, _ := http.NewRequest(Post, "/batch", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("Idempotency-Key", "batch")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal("status code. Got:", w.Code, "Wanted:", expected)
+		}
+		var responses []*BatchResponse
+		json.Unmarshal(w.Body.Bytes(), &responses)
+		return responses
+	}
+
+	responses := test(`[
+		{"method": "POST", "url": "/payments"},
+		{"method": "POST", "url": "/payments"},
+		{"method": "POST", "url": "/other-batch", "body": []}
+	]`, http.StatusOK)
+	for i, status := range []int{http.StatusCreated, http.StatusCreated, http.StatusBadRequest} {
+		if responses[i].Status != status {
+			t.Fatal("sub-response", i, "status code. Got:", responses[i].Status, "Wanted:", status)
+		}
+	}
+	if count != 2 {
+		t.Fatal("The sub-requests inherited the Idempotency-Key of the batch. Calls:", count)
+	}
+
+	defer func(original int) { MaxBatchRequests = original }(MaxBatchRequests)
+	MaxBatchRequests = 1
+	test(`[{"method": "GET", "url": "/payments"}, {"method": "GET", "url": "/payments"}]`, http.StatusRequestEntityTooLarge)
+}
@@ -0,0 +1,128 @@
+package rst
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// limitedBody is a request body that fails with a 413 Request Entity Too Large
+// error once more than limit bytes have been read from it.
+type limitedBody struct {
+	io.Reader
+	closer    io.Closer
+	remaining int64
+}
+
+func newLimitedBody(r io.Reader, closer io.Closer, limit int64) *limitedBody {
+	return &limitedBody{Reader: r, closer: closer, remaining: limit}
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, RequestEntityTooLarge()
+	}
+	// Reading one extra byte tells apart a body of exactly the limit from a
+	// body exceeding it.
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.Reader.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n + int(b.remaining), RequestEntityTooLarge()
+	}
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	return b.closer.Close()
+}
+
+// prepareBody enforces MaxBodyBytes on the body of r, and replaces it with its
+// decompressed version if s.DecompressRequests is true. The size limit applies
+// to the decompressed data to prevent decompression bombs.
+func (s *Mux) prepareBody(r *http.Request) *Error {
+	if r.Body == nil {
+		return nil
+	}
+
+	var reader io.Reader = r.Body
+	if s.DecompressRequests {
+		switch strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding"))) {
+		case gzipCompression:
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				return BadRequest("", "The body of the request could not be decompressed with gzip.")
+			}
+			reader = gz
+		case flateCompression:
+			reader = flate.NewReader(r.Body)
+		}
+		if reader != r.Body {
+			r.Header.Del("Content-Encoding")
+			r.Header.Del("Content-Length")
+			r.ContentLength = -1
+		}
+	}
+
+	if s.MaxBodyBytes <= 0 {
+		if reader != r.Body {
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{reader, r.Body}
+		}
+		return nil
+	}
+
+	if reader == r.Body && r.ContentLength > s.MaxBodyBytes {
+		return RequestEntityTooLarge()
+	}
+	r.Body = newLimitedBody(reader, r.Body, s.MaxBodyBytes)
+	return nil
+}
+
+/*
+DecodeJSON decodes the JSON body of r into v with JSONUnmarshal.
+
+	func (ep *PeopleEP) Post(vars rst.RouteVars, r *http.Request) (rst.Resource, string, error) {
+		var p Person
+		if err := rst.DecodeJSON(r, &p); err != nil {
+			return nil, "", err
+		}
+		...
+	}
+
+A 415 Unsupported Media Type error is returned if the Content-Type header of r
+is set to something else than JSON, and a 400 Bad Request error if the body
+can't be decoded. Errors raised while reading the body, like the 413 Request
+Entity Too Large error of MaxBodyBytes, are returned as is.
+*/
+func DecodeJSON(r *http.Request, v interface{}) error {
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, err := mime.ParseMediaType(ct)
+		if err != nil || (mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json")) {
+			return UnsupportedMediaType("application/json")
+		}
+	}
+	if r.Body == nil {
+		return BadRequest("", "The body of the request is empty.")
+	}
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		if e, ok := err.(*Error); ok {
+			return e
+		}
+		return BadRequest("", "The body of the request could not be read.")
+	}
+	if err := JSONUnmarshal(b, v); err != nil {
+		return BadRequest("", "The body of the request is not valid JSON.")
+	}
+	return nil
+}
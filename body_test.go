@@ -0,0 +1,102 @@
+package rst
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDecompressRequests(t *testing.T) {
+	mux := NewMux()
+	mux.DecompressRequests = true
+	mux.HandleEndpoint("/echo", &echoEndpoint{})
+
+	var test = func(data []byte, expected int) *httptest.ResponseRecorder {
+		buffer := new(bytes.Buffer)
+		gz := gzip.NewWriter(buffer)
+		gz.Write(data)
+		gz.Close()
+
+		r, _ := http.NewRequest(Post, "/echo", buffer)
+		r.Header.Set("Content-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal("status code. Got:", w.Code, "Wanted:", expected)
+		}
+		return w
+	}
+
+	w := test(testMBText, http.StatusCreated)
+	if !bytes.Equal(w.Body.Bytes(), testMBText) {
+		t.Fatal("decompressed body did not match the original data")
+	}
+
+	// A small compressed payload expanding beyond the limit.
+	mux.MaxBodyBytes = 64 << 10
+	bomb := make([]byte, 16<<20)
+	test(bomb, http.StatusRequestEntityTooLarge)
+	test(bomb[:mux.MaxBodyBytes], http.StatusCreated)
+}
+
+func TestMaxBodyBytes(t *testing.T) {
+	mux := NewMux()
+	mux.MaxBodyBytes = 1024
+	mux.HandleEndpoint("/echo", &echoEndpoint{})
+
+	var test = func(size int, expected int) {
+		r, _ := http.NewRequest(Post, "/echo", bytes.NewReader(testMBText[:size]))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal(size, "status code. Got:", w.Code, "Wanted:", expected)
+		}
+	}
+
+	test(1024, http.StatusCreated)
+	test(1025, http.StatusRequestEntityTooLarge)
+
+	// Bodies are not limited by default.
+	mux.MaxBodyBytes = NewMux().MaxBodyBytes
+	test(len(testMBText), http.StatusCreated)
+}
+
+func TestDecodeJSON(t *testing.T) {
+	var test = func(contentType, body string, expected int) {
+		r, _ := http.NewRequest(Post, "/", strings.NewReader(body))
+		if contentType != "" {
+			r.Header.Set("Content-Type", contentType)
+		}
+		var v map[string]string
+		err := DecodeJSON(r, &v)
+		if expected == 0 {
+			if err != nil {
+				t.Fatal(contentType, body, err)
+			}
+			if v["name"] != "rst" {
+				t.Fatal("Decoded value. Got:", v, "Wanted: map[name:rst]")
+			}
+			return
+		}
+		if e, ok := err.(*Error); !ok || e.Code != expected {
+			t.Fatal(contentType, body, "Got:", err, "Wanted:", expected)
+		}
+	}
+
+	test("application/json", `{"name":"rst"}`, 0)
+	test("application/merge-patch+json; charset=utf-8", `{"name":"rst"}`, 0)
+	test("", `{"name":"rst"}`, 0)
+	test("application/json", `{"name":`, http.StatusBadRequest)
+	test("text/plain", `{"name":"rst"}`, http.StatusUnsupportedMediaType)
+
+	// JSONUnmarshal is used to decode the body.
+	defer func(original func([]byte, interface{}) error) { JSONUnmarshal = original }(JSONUnmarshal)
+	JSONUnmarshal = func(b []byte, v interface{}) error {
+		*(v.(*map[string]string)) = map[string]string{"name": "rst"}
+		return nil
+	}
+	test("application/json", `not json`, 0)
+}
@@ -0,0 +1,214 @@
+package rst
+
+import (
+	"bytes"
+	"container/list"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+/*
+ResponseCache keeps the encoded representations of resources in memory, to
+serve them again without calling Marshal as long as the ETag of the resource
+doesn't change.
+
+	mux := rst.NewMux()
+	mux.ResponseCache = rst.NewResponseCache(64 << 20) // 64MB
+
+Representations are identified by the path of the request, the ETag of the
+resource, the Accept header, the fields query parameter when
+FieldSelection is enabled, and the parameters listed by resources implementing
+CacheKeyer. Compressed payloads are cached too, and identified
+by their encoding. This means that the encoding of a resource must only depend
+on these values, and that resources without an ETag are never cached.
+
+The least recently used representations are evicted when the total size of
+the cached payloads exceeds the limit of the cache.
+*/
+type ResponseCache struct {
+	mu      sync.Mutex
+	max     int
+	size    int
+	entries map[string]*list.Element
+	lru     *list.List // Most recently used first.
+}
+
+// cachedRepresentation is an entry of a ResponseCache.
+type cachedRepresentation struct {
+	key         string
+	contentType string
+	body        []byte
+}
+
+// NewResponseCache returns a new cache that holds up to maxBytes of payloads.
+func NewResponseCache(maxBytes int) *ResponseCache {
+	return &ResponseCache{
+		max:     maxBytes,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Len returns the number of representations in c.
+func (c *ResponseCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+func (c *ResponseCache) get(key string) (*cachedRepresentation, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, exists := c.entries[key]; exists {
+		c.lru.MoveToFront(e)
+		return e.Value.(*cachedRepresentation), true
+	}
+	return nil, false
+}
+
+func (c *ResponseCache) add(rep *cachedRepresentation) {
+	if len(rep.body) > c.max {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, exists := c.entries[rep.key]; exists {
+		c.size -= len(e.Value.(*cachedRepresentation).body)
+		c.lru.Remove(e)
+	}
+	c.entries[rep.key] = c.lru.PushFront(rep)
+	c.size += len(rep.body)
+
+	for c.size > c.max {
+		oldest := c.lru.Back()
+		evicted := c.lru.Remove(oldest).(*cachedRepresentation)
+		delete(c.entries, evicted.key)
+		c.size -= len(evicted.body)
+	}
+}
+
+// representationKey returns the key identifying the representation of
+// resource negotiated with r.
+func representationKey(resource Resource, r *http.Request) string {
+	parts := []string{r.URL.Path, resource.ETag(), r.Header.Get("Accept")}
+	if FieldSelection {
+		parts = append(parts, strings.Join(parseListParam(r, fieldsParam), ","))
+	}
+	if keyer, implemented := resource.(CacheKeyer); implemented {
+		query := r.URL.Query()
+		for _, param := range keyer.CacheKeyParams() {
+			parts = append(parts, param+"="+strings.Join(query[param], ","))
+		}
+	}
+	return strings.Join(parts, "\x00")
+}
+
+/*
+CacheKeyer is implemented by resources whose representation depends on query
+parameters of the request, which the Vary header can't express.
+
+	func (p *Person) CacheKeyParams() []string {
+		return []string{"lang"}
+	}
+
+Responses are written with a Cache-Key header made of the path of the request
+and of the values of these parameters, like /people/1?lang=fr, which caches and
+CDNs can use as the key of the response instead of the full URL. The fields
+parameter is always part of the key when FieldSelection is enabled, whether
+the resource implements CacheKeyer or not.
+*/
+type CacheKeyer interface {
+	CacheKeyParams() []string
+}
+
+/*
+Tagger is implemented by resources tagged for the purge of CDN caches. The tags
+are written in the CacheTagHeader header of the response.
+
+	func (p *Person) CacheTags() []string {
+		return []string{"user-" + p.ID, "org-" + p.OrgID}
+	}
+
+A request to /people/42 then receives a Surrogate-Key: user-42 org-7 header,
+and every cached response tagged with user-42 can be purged at once when the
+person is updated.
+*/
+type Tagger interface {
+	CacheTags() []string
+}
+
+// CacheTagHeader is the header in which the tags of resources implementing
+// Tagger are written. Fastly reads Surrogate-Key, while Cloudflare and Akamai
+// read Cache-Tag and Edge-Cache-Tag.
+var CacheTagHeader = "Surrogate-Key"
+
+// CacheTagSeparator separates the tags in CacheTagHeader. Surrogate-Key
+// expects spaces, and Cache-Tag commas.
+var CacheTagSeparator = " "
+
+// cacheKey returns the value of the Cache-Key header of the response to r,
+// or an empty string if the representation of resource doesn't depend on the
+// query of r.
+func cacheKey(resource Resource, r *http.Request) string {
+	var params []string
+	if FieldSelection {
+		params = append(params, fieldsParam)
+	}
+	if keyer, implemented := resource.(CacheKeyer); implemented {
+		params = append(params, keyer.CacheKeyParams()...)
+	}
+	if len(params) == 0 {
+		return ""
+	}
+
+	query, values := r.URL.Query(), make(url.Values)
+	for _, param := range params {
+		if v, exists := query[param]; exists {
+			values[param] = v
+		}
+	}
+	if len(values) == 0 {
+		return r.URL.Path
+	}
+	return r.URL.Path + "?" + values.Encode()
+}
+
+// marshal returns the representation cached for key, or the one returned by
+// encode, which is then added to c.
+func (c *ResponseCache) marshal(key string, encode func() (string, []byte, error)) (string, []byte, error) {
+	if rep, cached := c.get(key); cached {
+		return rep.contentType, rep.body, nil
+	}
+	contentType, b, err := encode()
+	if err != nil {
+		return "", nil, err
+	}
+	c.add(&cachedRepresentation{key, contentType, b})
+	return contentType, b, nil
+}
+
+// compress returns b compressed in format, from c if it's already there.
+func (c *ResponseCache) compress(key string, b []byte, format string) []byte {
+	key += "\x00" + format
+	if rep, cached := c.get(key); cached {
+		return rep.body
+	}
+
+	var buffer bytes.Buffer
+	compressor := getCompressor(&buffer, format)
+	compressor.Write(b)
+	putCompressor(compressor, format)
+	c.add(&cachedRepresentation{key: key, body: buffer.Bytes()})
+	return buffer.Bytes()
+}
+
+// responseCache returns the cache of the mux serving r, or nil.
+func responseCache(r *http.Request) *ResponseCache {
+	if m := getMux(r); m != nil {
+		return m.ResponseCache
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package rst
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// countingResource counts the number of times it's marshaled.
+type countingResource struct {
+	etag  string
+	body  []byte
+	count *int
+}
+
+func (c *countingResource) ETag() string            { return c.etag }
+func (c *countingResource) LastModified() time.Time { return time.Time{} }
+func (c *countingResource) TTL() time.Duration      { return 0 }
+
+func (c *countingResource) MarshalRST(r *http.Request) (string, []byte, error) {
+	*c.count++
+	return "text/plain; charset=utf-8", c.body, nil
+}
+
+type countingEndpoint struct {
+	resource *countingResource
+}
+
+func (ep *countingEndpoint) Get(vars RouteVars, r *http.Request) (Resource, error) {
+	return ep.resource, nil
+}
+
+func TestResponseCache(t *testing.T) {
+	var count int
+	resource := &countingResource{"v1", bytes.Repeat([]byte("rst "), CompressionThreshold), &count}
+
+	mux := NewMux()
+	mux.ResponseCache = NewResponseCache(1 << 20)
+	mux.HandleEndpoint("/counted", &countingEndpoint{resource})
+
+	var test = func(encoding string, marshaled int) {
+		r, _ := newRequest("GET /counted HTTP/1.1\nHost: www.example.com\nAccept-Encoding: " + encoding + "\n\n")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatal("Status code. Got:", w.Code, "Wanted:", http.StatusOK)
+		}
+		if count != marshaled {
+			t.Fatal("Marshal calls. Got:", count, "Wanted:", marshaled)
+		}
+
+		body := w.Body.Bytes()
+		if encoding != "" {
+			if got := w.Header().Get("Content-Encoding"); got != encoding {
+				t.Fatal("Content-Encoding. Got:", got, "Wanted:", encoding)
+			}
+			var err error
+			if body, err = decompress(ioutil.NopCloser(w.Body), encoding); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if !bytes.Equal(body, resource.body) {
+			t.Fatal("Body. Got:", len(body), "bytes. Wanted:", len(resource.body))
+		}
+	}
+
+	test("", 1)
+	test("", 1)
+	test("gzip", 1)
+	test("gzip", 1)
+	test("deflate", 1)
+
+	// A new version of the resource is marshaled again.
+	resource.etag = "v2"
+	test("gzip", 2)
+	test("", 2)
+}
+
+func TestResponseCacheEviction(t *testing.T) {
+	cache := NewResponseCache(10)
+	cache.add(&cachedRepresentation{key: "a", body: []byte("12345")})
+	cache.add(&cachedRepresentation{key: "b", body: []byte("12345")})
+	cache.get("a")
+	cache.add(&cachedRepresentation{key: "c", body: []byte("12345")})
+
+	if _, cached := cache.get("b"); cached {
+		t.Fatal("The least recently used representation was not evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, cached := cache.get(key); !cached {
+			t.Fatal(key, "was evicted")
+		}
+	}
+
+	cache.add(&cachedRepresentation{key: "large", body: []byte(strings.Repeat("x", 11))})
+	if cache.Len() != 2 {
+		t.Fatal("Len. Got:", cache.Len(), "Wanted:", 2)
+	}
+}
+
+// localizedResource is a resource whose representation depends on the lang
+// query parameter.
+type localizedResource struct {
+	*countingResource
+}
+
+func (l *localizedResource) CacheKeyParams() []string {
+	return []string{"lang"}
+}
+
+func TestCacheKey(t *testing.T) {
+	defer func(original bool) { FieldSelection = original }(FieldSelection)
+	var count int
+	resource := &localizedResource{&countingResource{"v1", []byte("hello"), &count}}
+	mux := NewMux()
+	mux.ResponseCache = NewResponseCache(1 << 20)
+	mux.Handle("/greeting", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return resource, nil
+	}))
+	mux.Handle("/people/1", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return testPeople[len(testPeople)-1], nil
+	}))
+
+	var test = func(url, expected string) {
+		r, _ := http.NewRequest(Get, url, nil)
+		r.Header.Set("Accept", "application/json")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if got := w.Header().Get("Cache-Key"); got != expected {
+			t.Fatal(url, "Cache-Key. Got:", got, "Wanted:", expected)
+		}
+	}
+
+	test("/people/1?fields=id", "")
+	FieldSelection = true
+	test("/people/1?fields=id,name&sort=asc", "/people/1?fields=id%2Cname")
+	test("/people/1", "/people/1")
+
+	test("/greeting?lang=fr&utm_source=mail", "/greeting?lang=fr")
+	test("/greeting?lang=en", "/greeting?lang=en")
+	// Each language is cached separately.
+	if count != 2 {
+		t.Fatal("Marshal calls. Got:", count, "Wanted:", 2)
+	}
+	test("/greeting?lang=fr", "/greeting?lang=fr")
+	if count != 2 {
+		t.Fatal("Marshal calls. Got:", count, "Wanted:", 2)
+	}
+}
+
+// taggedResource is a resource tagged for the purge of CDN caches.
+type taggedResource struct {
+	Resource
+	tags []string
+}
+
+func (tr *taggedResource) CacheTags() []string {
+	return tr.tags
+}
+
+func TestCacheTags(t *testing.T) {
+	defer func(header, separator string) {
+		CacheTagHeader, CacheTagSeparator = header, separator
+	}(CacheTagHeader, CacheTagSeparator)
+	mux := NewMux()
+	mux.Handle("/people/42", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return &taggedResource{Text(testCannedContent), []string{"user-42", "org-7"}}, nil
+	}))
+
+	var test = func(header, expected string) {
+		r, _ := http.NewRequest(Get, "/people/42", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if got := w.Header().Get(header); got != expected {
+			t.Fatal(header, "Got:", got, "Wanted:", expected)
+		}
+	}
+
+	test("Surrogate-Key", "user-42 org-7")
+	CacheTagHeader, CacheTagSeparator = "Cache-Tag", ","
+	test("Cache-Tag", "user-42,org-7")
+	test("Surrogate-Key", "")
+}
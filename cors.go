@@ -87,13 +87,70 @@ func ParseAccessControlRequest(r *http.Request) *AccessControlRequest {
 
 // AccessControlResponse defines the response headers to a CORS access control
 // request.
+//
+// ExposedHeaders lists the response headers browsers let cross-origin scripts
+// read, in addition to the CORS-safelisted ones. When ExposeRSTHeaders is set,
+// the headers written by rst itself are exposed too: Accept-Ranges, Cache-Key,
+// Content-Location, Content-Range, Deprecation, ETag, Link, Location, Sunset,
+// X-Error-ID and X-Total-Count, as well as the request ID header of the mux
+// when PropagateRequestID is enabled, and CacheTagHeader when it's set.
 type AccessControlResponse struct {
-	Origin         string
-	ExposedHeaders []string
-	Methods        []string // Empty array means any, nil means none.
-	AllowedHeaders []string // Empty array means any, nil means none.
-	Credentials    bool
-	MaxAge         time.Duration
+	Origin           string
+	ExposedHeaders   []string
+	ExposeRSTHeaders bool
+	Methods          []string // Empty array means any, nil means none.
+	AllowedHeaders   []string // Empty array means any, nil means none.
+	Credentials      bool
+	MaxAge           time.Duration
+}
+
+// rstHeaders are the response headers written by rst which are not
+// CORS-safelisted.
+var rstHeaders = []string{
+	"Accept-Ranges",
+	"Cache-Key",
+	"Content-Location",
+	"Content-Range",
+	"Deprecation",
+	"Etag",
+	"Link",
+	"Location",
+	"Sunset",
+	"X-Error-Id",
+	"X-Total-Count",
+}
+
+// exposedHeaders returns the headers listed in Access-Control-Expose-Headers
+// in the responses to requests served by mux m, which may be nil.
+func (ac *AccessControlResponse) exposedHeaders(m *Mux) []string {
+	var (
+		headers []string
+		seen    = make(map[string]bool)
+	)
+	add := func(names ...string) {
+		for _, name := range names {
+			name = http.CanonicalHeaderKey(name)
+			if !seen[name] {
+				seen[name] = true
+				headers = append(headers, name)
+			}
+		}
+	}
+	add(ac.ExposedHeaders...)
+	if ac.ExposeRSTHeaders {
+		add(rstHeaders...)
+		if m != nil && m.PropagateRequestID {
+			if m.RequestIDHeader != "" {
+				add(m.RequestIDHeader)
+			} else {
+				add(DefaultRequestIDHeader)
+			}
+		}
+		if CacheTagHeader != "" {
+			add(CacheTagHeader)
+		}
+	}
+	return headers
 }
 
 type accessControlHandler struct {
@@ -141,8 +198,8 @@ func (h *accessControlHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	w.Header().Set("Access-Control-Allow-Credentials", strconv.FormatBool(resp.Credentials))
 
 	// Exposed headers
-	if len(resp.ExposedHeaders) > 0 {
-		w.Header().Set("Access-Control-Expose-Headers", strings.Join(normalizeHeaderArray(resp.ExposedHeaders), ", "))
+	if exposed := resp.exposedHeaders(getMux(r)); len(exposed) > 0 {
+		w.Header().Set("Access-Control-Expose-Headers", strings.Join(exposed, ", "))
 	}
 
 	// OPTIONS only
@@ -150,7 +207,11 @@ func (h *accessControlHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(resp.MaxAge.Seconds())))
+	maxAge := resp.MaxAge
+	if m := getMux(r); maxAge == 0 && m != nil {
+		maxAge = m.OptionsMaxAge
+	}
+	w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(maxAge.Seconds())))
 
 	if req.Method != "" && resp.Methods != nil {
 		var methods []string
@@ -2,7 +2,9 @@ package rst
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 var testCORSHeaders = []string{
@@ -202,3 +204,59 @@ func TestPreflightInterface(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestOptionsMaxAge(t *testing.T) {
+	mux := NewMux()
+	mux.OptionsMaxAge = 10 * time.Minute
+	mux.HandleEndpoint("/people", &getterOnly{})
+
+	var test = func(header http.Header, maxAge string) {
+		r, _ := http.NewRequest(Options, "/people", nil)
+		r.Header = header
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if got, expected := w.Header().Get("Cache-Control"), "max-age=600"; got != expected {
+			t.Fatal("Cache-Control. Got:", got, "Wanted:", expected)
+		}
+		if got := w.Header().Get("Access-Control-Max-Age"); got != maxAge {
+			t.Fatal("Access-Control-Max-Age. Got:", got, "Wanted:", maxAge)
+		}
+	}
+
+	// Plain OPTIONS requests.
+	test(make(http.Header), "")
+
+	preflight := make(http.Header)
+	preflight.Set("Origin", "example.com")
+	preflight.Set("Access-Control-Request-Method", Get)
+	mux.SetCORSPolicy(&AccessControlResponse{Origin: "*"})
+	test(preflight, "600")
+
+	// The MaxAge of the policy takes precedence.
+	mux.SetCORSPolicy(DefaultAccessControl)
+	test(preflight, "86400")
+}
+
+func TestExposeRSTHeaders(t *testing.T) {
+	mux := NewMux()
+	mux.PropagateRequestID = true
+	mux.HandleEndpoint("/people", &getterOnly{})
+
+	var test = func(ac *AccessControlResponse, expected string) {
+		mux.SetCORSPolicy(ac)
+		r, _ := http.NewRequest(Get, "/people", nil)
+		r.Header.Set("Origin", "example.com")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if got := w.Header().Get("Access-Control-Expose-Headers"); got != expected {
+			t.Fatal("Access-Control-Expose-Headers. Got:", got, "Wanted:", expected)
+		}
+	}
+
+	test(&AccessControlResponse{Origin: "*", ExposedHeaders: []string{"x-custom"}}, "X-Custom")
+	test(&AccessControlResponse{
+		Origin:           "*",
+		ExposedHeaders:   []string{"X-Custom", "ETag"},
+		ExposeRSTHeaders: true,
+	}, "X-Custom, Etag, Accept-Ranges, Cache-Key, Content-Location, Content-Range, Deprecation, Link, Location, Sunset, X-Error-Id, X-Total-Count, X-Request-Id, Surrogate-Key")
+}
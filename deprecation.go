@@ -0,0 +1,43 @@
+package rst
+
+import (
+	"net/http"
+	"time"
+)
+
+/*
+Deprecator is implemented by endpoints which are deprecated, and will be
+removed from the API at the date returned by Deprecated.
+
+	func (ep *LegacyPeopleEP) Deprecated() (time.Time, string) {
+		return time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), "/docs/migrations/people-v2"
+	}
+
+Every response of the endpoint, errors included, is written with a
+Deprecation: true header, a Sunset header at the given date as defined by
+RFC 8594, and a Link header to infoURL with the deprecation relation type,
+which tells clients where to learn about the replacement of the endpoint.
+
+The Sunset header is omitted when sunset is the zero time, and the Link
+header when infoURL is empty.
+*/
+type Deprecator interface {
+	Deprecated() (sunset time.Time, infoURL string)
+}
+
+// writeDeprecationHeaders writes the headers describing the deprecation of
+// endpoint in the response, if it implements Deprecator.
+func writeDeprecationHeaders(endpoint Endpoint, w http.ResponseWriter, r *http.Request) {
+	deprecator, implemented := endpoint.(Deprecator)
+	if !implemented {
+		return
+	}
+	sunset, infoURL := deprecator.Deprecated()
+	w.Header().Set("Deprecation", "true")
+	if !sunset.IsZero() {
+		w.Header().Set("Sunset", sunset.UTC().Format(rfc1123))
+	}
+	if infoURL != "" {
+		w.Header().Add("Link", "<"+absoluteURL(r, infoURL)+`>; rel="deprecation"`)
+	}
+}
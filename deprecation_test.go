@@ -0,0 +1,59 @@
+package rst
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type deprecatedEndpoint struct {
+	sunset  time.Time
+	infoURL string
+}
+
+func (ep *deprecatedEndpoint) Get(vars RouteVars, r *http.Request) (Resource, error) {
+	if vars.Get("id") != "1" {
+		return nil, NotFound()
+	}
+	return Text(testCannedContent), nil
+}
+
+func (ep *deprecatedEndpoint) Deprecated() (time.Time, string) {
+	return ep.sunset, ep.infoURL
+}
+
+func TestDeprecator(t *testing.T) {
+	endpoint := &deprecatedEndpoint{time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), "/docs/deprecations"}
+	mux := NewMux()
+	mux.HandleEndpoint("/legacy/{id}", endpoint)
+	mux.Handle("/people/{id}", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return Text(testCannedContent), nil
+	}))
+
+	var test = func(path string, status int, header http.Header) {
+		r, _ := newRequest("GET " + path + " HTTP/1.1\nHost: www.example.com\n\n")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != status {
+			t.Fatal(path, "status code. Got:", w.Code, "Wanted:", status)
+		}
+		for _, key := range []string{"Deprecation", "Sunset", "Link"} {
+			if got, want := w.Header().Get(key), header.Get(key); got != want {
+				t.Fatal(path, key, "Got:", got, "Wanted:", want)
+			}
+		}
+	}
+
+	deprecated := http.Header{
+		"Deprecation": {"true"},
+		"Sunset":      {"Sun, 01 Jan 2017 00:00:00 GMT"},
+		"Link":        {`<http://www.example.com/docs/deprecations>; rel="deprecation"`},
+	}
+	test("/legacy/1", http.StatusOK, deprecated)
+	test("/legacy/2", http.StatusNotFound, deprecated)
+	test("/people/1", http.StatusOK, http.Header{})
+
+	endpoint.sunset, endpoint.infoURL = time.Time{}, ""
+	test("/legacy/1", http.StatusOK, http.Header{"Deprecation": {"true"}})
+}
@@ -0,0 +1,88 @@
+package rst
+
+import (
+	"encoding/xml"
+	"net/http"
+	"time"
+)
+
+// serviceManifest is the resource served by discovery endpoints.
+type serviceManifest struct {
+	XMLName  xml.Name         `json:"-" xml:"manifest"`
+	Routes   []*routeManifest `json:"routes" xml:"route"`
+	etag     string
+	modified time.Time
+}
+
+// routeManifest describes a route in a serviceManifest.
+type routeManifest struct {
+	RouteInfo
+	MediaTypes []string `json:"mediaTypes,omitempty" xml:"mediaType"`
+}
+
+// ETag implements the rst.Resource interface.
+func (m *serviceManifest) ETag() string {
+	return m.etag
+}
+
+// LastModified implements the rst.Resource interface.
+func (m *serviceManifest) LastModified() time.Time {
+	return m.modified
+}
+
+// TTL implements the rst.Resource interface.
+func (m *serviceManifest) TTL() time.Duration {
+	return 0
+}
+
+// discoveryEndpoint serves the manifest of the routes of a Mux.
+type discoveryEndpoint struct {
+	mux *Mux
+}
+
+// Get implements the rst.Getter interface.
+func (ep *discoveryEndpoint) Get(vars RouteVars, r *http.Request) (Resource, error) {
+	ep.mux.mu.RLock()
+	routes := append([]*route(nil), ep.mux.routes...)
+	ep.mux.mu.RUnlock()
+
+	manifest := &serviceManifest{}
+	for _, rt := range routes {
+		rm := &routeManifest{RouteInfo: rt.info()}
+		if endpoint := endpointOf(rt.handler); endpoint != nil && len(rm.Methods) > 0 {
+			rm.MediaTypes = advertisedTypes(endpoint)
+		}
+		manifest.Routes = append(manifest.Routes, rm)
+	}
+
+	etag, err := ContentETag(manifest.Routes)
+	if err != nil {
+		return nil, err
+	}
+	manifest.etag = etag
+
+	ep.mux.mu.RLock()
+	manifest.modified = ep.mux.modified
+	ep.mux.mu.RUnlock()
+
+	return manifest, nil
+}
+
+/*
+HandleDiscovery registers at pattern an endpoint that describes the service
+exposed by s. It answers GET, HEAD and OPTIONS requests with a manifest that
+lists the routes of s, the methods allowed by each endpoint, and the media
+types in which their resources can be encoded.
+
+	mux.HandleDiscovery("/")
+
+	GET / HTTP/1.1
+	Accept: application/json
+
+	{"routes":[{"pattern":"/people/{id}","methods":["HEAD","GET","DELETE"],"mediaTypes":[...]}]}
+
+Routes registered after the discovery endpoint are included in the manifest.
+*/
+func (s *Mux) HandleDiscovery(pattern string) {
+	s.HandleEndpoint(pattern, &discoveryEndpoint{s})
+}
@@ -0,0 +1,99 @@
+package rst
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestHandleDiscovery(t *testing.T) {
+	mux := NewMux()
+	mux.HandleEndpoint("/people", &peopleCollection{})
+	mux.HandleEndpoint("/people/{id}", &personResource{})
+	mux.HandleDiscovery("/")
+
+	r, _ := newRequest("GET / HTTP/1.1\nHost: www.example.com\nAccept: application/json\n\n")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatal("Status code. Got:", w.Code, "Wanted:", http.StatusOK)
+	}
+
+	var manifest struct {
+		Routes []struct {
+			Pattern    string   `json:"pattern"`
+			Methods    []string `json:"methods"`
+			MediaTypes []string `json:"mediaTypes"`
+		} `json:"routes"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &manifest); err != nil {
+		t.Fatal(err)
+	}
+	if len(manifest.Routes) != 3 {
+		t.Fatal("Routes. Got:", len(manifest.Routes), "Wanted:", 3)
+	}
+
+	var test = func(i int, pattern string, endpoint Endpoint) {
+		route := manifest.Routes[i]
+		if route.Pattern != pattern {
+			t.Fatal("Pattern. Got:", route.Pattern, "Wanted:", pattern)
+		}
+		if wanted := AllowedMethods(endpoint); !reflect.DeepEqual(route.Methods, wanted) {
+			t.Fatal("Methods of", pattern, "Got:", route.Methods, "Wanted:", wanted)
+		}
+		if len(route.MediaTypes) == 0 || route.MediaTypes[0] != "application/json" {
+			t.Fatal("Media types of", pattern, "Got:", route.MediaTypes)
+		}
+	}
+	test(0, "/people", &peopleCollection{})
+	test(1, "/people/{id}", &personResource{})
+
+	// OPTIONS is answered too.
+	r, _ = newRequest("OPTIONS / HTTP/1.1\nHost: www.example.com\n\n")
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	if got, wanted := w.Header().Get("Allow"), "HEAD, GET"; got != wanted {
+		t.Fatal("Allow. Got:", got, "Wanted:", wanted)
+	}
+}
+
+func TestDiscoveryMediaTypes(t *testing.T) {
+	mux := NewMux()
+	mux.HandleEndpoint("/people", &peopleCollection{})
+	mux.HandleEndpoint("/export", &csvExport{})
+	mux.HandleDiscovery("/")
+
+	var test = func() [][]string {
+		r, _ := newRequest("GET / HTTP/1.1\nHost: www.example.com\nAccept: application/json\n\n")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		var manifest struct {
+			Routes []struct {
+				MediaTypes []string `json:"mediaTypes"`
+			} `json:"routes"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &manifest); err != nil {
+			t.Fatal(err)
+		}
+		var types [][]string
+		for _, route := range manifest.Routes {
+			types = append(types, route.MediaTypes)
+		}
+		return types
+	}
+
+	types := test()
+	if got, wanted := types[1], []string{"text/csv"}; !reflect.DeepEqual(got, wanted) {
+		t.Fatal("Media types of a Producer. Got:", got, "Wanted:", wanted)
+	}
+
+	// The advertised types can be modified without changing the ones of the
+	// package.
+	advertised := advertisedTypes(&peopleCollection{})
+	_ = append(advertised[:len(advertised)-1], "text/csv")
+	if got := test()[0]; !reflect.DeepEqual(got, types[0]) {
+		t.Fatal("Media types. Got:", got, "Wanted:", types[0])
+	}
+}
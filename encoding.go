@@ -2,12 +2,15 @@ package rst
 
 import (
 	"bytes"
+	"crypto/sha1"
 	"encoding"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"html/template"
 	"net/http"
 	"reflect"
+	"sort"
 	"strings"
 )
 
@@ -50,13 +53,225 @@ type Marshaler interface {
 	MarshalRST(*http.Request) (contentType string, data []byte, err error)
 }
 
+/*
+Representable is implemented by resources wishing to be encoded differently
+depending on the content type negotiated with the client, without taking over
+the encoding process like Marshaler does.
+
+Example:
+	type Invoice struct {
+		ID    string
+		Lines []*Line
+		Total float64
+	}
+
+	// Representation returns a compact summary of the invoice to clients
+	// asking for text/plain, and the invoice itself to all the others.
+	func (i *Invoice) Representation(contentType string, r *http.Request) (interface{}, error) {
+		if contentType == "text/plain" {
+			return fmt.Sprintf("%s: %.2f", i.ID, i.Total), nil
+		}
+		return i, nil
+	}
+*/
+type Representable interface {
+	// Representation returns the value to encode in contentType, which is
+	// one of the media types supported by MarshalResource, without
+	// parameters.
+	Representation(contentType string, r *http.Request) (interface{}, error)
+}
+
+/*
+JSONMarshal is the function used to encode resources in JSON. It defaults to
+json.Marshal, and can be replaced by any implementation compatible with
+encoding/json:
+
+	rst.JSONMarshal = jsoniter.ConfigCompatibleWithStandardLibrary.Marshal
+*/
+var JSONMarshal = json.Marshal
+
+// JSONUnmarshal is the function used by DecodeJSON to decode request bodies.
+// It defaults to json.Unmarshal.
+var JSONUnmarshal = json.Unmarshal
+
 var jsonNull = []byte("null")
 
+/*
+HTMLRenderer is implemented by resources which can be rendered in HTML for the
+clients accepting text/html, like browsers, with a template of html/template
+executed against data.
+
+	var profileTemplate = template.Must(template.ParseFiles("profile.html"))
+
+	func (p *Profile) Template() (*template.Template, interface{}) {
+		return profileTemplate, p
+	}
+
+The values of data are escaped by html/template according to their context in
+the page. Clients accepting JSON or XML still receive the resource encoded in
+these formats, and so do clients accepting any media type.
+*/
+type HTMLRenderer interface {
+	Template() (tmpl *template.Template, data interface{})
+}
+
+// Marshalers maps media types to the functions that encode values in them, and
+// extends the list of types MarshalResource can negotiate with clients, in
+// resources and in errors alike.
+//
+//	rst.Marshalers["application/vnd.myapp+json"] = func(v interface{}) ([]byte, error) {
+//		return json.Marshal(map[string]interface{}{"data": v})
+//	}
+//
+// The built-in types always take precedence, which means a client accepting
+// application/* still receives JSON. Representable resources are asked for their
+// representation in the registered types as in the built-in ones. Marshalers
+// must be registered before the mux starts serving requests, and keys which
+// aren't valid media types are ignored.
+var Marshalers = map[string]func(v interface{}) ([]byte, error){}
+
+// offeredTypes returns the media types in which MarshalResource negotiates
+// the encoding of resource.
+func offeredTypes(resource interface{}) []string {
+	// Encoded JSON can't be represented in any other format.
+	if _, encoded := resource.(json.RawMessage); encoded {
+		return []string{"application/json", "text/javascript"}
+	}
+	_, html := resource.(HTMLRenderer)
+	if !html && len(Marshalers) == 0 {
+		return alternatives
+	}
+	offered := append([]string(nil), alternatives[:len(alternatives)-1]...)
+	if html {
+		offered = append(offered, "text/html")
+	}
+	offered = append(offered, registeredTypes()...)
+	return append(offered, "*/*")
+}
+
+// registeredTypes returns the sorted keys of Marshalers, except the ones which
+// aren't a type and a subtype separated by a slash.
+func registeredTypes() []string {
+	types := make([]string, 0, len(Marshalers))
+	for mediaType := range Marshalers {
+		if t := strings.SplitN(mediaType, "/", 2); len(t) != 2 || t[0] == "" || t[1] == "" {
+			continue
+		}
+		types = append(types, mediaType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Formats maps the names of the formats clients can ask for with the
+// FormatParam or the PathExtensions of a Mux to the media types they designate.
+var Formats = map[string]string{
+	"json": "application/json",
+	"xml":  "application/xml",
+	"txt":  "text/plain",
+}
+
+// NegotiateMultipleChoices makes MarshalResource return a *MultipleChoices
+// error, written in a 300 Multiple Choices response, instead of picking a
+// representation arbitrarily, when the Accept header
+// of the request only matches several representations of the resource with a
+// wildcard, like */* or application/*. By default, the first representation
+// available wins, which is also always the case for requests without an
+// Accept header.
+//
+// The alternatives are listed in the body of the response, and in Link headers
+// with the alternate relation type, like:
+//
+//	Link: </people/1?format=xml>; rel="alternate"; type="application/xml"
+//
+// The URLs of the links select the representation with the FormatParam or the
+// PathExtensions of the mux when they're enabled, and are the URL of the
+// request otherwise.
+var NegotiateMultipleChoices = false
+
+// ambiguousTypes returns the media types of available matched by the first
+// clause of accept matching any of them, if it's a wildcard. A clause naming
+// one of the types is never ambiguous.
+func ambiguousTypes(accept Accept, available []string) []string {
+	for _, clause := range accept {
+		var matched []string
+		for _, mediaType := range available {
+			t := strings.SplitN(mediaType, "/", 2)
+			if len(t) != 2 {
+				continue
+			}
+			switch {
+			case clause.Type == t[0] && clause.SubType == t[1]:
+				return nil
+			case clause.Type == "*" && clause.SubType == "*", clause.Type == t[0] && clause.SubType == "*":
+				matched = append(matched, mediaType)
+			}
+		}
+		if len(matched) > 0 {
+			return matched
+		}
+	}
+	return nil
+}
+
+// alternateURL returns the URL from which the representation of the resource
+// requested by r in mediaType can be fetched.
+func alternateURL(r *http.Request, mediaType string) string {
+	u := *r.URL
+	m := getMux(r)
+	if m == nil || (m.FormatParam == "" && !m.PathExtensions) {
+		return u.RequestURI()
+	}
+
+	var names []string
+	for name, t := range Formats {
+		if t == mediaType {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return u.RequestURI()
+	}
+	sort.Strings(names)
+
+	if m.FormatParam != "" {
+		query := u.Query()
+		query.Set(m.FormatParam, names[0])
+		u.RawQuery = query.Encode()
+	} else {
+		u.Path += "." + names[0]
+		u.RawPath = ""
+	}
+	return u.RequestURI()
+}
+
+// forceFormat replaces the Accept header of r with the media type of format,
+// or returns a 406 Not Acceptable error if format is not in Formats.
+func forceFormat(r *http.Request, format string) *Error {
+	mediaType, known := Formats[strings.ToLower(format)]
+	if !known {
+		var available []string
+		for _, mediaType := range Formats {
+			available = append(available, mediaType)
+		}
+		sort.Strings(available)
+		return NotAcceptable(available...)
+	}
+	r.Header.Set("Accept", mediaType)
+	return nil
+}
+
 // MarshalResource negotiates contentType based on the Accept header in r, and returns
 // the encoded version of resource as an array of bytes.
 //
 // MarshalResource can encode a resource in JSON and XML, as well as text using either
-// encoding.TextMarshaler or fmt.Stringer.
+// encoding.TextMarshaler or fmt.Stringer, in HTML if it implements HTMLRenderer,
+// and in the media types registered in Marshalers.
+//
+// If resource implements Representable, the value returned by its
+// Representation method for the negotiated content type is encoded instead.
+//
+// A json.RawMessage is written as is, and only in JSON.
 //
 // MarshalResource's XML marshaling will always return a valid XML document with a
 // header and a root object, which is not the case for the encoding/xml package.
@@ -64,7 +279,8 @@ var jsonNull = []byte("null")
 // MarshalResource can be called from Marshaler.MarshalRST on the same resource safely.
 func MarshalResource(resource interface{}, r *http.Request) (contentType string, encoded []byte, err error) {
 	accept := ParseAccept(r.Header.Get("Accept"))
-	if len(accept) == 0 {
+	implicit := len(accept) == 0
+	if implicit {
 		accept = append(accept, AcceptClause{
 			Type:    "*",
 			SubType: "*",
@@ -73,17 +289,50 @@ func MarshalResource(resource interface{}, r *http.Request) (contentType string,
 		})
 	}
 
-	switch accept.Negotiate(alternatives...) {
+	switch resource.(type) {
+	case *Error, *MultipleChoices:
+	default:
+		// A request without an Accept header gets the default
+		// representation.
+		if !NegotiateMultipleChoices || implicit {
+			break
+		}
+		if choices := ambiguousTypes(accept, availableTypes(resource)); len(choices) > 1 {
+			mc := &MultipleChoices{}
+			for _, mediaType := range choices {
+				mc.Alternatives = append(mc.Alternatives, &Alternative{mediaType, alternateURL(r, mediaType)})
+			}
+			return "", nil, mc
+		}
+	}
+
+	negotiated := accept.Negotiate(offeredTypes(resource)...)
+	switch negotiated {
 	case "application/json", "text/javascript":
-		b, err := json.Marshal(resource)
+		if resource, err = representation(resource, "application/json", r); err != nil {
+			return "", nil, err
+		}
+		if raw, encoded := resource.(json.RawMessage); encoded {
+			return "application/json; charset=utf-8", raw, nil
+		}
+		b, err := JSONMarshal(resource)
 		if bytes.Equal(b, jsonNull) {
 			b = []byte{}
 		}
 		return "application/json; charset=utf-8", b, err
 	case "application/xml", "text/xml":
+		if resource, err = representation(resource, "application/xml", r); err != nil {
+			return "", nil, err
+		}
 		b, err := marshalXML(resource)
 		return "application/xml; charset=utf-8", b, err
 	case "text/plain":
+		if resource, err = representation(resource, "text/plain", r); err != nil {
+			return "", nil, err
+		}
+		if s, isString := resource.(string); isString {
+			return "text/plain; charset=utf-8", []byte(s), nil
+		}
 		if marshaler, implemented := resource.(encoding.TextMarshaler); implemented {
 			b, err := marshaler.MarshalText()
 			return "text/plain; charset=utf-8", b, err
@@ -91,8 +340,92 @@ func MarshalResource(resource interface{}, r *http.Request) (contentType string,
 		if marshaler, implemented := resource.(fmt.Stringer); implemented {
 			return "text/plain; charset=utf-8", []byte(marshaler.String()), nil
 		}
+	case "text/html":
+		if renderer, implemented := resource.(HTMLRenderer); implemented {
+			tmpl, data := renderer.Template()
+			var buffer bytes.Buffer
+			if err := tmpl.Execute(&buffer, data); err != nil {
+				return "", nil, err
+			}
+			return "text/html; charset=utf-8", buffer.Bytes(), nil
+		}
+	default:
+		if marshal, registered := Marshalers[negotiated]; registered {
+			if resource, err = representation(resource, negotiated, r); err != nil {
+				return "", nil, err
+			}
+			b, err := marshal(resource)
+			return negotiated, b, err
+		}
+	}
+	return "", nil, NotAcceptable(availableTypes(resource)...)
+}
+
+// availableTypes returns the media types in which MarshalResource can encode
+// resource.
+func availableTypes(resource interface{}) []string {
+	if _, encoded := resource.(json.RawMessage); encoded {
+		return []string{"application/json"}
+	}
+	types := []string{"application/json", "application/xml"}
+	switch resource.(type) {
+	case string, Representable, encoding.TextMarshaler, fmt.Stringer:
+		types = append(types, "text/plain")
+	}
+	if _, implemented := resource.(HTMLRenderer); implemented {
+		types = append(types, "text/html")
+	}
+	return append(types, registeredTypes()...)
+}
+
+/*
+CanonicalJSON returns the JSON encoding of v in a canonical form, where the keys
+of every object are sorted, and where no insignificant whitespace is left.
+
+Unlike json.Marshal, the result doesn't depend on the order in which custom
+MarshalJSON methods write their keys, which makes it suitable for hashing.
+CanonicalJSON always uses encoding/json, regardless of JSONMarshal.
+*/
+func CanonicalJSON(v interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed interface{}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(&parsed); err != nil {
+		return nil, err
+	}
+	return json.Marshal(parsed)
+}
+
+/*
+ContentETag returns a quoted ETag derived from the canonical JSON encoding of
+v. The same logical value always yields the same ETag, regardless of the order
+of its keys.
+
+	func (p *Profile) ETag() string {
+		etag, _ := rst.ContentETag(p.Attributes)
+		return etag
+	}
+*/
+func ContentETag(v interface{}) (string, error) {
+	b, err := CanonicalJSON(v)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("\"%x\"", sha1.Sum(b)), nil
+}
+
+// representation returns the value to encode in contentType if resource
+// implements Representable, or resource itself if it doesn't.
+func representation(resource interface{}, contentType string, r *http.Request) (interface{}, error) {
+	if representable, implemented := resource.(Representable); implemented {
+		return representable.Representation(contentType, r)
 	}
-	return "", nil, NotAcceptable()
+	return resource, nil
 }
 
 // marshalXML adds an XML header and an envelope when needed to the result
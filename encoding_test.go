@@ -2,11 +2,17 @@ package rst
 
 import (
 	"bytes"
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"html/template"
 	"mime"
 	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 // Checking if marshalXML inserts a header and outputs a valid xml document
@@ -121,6 +127,11 @@ func TestMarshal(t *testing.T) {
 	if e, valid := err.(*Error); !valid || e.Code != http.StatusNotAcceptable {
 		t.Errorf("Expecting error with code %d. Got: %s", http.StatusNotAcceptable, err)
 	}
+	for _, available := range []string{"application/json", "application/xml"} {
+		if e, _ := err.(*Error); e == nil || !strings.Contains(e.Description, available) {
+			t.Errorf("Expecting %s in the description of the error. Got: %s", available, err)
+		}
+	}
 }
 
 // Testing whether marshalResource handles the Marshaler interface correctly.
@@ -142,3 +153,320 @@ func TestMarshalResource(t *testing.T) {
 		t.Fatal("Got:", string(b), "Wanted: hello, world!")
 	}
 }
+
+// Testing whether MarshalResource encodes the representation returned by a
+// Representable for the negotiated content type.
+type representablePerson person
+
+func (p *representablePerson) Representation(contentType string, r *http.Request) (interface{}, error) {
+	if contentType == "text/plain" {
+		return p.Firstname + " " + p.Lastname, nil
+	}
+	return map[string]string{"name": p.Firstname}, nil
+}
+
+func TestMarshalRepresentable(t *testing.T) {
+	p := &representablePerson{Firstname: "Ada", Lastname: "Lovelace"}
+
+	var test = func(accept, wantedType, wantedBody string) {
+		r, _ := newRequest(fmt.Sprintf("GET /index.html HTTP/1.1\nHost: www.example.com\nAccept: %s\n\n", accept))
+		ct, b, err := Marshal(p, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mime, _, _ := mime.ParseMediaType(ct); mime != wantedType {
+			t.Fatal("Content-Type. Got:", mime, "Wanted:", wantedType)
+		}
+		if string(b) != wantedBody {
+			t.Fatal("Body. Got:", string(b), "Wanted:", wantedBody)
+		}
+	}
+
+	test("application/json", "application/json", `{"name":"Ada"}`)
+	test("text/plain", "text/plain", "Ada Lovelace")
+}
+
+func TestAvailableStringTypes(t *testing.T) {
+	r, _ := http.NewRequest(Get, "/", nil)
+	r.Header.Set("Accept", "image/png")
+	_, _, err := MarshalResource("hello, world!", r)
+	if e, ok := err.(*Error); !ok || e.Code != http.StatusNotAcceptable || !strings.Contains(e.Description, "text/plain") {
+		t.Fatal("Got:", err, "Wanted: a 406 error listing text/plain")
+	}
+}
+
+// unorderedJSON writes its keys in the random order of map iteration.
+type unorderedJSON map[string]int
+
+func (u unorderedJSON) MarshalJSON() ([]byte, error) {
+	var parts []string
+	for key, value := range u {
+		parts = append(parts, fmt.Sprintf("%q:%d", key, value))
+	}
+	return []byte("{" + strings.Join(parts, ",") + "}"), nil
+}
+
+func TestContentETag(t *testing.T) {
+	resource := map[string]interface{}{
+		"name":   "Ada",
+		"scores": unorderedJSON{"a": 1, "b": 2, "c": 3, "d": 4, "e": 5, "f": 6},
+		"meta":   map[string]int{"x": 1, "y": 2, "z": 3},
+	}
+
+	expected, err := ContentETag(resource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(expected, `"`) || !strings.HasSuffix(expected, `"`) {
+		t.Fatal("The ETag is not quoted. Got:", expected)
+	}
+	for i := 0; i < 100; i++ {
+		etag, err := ContentETag(resource)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if etag != expected {
+			t.Fatal("Run", i, "Got:", etag, "Wanted:", expected)
+		}
+	}
+
+	b, err := CanonicalJSON(unorderedJSON{"b": 2, "a": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wanted := `{"a":1,"b":2}`; string(b) != wanted {
+		t.Fatal("Got:", string(b), "Wanted:", wanted)
+	}
+}
+
+func TestJSONMarshal(t *testing.T) {
+	defer func(original func(interface{}) ([]byte, error)) { JSONMarshal = original }(JSONMarshal)
+	JSONMarshal = func(v interface{}) ([]byte, error) {
+		return []byte(`"custom"`), nil
+	}
+
+	r, _ := http.NewRequest(Get, "/", nil)
+	r.Header.Set("Accept", "application/json")
+	_, b, err := Marshal(testPeople[len(testPeople)-1], r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `"custom"` {
+		t.Fatal("JSONMarshal was not used. Got:", string(b), "Wanted:", `"custom"`)
+	}
+}
+
+func TestFormatParam(t *testing.T) {
+	mux := NewMux()
+	mux.FormatParam = "format"
+	mux.Handle("/people/{id}", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return testPeople[len(testPeople)-1], nil
+	}))
+
+	var test = func(query string, expected int, contentType string) {
+		r, _ := http.NewRequest(Get, "/people/1"+query, nil)
+		r.Header.Set("Accept", "application/json")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal(query, "status code. Got:", w.Code, "Wanted:", expected)
+		}
+		if got := w.Header().Get("Content-Type"); contentType != "" && !strings.HasPrefix(got, contentType) {
+			t.Fatal(query, "Content-Type. Got:", got, "Wanted:", contentType)
+		}
+	}
+
+	test("?format=xml", http.StatusOK, "application/xml")
+	test("?format=XML", http.StatusOK, "application/xml")
+	test("?format=json", http.StatusOK, "application/json")
+	test("", http.StatusOK, "application/json")
+	test("?format=yaml", http.StatusNotAcceptable, "")
+
+	mux.FormatParam = ""
+	test("?format=xml", http.StatusOK, "application/json")
+}
+
+func TestMuxContentType(t *testing.T) {
+	mux := NewMux()
+	mux.ContentType = "application/json"
+	mux.FormatParam = "format"
+	mux.Handle("/people/{id}", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return testPeople[len(testPeople)-1], nil
+	}))
+
+	var test = func(path, accept string, expected int) {
+		r, _ := http.NewRequest(Get, path, nil)
+		r.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal(path, accept, "status code. Got:", w.Code, "Wanted:", expected)
+		}
+		if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, "application/json") {
+			t.Fatal(path, accept, "Content-Type. Got:", got, "Wanted: application/json")
+		}
+	}
+
+	test("/people/1", "application/xml", http.StatusOK)
+	test("/people/1", "text/html", http.StatusOK)
+	test("/people/1?format=xml", "", http.StatusOK)
+	test("/employers/1", "application/xml", http.StatusNotFound)
+}
+
+// negotiated is a resource available in JSON and XML only.
+type negotiated struct {
+	ID string `json:"id" xml:"id"`
+}
+
+func TestNegotiateMultipleChoices(t *testing.T) {
+	defer func(enabled bool) { NegotiateMultipleChoices = enabled }(NegotiateMultipleChoices)
+	NegotiateMultipleChoices = true
+
+	mux := NewMux()
+	mux.FormatParam = "format"
+	mux.Handle("/people/{id}", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return WithCache(&negotiated{vars.Get("id")}, "", time.Time{}, 0), nil
+	}))
+
+	var test = func(accept string, expected int, links []string) {
+		r, _ := http.NewRequest(Get, "/people/1", nil)
+		r.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal(accept, "status code. Got:", w.Code, "Wanted:", expected)
+		}
+		if got := w.Header()["Link"]; !reflect.DeepEqual(got, links) {
+			t.Fatal(accept, "Link. Got:", got, "Wanted:", links)
+		}
+		for _, mediaType := range []string{"application/json", "application/xml"} {
+			if expected == http.StatusMultipleChoices && !strings.Contains(w.Body.String(), mediaType) {
+				t.Fatal(accept, "body should list", mediaType, "Got:", w.Body.String())
+			}
+		}
+	}
+
+	alternates := []string{
+		`</people/1?format=json>; rel="alternate"; type="application/json"`,
+		`</people/1?format=xml>; rel="alternate"; type="application/xml"`,
+	}
+	test("*/*", http.StatusMultipleChoices, alternates)
+	test("application/*", http.StatusMultipleChoices, alternates)
+	test("application/xml, */*;q=0.5", http.StatusOK, nil)
+	test("application/json", http.StatusOK, nil)
+	test("text/*, application/json;q=0.5", http.StatusOK, nil)
+	test("", http.StatusOK, nil)
+
+	NegotiateMultipleChoices = false
+	test("*/*", http.StatusOK, nil)
+}
+
+func TestMarshalers(t *testing.T) {
+	const vendorType = "application/vnd.myapp+json"
+	Marshalers[vendorType] = func(v interface{}) ([]byte, error) {
+		return json.Marshal(map[string]interface{}{"data": v})
+	}
+	defer delete(Marshalers, vendorType)
+
+	resource := testPeople[len(testPeople)-1]
+	mux := NewMux()
+	mux.Handle("/people/{id}", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		if vars.Get("id") != resource.ID {
+			return nil, NotFound()
+		}
+		return resource, nil
+	}))
+
+	var test = func(path, accept, contentType string, code int) map[string]json.RawMessage {
+		r, _ := http.NewRequest(Get, path, nil)
+		r.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != code {
+			t.Fatal(path, accept, "status code. Got:", w.Code, "Wanted:", code)
+		}
+		if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, contentType) {
+			t.Fatal(path, accept, "Content-Type. Got:", got, "Wanted:", contentType)
+		}
+		var body map[string]json.RawMessage
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatal(path, accept, err)
+		}
+		return body
+	}
+
+	if body := test("/people/"+resource.ID, vendorType, vendorType, http.StatusOK); body["data"] == nil {
+		t.Fatal("resource body. Got:", body, "Wanted: a data key")
+	}
+	body := test("/people/unknown", vendorType, vendorType, http.StatusNotFound)
+	var e Error
+	if err := json.Unmarshal(body["data"], &e); err != nil || e.Reason != http.StatusText(http.StatusNotFound) {
+		t.Fatal("error body. Got:", string(body["data"]), "Wanted: a 404 error")
+	}
+
+	// Built-in types take precedence.
+	if body := test("/people/unknown", "application/*", "application/json", http.StatusNotFound); body["data"] != nil {
+		t.Fatal("error body. Got:", body, "Wanted: plain JSON")
+	}
+}
+
+func TestMalformedMarshalers(t *testing.T) {
+	defer func(enabled bool) { NegotiateMultipleChoices = enabled }(NegotiateMultipleChoices)
+	NegotiateMultipleChoices = true
+	Marshalers["vnd.myapp"] = json.Marshal
+	defer delete(Marshalers, "vnd.myapp")
+
+	mux := NewMux()
+	mux.Handle("/people/{id}", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return testPeople[0], nil
+	}))
+
+	for _, accept := range []string{"*/*", "application/json", "vnd.myapp"} {
+		r, _ := http.NewRequest(Get, "/people/1", nil)
+		r.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code == http.StatusInternalServerError {
+			t.Fatal(accept, "status code. Got:", w.Code)
+		}
+		if strings.Contains(w.Body.String(), "vnd.myapp") {
+			t.Fatal(accept, "malformed media type in the response:", w.Body.String())
+		}
+	}
+}
+
+var testProfileTemplate = template.Must(template.New("profile").Parse(`<h1>{{.Firstname}} {{.Lastname}}</h1>`))
+
+// htmlPerson is a person rendered in HTML with a template.
+type htmlPerson struct {
+	*person
+}
+
+func (p *htmlPerson) Template() (*template.Template, interface{}) {
+	return testProfileTemplate, p.person
+}
+
+func TestHTMLRenderer(t *testing.T) {
+	p := *testPeople[len(testPeople)-1]
+	p.Firstname = "<script>alert(1)</script>"
+	resource := &htmlPerson{&p}
+
+	var test = func(accept, contentType, body string) {
+		r, _ := http.NewRequest(Get, "/people/1", nil)
+		r.Header.Set("Accept", accept)
+		ct, b, err := MarshalResource(resource, r)
+		if err != nil {
+			t.Fatal(accept, err)
+		}
+		if !strings.HasPrefix(ct, contentType) {
+			t.Fatal(accept, "Content-Type. Got:", ct, "Wanted:", contentType)
+		}
+		if !strings.Contains(string(b), body) {
+			t.Fatal(accept, "body. Got:", string(b), "Wanted:", body)
+		}
+	}
+
+	test("text/html,application/xhtml+xml,*/*;q=0.8", "text/html", "<h1>&lt;script&gt;alert(1)&lt;/script&gt; "+p.Lastname+"</h1>")
+	test("application/json", "application/json", `"lastname":"`+p.Lastname+`"`)
+	test("*/*", "application/json", `"lastname":"`+p.Lastname+`"`)
+}
@@ -2,21 +2,33 @@ package rst
 
 import (
 	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"html/template"
 	"io/ioutil"
 	"log"
+	"math"
 	"net/http"
 	"runtime"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mohamedattahri/rst/internal/assets"
 )
 
 // ErrorHandler is a wrapper that allows any Go error to implement the
-// http.Handler interface.
+// http.Handler interface. Redirections returned by Redirect, and the
+// MultipleChoices returned by MarshalResource, are written as they are.
 func ErrorHandler(err error) http.Handler {
-	if e, ok := err.(*Error); ok {
+	switch e := err.(type) {
+	case *Error:
+		return e
+	case *Redirection:
+		return e
+	case *MultipleChoices:
 		return e
 	}
 	// panic will be intercepted in the main mux handler, and will write a
@@ -71,12 +83,18 @@ func NotFound() *Error {
 
 // MethodNotAllowed is returned when the method specified in a request is
 // not allowed by the resource identified by the request-URI.
+//
+// allowed is the full set of methods allowed by the resource, including custom
+// ones. They are listed in the Allow header and in the description of the
+// error in a stable order: the methods supported by rst first, in the order of
+// AllowedMethods and followed by OPTIONS, then the others in alphabetical
+// order. Methods are upper cased, and duplicates are removed.
 func MethodNotAllowed(forbidden string, allowed []string) *Error {
-	methods := strings.Join(allowed, ", ")
+	methods := strings.Join(sortMethods(allowed), ", ")
 	err := NewError(
 		http.StatusMethodNotAllowed,
 		fmt.Sprintf("%s method is not allowed for this resource", forbidden),
-		fmt.Sprintf("This ressource only allows the following methods: %s.", methods),
+		fmt.Sprintf("This resource only allows the following methods: %s.", methods),
 	)
 	err.Header.Set("Allow", methods)
 	return err
@@ -86,15 +104,43 @@ func MethodNotAllowed(forbidden string, allowed []string) *Error {
 // is only capable of generating response entities which have content
 // characteristics not acceptable according to the accept headers sent in the
 // request.
-func NotAcceptable() *Error {
+//
+// The media types listed in available are the ones in which the resource could
+// have been represented, and are named in the description of the error.
+func NotAcceptable(available ...string) *Error {
+	description := "Resource is only capable of generating content not acceptable according to the accept headers sent in the request."
+	if len(available) > 0 {
+		description += fmt.Sprintf(" Available representations: %s.", strings.Join(available, ", "))
+	}
 	err := NewError(
 		http.StatusNotAcceptable,
 		http.StatusText(http.StatusNotAcceptable),
-		"Resource is only capable of generating content not acceptable according to the accept headers sent in the request.",
+		description,
 	)
 	return err
 }
 
+/*
+Gone is returned when the resource identified by the Request-URI used to exist,
+but has been permanently removed. Unlike NotFound, it tells clients that the
+resource won't be available again, and that links to it can be deleted.
+
+	func (ep *PeopleEP) Get(vars rst.RouteVars, r *http.Request) (rst.Resource, error) {
+		p, err := database.FindPerson(vars.Get("id"))
+		if err == database.ErrDeleted {
+			return nil, rst.Gone()
+		}
+		...
+	}
+*/
+func Gone() *Error {
+	return NewError(
+		http.StatusGone,
+		http.StatusText(http.StatusGone),
+		"The resource at the requested URI has been permanently removed.",
+	)
+}
+
 // Conflict is returned when a request can't be processed due to a conflict with
 // the current state of the resource.
 func Conflict() *Error {
@@ -117,6 +163,17 @@ func PreconditionFailed() *Error {
 	return err
 }
 
+// PreconditionRequired is returned when a request modifying a resource is not
+// conditional, and could overwrite changes made since the client retrieved the
+// resource. See Mux.RequirePreconditions.
+func PreconditionRequired() *Error {
+	return NewError(
+		http.StatusPreconditionRequired,
+		http.StatusText(http.StatusPreconditionRequired),
+		"This request must be conditional. Please retry it with an If-Match header set to the ETag of the resource.",
+	)
+}
+
 // UnsupportedMediaType is returned when the entity in the request is in a format
 // not support by the server. The supported media MIME type strings can be passed
 // to improve the description of the error description.
@@ -133,6 +190,34 @@ func UnsupportedMediaType(mimes ...string) *Error {
 	return err
 }
 
+// FieldError is a violation of the schema of a request body, located by the
+// JSON pointer of the offending value (RFC 6901).
+type FieldError struct {
+	Pointer string `json:"pointer" xml:"pointer,attr"`
+	Message string `json:"message" xml:",chardata"`
+}
+
+// UnprocessableEntity is returned when the body of a request is well-formed,
+// but contains invalid values. The violations listed in errors are included
+// in the response.
+func UnprocessableEntity(errors ...*FieldError) *Error {
+	var pointers []string
+	for _, e := range errors {
+		pointers = append(pointers, e.Pointer)
+	}
+	description := "The entity in the request contains invalid values."
+	if len(pointers) > 0 {
+		description += fmt.Sprintf(" Invalid fields: %s.", strings.Join(pointers, ", "))
+	}
+	err := NewError(
+		http.StatusUnprocessableEntity,
+		"Entity inside request could not be processed",
+		description,
+	)
+	err.Errors = errors
+	return err
+}
+
 // RequestedRangeNotSatisfiable is returned when the range in the Range header
 // does not overlap the current extent of the requested resource.
 func RequestedRangeNotSatisfiable(cr *ContentRange) *Error {
@@ -146,6 +231,78 @@ func RequestedRangeNotSatisfiable(cr *ContentRange) *Error {
 	return err
 }
 
+// RequestEntityTooLarge is returned when the body of the request is larger
+// than the server is willing to process.
+func RequestEntityTooLarge() *Error {
+	return NewError(
+		http.StatusRequestEntityTooLarge,
+		http.StatusText(http.StatusRequestEntityTooLarge),
+		"The body of the request is larger than the server is willing to process.",
+	)
+}
+
+// URITooLong is returned when the URI of the request is longer than the server
+// is willing to interpret.
+func URITooLong() *Error {
+	return NewError(
+		http.StatusRequestURITooLong,
+		http.StatusText(http.StatusRequestURITooLong),
+		"The URI of the request is longer than the server is willing to interpret.",
+	)
+}
+
+// RequestHeaderFieldsTooLarge is returned when the header fields of the
+// request are too large to be processed by the server.
+func RequestHeaderFieldsTooLarge() *Error {
+	return NewError(
+		http.StatusRequestHeaderFieldsTooLarge,
+		http.StatusText(http.StatusRequestHeaderFieldsTooLarge),
+		"The header fields of the request are too large to be processed.",
+	)
+}
+
+// ServiceUnavailable is returned when the server is temporarily unable to
+// handle the request, because of maintenance or overloading for example.
+//
+// When retryAfter is positive, it's written in seconds in the Retry-After
+// header of the response to let clients know when to try again. Use RetryAt
+// to write a date instead, like the end of a scheduled maintenance window.
+func ServiceUnavailable(retryAfter time.Duration) *Error {
+	err := NewError(
+		http.StatusServiceUnavailable,
+		http.StatusText(http.StatusServiceUnavailable),
+		"The server is temporarily unable to handle the request.",
+	)
+	if retryAfter > 0 {
+		seconds := int64(math.Ceil(retryAfter.Seconds()))
+		err.Header.Set("Retry-After", strconv.FormatInt(seconds, 10))
+	}
+	return err
+}
+
+/*
+RetryAt sets the Retry-After header of e to t, in the HTTP-date form, and
+returns e. It lets clients know the date at which they can try again, which is
+more accurate than a delay when it's known in advance.
+
+	if maintenance.InProgress() {
+		return nil, rst.ServiceUnavailable(0).RetryAt(maintenance.End)
+	}
+
+The header is removed when t is the zero time.
+*/
+func (e *Error) RetryAt(t time.Time) *Error {
+	if e.Header == nil {
+		e.Header = make(http.Header)
+	}
+	if t.IsZero() {
+		e.Header.Del("Retry-After")
+	} else {
+		e.Header.Set("Retry-After", t.UTC().Format(rfc1123))
+	}
+	return e
+}
+
 type stackRecord struct {
 	Filename string `json:"file" xml:"File"`
 	Line     int    `json:"line" xml:"Line"`
@@ -189,12 +346,51 @@ func InternalServerError(reason, description string, captureStack bool) *Error {
 //
 // Header can be used to specify headers that will be written in the HTTP
 // response generated from this error.
+//
+// ID is a correlation ID set on internal server errors served by a Mux, which
+// can be given to support to find the error in the logs.
+//
+// Stack and Causes are only set on internal server errors served by a Mux in
+// debug mode. Causes lists the messages of the errors wrapped by the one which
+// caused the failure, as returned by errors.Unwrap.
+//
+// Errors lists the invalid fields of the body of the request, and is set by
+// UnprocessableEntity.
+//
+// Err is the underlying error wrapped with Wrap, which can be retrieved with
+// errors.Is and errors.As. It's never written in responses, but is logged along
+// with internal server errors.
 type Error struct {
 	Code        int            `json:"-" xml:"-"`
 	Header      http.Header    `json:"-" xml:"-"`
+	ID          string         `json:"id,omitempty" xml:"ID,omitempty"`
 	Reason      string         `json:"message" xml:"Message"`
 	Description string         `json:"description,omitempty" xml:"Description,omitempty"`
 	Stack       []*stackRecord `json:"stack,omitempty" xml:"Stack,omitempty"`
+	Causes      []string       `json:"causes,omitempty" xml:"Causes>Cause,omitempty"`
+	Errors      []*FieldError  `json:"errors,omitempty" xml:"Errors>Error,omitempty"`
+	Err         error          `json:"-" xml:"-"`
+}
+
+/*
+Wrap sets err as the cause of e, and returns e.
+
+	p, err := database.FindPerson(vars.Get("id"))
+	if err != nil {
+		return nil, rst.InternalServerError("", "", false).Wrap(err)
+	}
+
+Clients see e only, while the middlewares and loggers of the service can
+inspect err with errors.Is and errors.As.
+*/
+func (e *Error) Wrap(err error) *Error {
+	e.Err = err
+	return e
+}
+
+// Unwrap returns the error wrapped in e, or nil.
+func (e *Error) Unwrap() error {
+	return e.Err
 }
 
 func (e *Error) Error() string {
@@ -204,6 +400,10 @@ func (e *Error) Error() string {
 func (e *Error) String() string {
 	s := fmt.Sprintf("%d (%s) - %s", e.Code, http.StatusText(e.Code), e.Reason)
 
+	if e.ID != "" {
+		s += fmt.Sprintf("\nID: %s", e.ID)
+	}
+
 	if e.Description != "" {
 		s += fmt.Sprintf("\n%s", e.Description)
 	}
@@ -249,22 +449,27 @@ func (e *Error) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		b = []byte(e.String())
 	}
 
+	// Remove headers which might have been set by a previous assumption of
+	// success.
+	for _, key := range []string{
+		"Last-Modified", "ETag", "Expires", "Content-Length", "Cache-Control",
+		"Content-Location", "X-Total-Count", "Cache-Key", CacheTagHeader,
+	} {
+		if key != "" {
+			w.Header().Del(key)
+		}
+	}
+
 	for key, values := range e.Header {
 		for _, value := range values {
 			w.Header().Add(key, value)
 		}
 	}
 
-	// Remove headers which might have been set by a previous assumption of
-	// success.
-	w.Header().Del("Last-Modified")
-	w.Header().Del("ETag")
-	w.Header().Del("Expires")
-
 	w.Header().Set("Content-Type", ct)
 	w.Header().Add("Vary", "Accept")
 	if e.Code != http.StatusNotFound && e.Code != http.StatusGone {
-		w.Header().Add("Cache-Control", "no-cache, no-store, must-revalidate")
+		w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
 	}
 	w.WriteHeader(e.Code)
 	w.Write(b)
@@ -284,6 +489,28 @@ func NewError(code int, reason, description string) *Error {
 	}
 }
 
+// errorCauses returns the messages of the errors wrapped by v, if v is an
+// error.
+func errorCauses(v interface{}) (causes []string) {
+	err, ok := v.(error)
+	if !ok {
+		return nil
+	}
+	for err = errors.Unwrap(err); err != nil; err = errors.Unwrap(err) {
+		causes = append(causes, err.Error())
+	}
+	return causes
+}
+
+// newErrorID returns a random correlation ID for an error.
+func newErrorID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
 var errorTemplate *template.Template
 
 func init() {
@@ -2,11 +2,16 @@ package rst
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 // TestInternalServerErrorStack tests whether the stack is only visible when
@@ -57,3 +62,326 @@ func TestInternalServerErrorStackDisplay(t *testing.T) {
 		t.Fatalf("provoked panic with Debug=False did not log message correctly: %s", buffer.String())
 	}
 }
+
+func TestOversizedRequestErrors(t *testing.T) {
+	var test = func(err *Error, code int) {
+		if err.Code != code {
+			t.Fatal("Got:", err.Code, "Wanted:", code)
+		}
+
+		r, _ := newRequest("GET /index.html HTTP/1.1\nHost: www.example.com\nAccept: application/json\n\n")
+		ct, b, e := Marshal(err, r)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if !strings.HasPrefix(ct, "application/json") {
+			t.Fatal("Content-Type. Got:", ct, "Wanted: application/json")
+		}
+		if !strings.Contains(string(b), err.Reason) {
+			t.Fatal("expected body to contain the reason of the error")
+		}
+	}
+
+	test(URITooLong(), http.StatusRequestURITooLong)
+	test(RequestHeaderFieldsTooLarge(), http.StatusRequestHeaderFieldsTooLarge)
+}
+
+func TestInternalServerErrorID(t *testing.T) {
+	logger := testMux.Logger
+	defer func() {
+		testMux.Logger = logger
+	}()
+	buffer := new(bytes.Buffer)
+	testMux.Logger = log.New(buffer, "", log.Ltime)
+
+	var test = func(rr *requestResponse) {
+		if err := rr.TestStatusCode(http.StatusInternalServerError); err != nil {
+			t.Fatal(err)
+		}
+		id := rr.resp.Header.Get("X-Error-ID")
+		if id == "" {
+			t.Fatal("expected response to have an X-Error-ID header")
+		}
+
+		var body struct {
+			ID string `json:"id"`
+		}
+		if err := json.NewDecoder(rr.resp.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		rr.resp.Body.Close()
+		if body.ID != id {
+			t.Fatal("ID in body. Got:", body.ID, "Wanted:", id)
+		}
+	}
+
+	header := make(http.Header)
+	header.Set("Accept", "application/json")
+
+	// Panic
+	test(newRequestResponse(Get, testServerAddr+"/panic", header, nil))
+
+	// Returned error, which must be logged with its ID.
+	mux := NewMux()
+	mux.Logger = log.New(buffer, "", log.Ltime)
+	mux.Handle("/error", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return nil, InternalServerError("database unavailable", "", false)
+	}))
+	r, _ := http.NewRequest(Get, "/error", nil)
+	r.Header = header
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	test(&requestResponse{req: r, resp: w.Result()})
+	if id := w.Header().Get("X-Error-ID"); !strings.Contains(buffer.String(), id) {
+		t.Fatalf("expected logs to contain error ID %s: %s", id, buffer.String())
+	}
+}
+
+// Errors declared in variables are returned by many requests, which must each
+// get their own ID and log entry.
+func TestSharedInternalServerError(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	shared := InternalServerError("database unavailable", "", false)
+	mux := NewMux()
+	mux.Logger = log.New(buffer, "", log.Ltime)
+	mux.Handle("/error", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return nil, shared
+	}))
+
+	var ids []string
+	for i := 0; i < 2; i++ {
+		r, _ := http.NewRequest(Get, "/error", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		id := w.Header().Get("X-Error-ID")
+		if id == "" || !strings.Contains(buffer.String(), id) {
+			t.Fatal("Response", i, "ID", id, "is not in the logs:", buffer.String())
+		}
+		ids = append(ids, id)
+	}
+	if ids[0] == ids[1] {
+		t.Fatal("Both responses have the same error ID:", ids[0])
+	}
+	if shared.ID != "" || shared.Header.Get("X-Error-ID") != "" {
+		t.Fatal("The shared error was modified. Got:", shared.ID, shared.Header)
+	}
+}
+
+func TestServiceUnavailable(t *testing.T) {
+	var test = func(retryAfter time.Duration, expected string) {
+		err := ServiceUnavailable(retryAfter)
+		if err.Code != http.StatusServiceUnavailable {
+			t.Fatal("Got:", err.Code, "Wanted:", http.StatusServiceUnavailable)
+		}
+
+		r, _ := newRequest("GET /index.html HTTP/1.1\nHost: www.example.com\nAccept: application/json\n\n")
+		w := httptest.NewRecorder()
+		ErrorHandler(err).ServeHTTP(w, r)
+		if got := w.Header().Get("Retry-After"); got != expected {
+			t.Fatal("Retry-After. Got:", got, "Wanted:", expected)
+		}
+	}
+
+	test(2*time.Minute, "120")
+	test(1500*time.Millisecond, "2")
+	test(0, "")
+}
+
+func TestRetryAt(t *testing.T) {
+	var test = func(err *Error, expected string) {
+		r, _ := newRequest("GET /index.html HTTP/1.1\nHost: www.example.com\nAccept: application/json\n\n")
+		w := httptest.NewRecorder()
+		ErrorHandler(err).ServeHTTP(w, r)
+		if got := w.Header().Get("Retry-After"); got != expected {
+			t.Fatal("Retry-After. Got:", got, "Wanted:", expected)
+		}
+	}
+
+	paris := time.FixedZone("Paris", 2*60*60)
+	end := time.Date(2015, 10, 21, 9, 28, 0, 0, paris)
+	test(ServiceUnavailable(0).RetryAt(end), "Wed, 21 Oct 2015 07:28:00 GMT")
+	test(ServiceUnavailable(time.Minute).RetryAt(end), "Wed, 21 Oct 2015 07:28:00 GMT")
+	test(ServiceUnavailable(time.Minute), "60")
+	test(ServiceUnavailable(time.Minute).RetryAt(time.Time{}), "")
+}
+
+func TestNotAcceptableAvailableTypes(t *testing.T) {
+	header := make(http.Header)
+	header.Set("Accept", "image/png")
+	rr := newRequestResponse(Get, testServerAddr+"/people/"+testPeople[len(testPeople)-1].ID, header, nil)
+	if err := rr.TestStatusCode(http.StatusNotAcceptable); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadAll(rr.resp.Body)
+	for _, available := range []string{"application/json", "application/xml"} {
+		if !strings.Contains(string(b), available) {
+			t.Fatal("Body. Got:", string(b), "Wanted:", available)
+		}
+	}
+}
+
+func TestMethodNotAllowedAllow(t *testing.T) {
+	const expected = "HEAD, GET, POST, OPTIONS, LINK, PURGE"
+	err := MethodNotAllowed(Delete, []string{"purge", Get, Head, "LINK", Get, Post, Options})
+	if got := err.Header.Get("Allow"); got != expected {
+		t.Fatal("Allow header. Got:", got, "Wanted:", expected)
+	}
+
+	for _, accept := range []string{"application/json", "application/xml", "text/html"} {
+		r, _ := http.NewRequest(Delete, "/", nil)
+		r.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		err.ServeHTTP(w, r)
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Fatal(accept, "status code. Got:", w.Code, "Wanted:", http.StatusMethodNotAllowed)
+		}
+		if got := w.Header().Get("Allow"); got != expected {
+			t.Fatal(accept, "Allow header. Got:", got, "Wanted:", expected)
+		}
+		if body := w.Body.String(); !strings.Contains(body, "This resource only allows the following methods: "+expected+".") {
+			t.Fatal(accept, "body should list the allowed methods. Got:", body)
+		}
+	}
+}
+
+func TestGone(t *testing.T) {
+	mux := NewMux()
+	mux.Handle("/people/{id}", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		if vars.Get("id") == "deleted" {
+			return nil, Gone()
+		}
+		return nil, NotFound()
+	}))
+
+	var test = func(path string, expected int) *httptest.ResponseRecorder {
+		r, _ := http.NewRequest(Get, path, nil)
+		r.Header.Set("Accept", "application/json")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal(path, "status code. Got:", w.Code, "Wanted:", expected)
+		}
+		return w
+	}
+
+	gone, notFound := test("/people/deleted", http.StatusGone), test("/people/unknown", http.StatusNotFound)
+	if ct := gone.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Fatal("Content-Type. Got:", ct, "Wanted: application/json")
+	}
+	var body Error
+	if err := json.Unmarshal(gone.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Reason != http.StatusText(http.StatusGone) {
+		t.Fatal("Reason. Got:", body.Reason, "Wanted:", http.StatusText(http.StatusGone))
+	}
+	if gone.Body.String() == notFound.Body.String() {
+		t.Fatal("Gone and NotFound should be distinct. Got:", gone.Body.String())
+	}
+}
+
+func TestInternalServerErrorDebugBody(t *testing.T) {
+	cause := errors.New("connection refused")
+	mux := NewMux()
+	mux.Logger = log.New(ioutil.Discard, "", log.Ltime)
+	mux.Handle("/people", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return nil, fmt.Errorf("query failed: %w", cause)
+	}))
+
+	var test = func(debug bool) {
+		mux.Debug = debug
+		r, _ := http.NewRequest(Get, "/people", nil)
+		r.Header.Set("Accept", "application/json")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != http.StatusInternalServerError {
+			t.Fatal("status code. Got:", w.Code, "Wanted:", http.StatusInternalServerError)
+		}
+
+		var body Error
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if got := len(body.Stack) > 0; got != debug {
+			t.Fatal("Debug", debug, "contains stack. Got:", got, "Wanted:", debug)
+		}
+		if debug && (len(body.Causes) != 1 || body.Causes[0] != cause.Error()) {
+			t.Fatal("causes. Got:", body.Causes, "Wanted:", []string{cause.Error()})
+		}
+		if !debug && (len(body.Causes) > 0 || strings.Contains(w.Body.String(), cause.Error())) {
+			t.Fatal("causes should not be visible. Got:", w.Body.String())
+		}
+	}
+
+	test(true)
+	test(false)
+}
+
+// testDatabaseError is the cause of an internal server error.
+type testDatabaseError struct {
+	table string
+}
+
+func (e *testDatabaseError) Error() string {
+	return "database error in table " + e.table
+}
+
+func TestErrorWrap(t *testing.T) {
+	cause := &testDatabaseError{"people"}
+	e := InternalServerError("", "", false).Wrap(cause)
+
+	var target *testDatabaseError
+	if !errors.As(e, &target) || target != cause {
+		t.Fatal("errors.As. Got:", target, "Wanted:", cause)
+	}
+	if !errors.As(fmt.Errorf("handler: %w", e), &target) {
+		t.Fatal("errors.As should find the cause through the chain")
+	}
+	if !errors.Is(e, cause) {
+		t.Fatal("errors.Is should match the cause")
+	}
+
+	// The cause is logged, but never sent to the client.
+	buffer := new(bytes.Buffer)
+	mux := NewMux()
+	mux.Logger = log.New(buffer, "", log.Ltime)
+	mux.Handle("/people", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return nil, InternalServerError("", "", false).Wrap(cause)
+	}))
+	for _, accept := range []string{"application/json", "application/xml", "text/html"} {
+		r, _ := http.NewRequest(Get, "/people", nil)
+		r.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if strings.Contains(w.Body.String(), cause.Error()) {
+			t.Fatal(accept, "the cause should not be in the body. Got:", w.Body.String())
+		}
+	}
+	if !strings.Contains(buffer.String(), cause.Error()) {
+		t.Fatal("the cause should be logged. Got:", buffer.String())
+	}
+}
+
+func TestErrorResetsResourceHeaders(t *testing.T) {
+	r, _ := http.NewRequest(Get, "/people", nil)
+	w := httptest.NewRecorder()
+	for key, value := range map[string]string{
+		"Cache-Control":    "max-age=60",
+		"Content-Location": "/people/1",
+		"X-Total-Count":    "42",
+		"Cache-Key":        "/people",
+		CacheTagHeader:     "people",
+	} {
+		w.Header().Set(key, value)
+	}
+	ServiceUnavailable(0).ServeHTTP(w, r)
+
+	if got := w.Header()["Cache-Control"]; len(got) != 1 || got[0] != "no-cache, no-store, must-revalidate" {
+		t.Fatal("Cache-Control. Got:", got, "Wanted: no-cache, no-store, must-revalidate")
+	}
+	for _, key := range []string{"Content-Location", "X-Total-Count", "Cache-Key", CacheTagHeader} {
+		if got := w.Header().Get(key); got != "" {
+			t.Fatal(key, "should not be set. Got:", got)
+		}
+	}
+}
@@ -1,7 +1,14 @@
 package rst
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -25,15 +32,18 @@ when you need to write chunked responses, or if you wish to add specific headers
 such a Content-Disposition, etc.
 */
 type Resource interface {
-	ETag() string            // ETag identifying the current version of the resource.
-	LastModified() time.Time // Date and time of the last modification of the resource.
-	TTL() time.Duration      // Time to live, or caching duration of the resource.
+	ETag() string            // ETag identifying the current version of the resource, or an empty string.
+	LastModified() time.Time // Date and time of the last modification of the resource, or the zero time.
+	TTL() time.Duration      // Time to live, or caching duration of the resource. Zero means no-cache, negative no-store.
 }
 
 /*
 ValidateConditions returns true if the If-Unmodified-Since or the If-Match headers of
 r are not matching with the current version of resource.
 
+EvaluatePreconditions should be preferred, as it evaluates all the conditional
+headers in the order defined by RFC 7232.
+
 	func (ep *endpoint) Patch(vars RouteVars, r *http.Request) (Resource, error) {
 		resource := db.Lookup(vars.Get("id"))
 		if ValidateConditions(resource, r) {
@@ -55,12 +65,105 @@ func ValidateConditions(resource Resource, r *http.Request) bool {
 	return false
 }
 
+/*
+EvaluatePreconditions evaluates the conditional headers of r against the
+current version of resource, in the order defined by section 6 of RFC 7232.
+If-Match is evaluated first, or If-Unmodified-Since when If-Match is absent.
+If-None-Match is evaluated next, or If-Modified-Since when If-None-Match is
+absent.
+
+pass is true when the request can be processed, in which case status is
+http.StatusOK. Otherwise, status is either http.StatusPreconditionFailed, or
+http.StatusNotModified for GET and HEAD requests whose If-None-Match or
+If-Modified-Since condition failed.
+
+	func (ep *endpoint) Put(vars rst.RouteVars, r *http.Request) (rst.Resource, error) {
+		resource := db.Lookup(vars.Get("id"))
+		if _, pass := rst.EvaluatePreconditions(resource, r); !pass {
+			return nil, rst.PreconditionFailed()
+		}
+
+		// update the resource safely from here
+	}
+
+If-Match is evaluated with the strong comparison function, and If-None-Match
+with the weak one. Conditions relying on a validator resource doesn't have,
+like a date for a resource without a last modification date, are ignored.
+*/
+func EvaluatePreconditions(resource Resource, r *http.Request) (status int, pass bool) {
+	safe := strings.ToUpper(r.Method) == Get || strings.ToUpper(r.Method) == Head
+	modified := resource.LastModified().UTC().Truncate(time.Second)
+
+	if raw := r.Header.Get("If-Match"); raw != "" {
+		if !matchETags(raw, resource.ETag(), false) {
+			return http.StatusPreconditionFailed, false
+		}
+	} else if d, err := time.Parse(rfc1123, r.Header.Get("If-Unmodified-Since")); err == nil && !resource.LastModified().IsZero() {
+		if modified.After(d) {
+			return http.StatusPreconditionFailed, false
+		}
+	}
+
+	if raw := r.Header.Get("If-None-Match"); raw != "" {
+		if matchETags(raw, resource.ETag(), true) {
+			if safe {
+				return http.StatusNotModified, false
+			}
+			return http.StatusPreconditionFailed, false
+		}
+	} else if d, err := time.Parse(rfc1123, r.Header.Get("If-Modified-Since")); err == nil && safe && !resource.LastModified().IsZero() {
+		if !modified.After(d) {
+			return http.StatusNotModified, false
+		}
+	}
+
+	return http.StatusOK, true
+}
+
+// matchETags returns true if etag is in raw, the comma-separated list of an
+// If-Match or If-None-Match header. The wildcard matches any ETag.
+func matchETags(raw, etag string, weak bool) bool {
+	for _, tag := range strings.Split(raw, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+		if etag == "" {
+			continue
+		}
+		if weak {
+			if strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		} else if tag == etag && !strings.HasPrefix(tag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
 /*
 Ranger is implemented by resources that support partial responses.
 
 Range will only be called if the request contains a valid Range header.
 Otherwise, it will be processed as a normal Get request.
 
+When a resource implements both Ranger and http.Handler, Range is called for
+valid range requests and the returned resource is written in a 206 Partial
+Content response. If it implements http.Handler too, its ServeHTTP method is
+only expected to write the part it represents. Requests without a valid Range
+header are served in full by the ServeHTTP method of the original resource.
+
+Partial responses are never compressed. Ranges always designate units of the
+representation as it is written in the payload.
+
+Partial responses are written with the ETag, the last modification date, and
+the TTL of the original resource, whatever the ones of the returned part.
+
+The endpoint serving the resource can implement RangeAdvertiser to advertise
+the supported units in responses to OPTIONS requests, and RangeSpanLimiter to
+override MaxRangeSpan.
+
 	type Doc []byte
 	// assuming Doc implements rst.Resource interface
 
@@ -88,40 +191,198 @@ type Ranger interface {
 	Count() uint64
 
 	// Range is used to return the part of the resource that is indicated by the
-	// passed range.
+	// passed range. ErrRangeUnavailable can be returned to serve the full
+	// resource instead.
 	Range(*Range) (*ContentRange, Resource, error)
 }
 
+/*
+ResponseTransformer, when set, is called by rst with every resource about to be
+marshaled in a response. The value it returns is what will be encoded in the
+payload instead of the resource itself.
+
+It can be used to wrap all responses in a consistent envelope:
+
+	rst.ResponseTransformer = func(resource rst.Resource, r *http.Request) interface{} {
+		return map[string]interface{}{
+			"data": resource,
+			"meta": map[string]string{"etag": resource.ETag()},
+		}
+	}
+
+Resources implementing http.Handler write their own payload and are not
+transformed. Resources implementing Marshaler, like the ones returned by
+NewEnvelope, NewCollection, or Text, are marshaled first: the transformer
+receives a resource whose JSON encoding is the one returned by MarshalRST, and
+payloads encoded in another format, like the ones of Blob, are written without
+being transformed. A nil value disables the feature.
+*/
+var ResponseTransformer func(Resource, *http.Request) interface{}
+
+// requirePreconditions returns a 428 Precondition Required error if the mux
+// serving r requires conditional writes, and r isn't conditional. PUT requests
+// creating a resource with If-None-Match: * are conditional too.
+func requirePreconditions(r *http.Request) *Error {
+	if m := getMux(r); m == nil || !m.RequirePreconditions {
+		return nil
+	}
+	if r.Header.Get("If-Match") != "" || r.Header.Get("If-Unmodified-Since") != "" {
+		return nil
+	}
+	if strings.ToUpper(r.Method) == Put && r.Header.Get("If-None-Match") == "*" {
+		return nil
+	}
+	return PreconditionRequired()
+}
+
+// ErrRangeUnavailable can be returned by Ranger.Range to indicate that range
+// requests can't be served at the moment. The full resource is then written in
+// the response, with the Accept-Ranges header set to none.
+var ErrRangeUnavailable = errors.New("rst: range requests are temporarily unavailable")
+
+// ErrAlreadyExists can be returned by Putter.Put, as is or wrapped, when the
+// resource it was asked to create has been created by another request in the
+// meantime. See the Putter interface for details.
+var ErrAlreadyExists = errors.New("rst: resource already exists")
+
+// writeError writes e in the response with the ErrorRenderer of the mux serving
+// r if set, or with ErrorHandler otherwise.
 func writeError(e error, w http.ResponseWriter, r *http.Request) {
+	if err, ok := e.(*Error); ok {
+		if m := getMux(r); m != nil {
+			m.writeError(err, w, r)
+			return
+		}
+	}
 	ErrorHandler(e).ServeHTTP(w, r)
 }
 
+// writeCacheHeaders sets the headers controlling the cacheability of resource.
+//
+// A positive TTL sets the Expires header. A TTL of zero means the resource can
+// be stored, but must be revalidated (Cache-Control: no-cache), and a negative
+// TTL means it must not be stored at all (Cache-Control: no-store).
+func writeCacheHeaders(resource Resource, w http.ResponseWriter) {
+	switch ttl := resource.TTL(); {
+	case ttl > 0:
+		w.Header().Set("Expires", time.Now().Add(ttl).UTC().Format(rfc1123))
+	case ttl == 0:
+		w.Header().Set("Cache-Control", "no-cache")
+	default:
+		w.Header().Set("Cache-Control", "no-store")
+	}
+}
+
 func writeResource(resource Resource, w http.ResponseWriter, r *http.Request) {
-	// Time-based conditional retrieval
-	if t, err := time.Parse(rfc1123, r.Header.Get("If-Modified-Since")); err == nil {
-		if t.Sub(resource.LastModified()).Seconds() >= 0 {
-			w.WriteHeader(http.StatusNotModified)
-			return
+	if d, delegated := resource.(*delegatedResource); delegated {
+		// The handler encodes its own response, whatever its Content-Encoding.
+		if rw, ok := w.(*responseWriter); ok {
+			rw.encoded = true
+		}
+		d.handler.ServeHTTP(w, r)
+		return
+	}
+
+	// Streamers are closed on every path, including the responses written
+	// without their content, like 304 Not Modified.
+	defer func() {
+		if _, streamer := resource.(Streamer); streamer {
+			if closer, implemented := resource.(io.Closer); implemented {
+				closer.Close()
+			}
 		}
+	}()
+
+	// The validators of a range are the ones of the whole resource.
+	var whole Resource
+	if rp, wrapped := resource.(*rangePart); wrapped {
+		resource, whole = rp.Resource, rp.whole
 	}
 
-	// ETag-based conditional retrieval
-	for _, t := range strings.Split(r.Header.Get("If-None-Match"), ";") {
-		if t == resource.ETag() {
-			w.WriteHeader(http.StatusNotModified)
+	// Custom status code
+	code := 0
+	if coder, implemented := resource.(StatusCoder); implemented {
+		code = coder.StatusCode()
+		if code < 200 || code > 299 {
+			err := fmt.Errorf("rst: %d is not a valid success status code", code)
+			writeError(InternalServerError("", "", false).Wrap(err), w, r)
 			return
 		}
 	}
+	if sr, wrapped := resource.(*statusResource); wrapped {
+		if sr.Resource == nil {
+			w.WriteHeader(code)
+			return
+		}
+		resource = sr.Resource
+	}
+	if p, wrapped := resource.(*partialResource); wrapped {
+		w.Header().Set("Content-Range", p.contentRange.String())
+		resource = p.Resource
+	}
+	if whole == nil {
+		whole = resource
+	}
+
+	// Conditional retrieval. The headers of requests with other methods
+	// were meant for the resource before it was changed.
+	if method := strings.ToUpper(r.Method); (method == Get || method == Head) && !checkPreconditions(whole, w, r) {
+		return
+	}
 
-	// Headers
+	// Headers. Validators the resource can't provide are omitted.
 	w.Header().Add("Vary", "Accept")
-	w.Header().Set("Last-Modified", resource.LastModified().UTC().Format(rfc1123))
-	w.Header().Set("ETag", resource.ETag())
-	w.Header().Set("Expires", time.Now().Add(resource.TTL()).UTC().Format(rfc1123))
+	if key := cacheKey(resource, r); key != "" {
+		w.Header().Set("Cache-Key", key)
+	}
+	if tagger, implemented := resource.(Tagger); implemented {
+		if tags := tagger.CacheTags(); len(tags) > 0 {
+			w.Header().Set(CacheTagHeader, strings.Join(tags, CacheTagSeparator))
+		}
+	}
+	if modified := whole.LastModified(); !modified.IsZero() {
+		w.Header().Set("Last-Modified", modified.UTC().Format(rfc1123))
+	}
+	if etag := whole.ETag(); etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+	writeCacheHeaders(whole, w)
+	if locator, implemented := resource.(ContentLocator); implemented {
+		if location := locator.ContentLocation(); location != "" {
+			w.Header().Set("Content-Location", absoluteURL(r, location))
+		}
+	}
+	if c, isCollection := resource.(*Collection); isCollection {
+		w.Header().Set("X-Total-Count", strconv.FormatUint(c.total, 10))
+	}
+
+	// Partial responses are never compressed, so that the offsets of their
+	// Content-Range header always apply to the bytes of the payload.
+	partial := w.Header().Get("Content-Range") != ""
+
+	if streamer, implemented := resource.(Streamer); implemented {
+		writeStream(streamer, code, w, r)
+		return
+	}
 
 	// If resource implements http.Handler, let it write in the ResponseWriter
 	// on its own.
 	if handler, implemented := resource.(http.Handler); implemented {
+		if format := acceptedCompression(r); HandlerCompression && format != "" && !partial && compressionAllowed(resource) {
+			cw := newCompressWriter(w, format)
+			defer cw.close()
+			w = cw
+		}
+		if partial {
+			w = &partialWriter{ResponseWriter: w}
+		}
+		if trailer, implemented := resource.(Trailer); implemented {
+			// The length of a response with trailers can't be known in advance.
+			w.Header().Del("Content-Length")
+			for _, name := range trailer.Trailer() {
+				w.Header().Add("Trailer", http.CanonicalHeaderKey(name))
+			}
+		}
 		handler.ServeHTTP(w, r)
 		return
 	}
@@ -130,41 +391,340 @@ func writeResource(resource Resource, w http.ResponseWriter, r *http.Request) {
 		contentType string
 		b           []byte
 		err         error
+		cache       = responseCache(r)
+		cacheKey    string
 	)
-	contentType, b, err = Marshal(resource, r)
+	if cache != nil && resource.ETag() != "" && !partial {
+		cacheKey = representationKey(resource, r)
+		contentType, b, err = cache.marshal(cacheKey, func() (string, []byte, error) {
+			return encodeResource(resource, r)
+		})
+	} else {
+		contentType, b, err = encodeResource(resource, r)
+	}
 	if err != nil {
 		writeError(err, w, r)
 		return
 	}
 	w.Header().Set("Content-Type", contentType)
 
-	if compression := getCompressionFormat(b, r); compression != "" {
-		w.Header().Set("Content-Encoding", compression)
+	// Payloads large enough to be compressed vary with Accept-Encoding, even
+	// when the client asked for them not to be.
+	sized := true
+	if compressible(b) && !partial && compressionAllowed(resource) {
 		w.Header().Add("Vary", "Accept-Encoding")
+		if compression := getCompressionFormat(b, r); compression != "" {
+			w.Header().Set("Content-Encoding", compression)
+			// Cached payloads are compressed once, and written as is.
+			if rw, ok := w.(*responseWriter); ok && cacheKey != "" {
+				b = cache.compress(cacheKey, b, compression)
+				rw.encoded = true
+			} else {
+				// Compressed on the fly, in a length unknown until written.
+				sized = false
+			}
+		}
 	}
 
-	if strings.ToUpper(r.Method) == Post {
-		w.WriteHeader(http.StatusCreated)
-		w.Write(b)
-		return
+	status := http.StatusOK
+	switch {
+	case strings.ToUpper(r.Method) == Post:
+		status = http.StatusCreated
+	case len(b) == 0:
+		status = http.StatusNoContent
+	case partial:
+		status = http.StatusPartialContent
+	}
+	if code != 0 {
+		status = code
+	}
+	// Responses to HEAD requests advertise the length of the payload a GET
+	// request would have received.
+	if sized && len(b) > 0 {
+		w.Header().Set("Content-Length", strconv.Itoa(len(b)))
 	}
+	w.WriteHeader(status)
 
-	if len(b) == 0 {
-		w.WriteHeader(http.StatusNoContent)
+	if len(b) == 0 || strings.ToUpper(r.Method) == Head {
 		return
 	}
 
-	if w.Header().Get("Content-Range") != "" {
-		w.WriteHeader(http.StatusPartialContent)
-	} else {
-		w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+// encodeResource returns the payload of the response to r for resource, once
+// transformed by ResponseTransformer, marshaled, and filtered by
+// FieldSelection.
+func encodeResource(resource Resource, r *http.Request) (string, []byte, error) {
+	contentType, b, err := transformResource(resource, r)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if FieldSelection && strings.HasPrefix(contentType, "application/json") {
+		if fields := parseListParam(r, fieldsParam); len(fields) > 0 {
+			if b, err = selectFields(b, fields, StrictFieldSelection); err != nil {
+				return "", nil, err
+			}
+		}
+	}
+	return contentType, b, nil
+}
+
+// transformResource marshals resource, once transformed by ResponseTransformer
+// if set.
+func transformResource(resource Resource, r *http.Request) (string, []byte, error) {
+	if ResponseTransformer == nil {
+		return Marshal(resource, r)
+	}
+	if _, implemented := resource.(Marshaler); !implemented {
+		return Marshal(ResponseTransformer(resource, r), r)
 	}
 
+	contentType, b, err := Marshal(resource, r)
+	if err != nil || !strings.HasPrefix(contentType, "application/json") {
+		return contentType, b, err
+	}
+	return Marshal(ResponseTransformer(&marshaledResource{resource, b}, r), r)
+}
+
+// marshaledResource is a resource encoded in JSON by its MarshalRST method,
+// passed to ResponseTransformer.
+type marshaledResource struct {
+	Resource
+	b []byte
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (m *marshaledResource) MarshalJSON() ([]byte, error) {
+	if len(m.b) == 0 {
+		return []byte("null"), nil
+	}
+	return m.b, nil
+}
+
+/*
+ContentLocator is implemented by resources whose representation is identified
+by a URL other than the one of the request, like the resource returned in the
+response to a PUT request sent to /people/me:
+
+	func (p *Person) ContentLocation() string {
+		return "/people/" + p.ID
+	}
+
+The URL is written in the Content-Location header of the response, resolved
+against the URL of the request. Unlike the Location header of responses to
+POST requests, it tells clients where the payload they received comes from,
+which allows them to cache it under that URL.
+*/
+type ContentLocator interface {
+	ContentLocation() string
+}
+
+// absoluteURL resolves ref against the URL of r, whose scheme and host are the
+// ones of the ExternalURL of the mux serving r when it's set.
+func absoluteURL(r *http.Request, ref string) string {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	base := &url.URL{Scheme: "http", Host: r.Host, Path: r.URL.Path}
+	if r.TLS != nil {
+		base.Scheme = "https"
+	}
+	if m := getMux(r); m != nil && m.ExternalURL != "" {
+		if external, err := url.Parse(m.ExternalURL); err == nil && external.Host != "" {
+			base.Scheme, base.Host = external.Scheme, external.Host
+		}
+	}
+	return base.ResolveReference(u).String()
+}
+
+/*
+Streamer is implemented by resources of unknown length, like live feeds or
+exports, which are written progressively in the response instead of being
+marshaled at once.
+
+	func (f *Feed) ContentType() string {
+		return "application/x-ndjson"
+	}
+
+	func (f *Feed) Stream(w io.Writer) error {
+		for event := range f.events {
+			if _, err := w.Write(event.JSON()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+The response is sent with chunked transfer encoding, and every write is flushed
+to the client. It's compressed if the client accepts it, regardless of
+CompressionThreshold and HandlerCompression, unless it's the part of a resource
+returned by Ranger.Range, since partial responses are never compressed.
+
+If Stream fails before anything has been written, the error is written in the
+response. Otherwise, the response ends where the stream stopped.
+
+Streamers implementing io.Closer are closed once the response has been
+written, including when Stream isn't called, like in responses to HEAD
+requests or to conditional requests answered with 304 Not Modified or 412
+Precondition Failed.
+*/
+type Streamer interface {
+	ContentType() string      // Media type of the stream.
+	Stream(w io.Writer) error // Writes the content of the resource in w.
+}
+
+// SizedStreamer is implemented by streamers which know the length of their
+// content in advance, like files. The length is sent in the Content-Length
+// header of uncompressed responses, which lets clients report the progress of
+// the download. Stream must then write exactly Size bytes.
+type SizedStreamer interface {
+	Streamer
+	Size() int64
+}
+
+// writeStream writes the content of streamer in the response, with the status
+// code if it's not 0. Parts of resources, written with a Content-Range header,
+// are never compressed.
+func writeStream(streamer Streamer, code int, w http.ResponseWriter, r *http.Request) {
+	partial := w.Header().Get("Content-Range") != ""
+	if code == 0 {
+		code = http.StatusOK
+		if partial {
+			code = http.StatusPartialContent
+		}
+	}
+	w.Header().Set("Content-Type", streamer.ContentType())
+	w.Header().Del("Content-Length")
+	// The length of a compressed stream can't be known in advance.
+	var format string
+	if compressionAllowed(streamer) && !partial {
+		format = acceptedCompression(r)
+	}
+	if sized, implemented := streamer.(SizedStreamer); implemented && format == "" {
+		w.Header().Set("Content-Length", strconv.FormatInt(sized.Size(), 10))
+	}
 	if strings.ToUpper(r.Method) == Head {
+		w.WriteHeader(code)
 		return
 	}
 
-	w.Write(b)
+	if compressionAllowed(streamer) && !partial {
+		w.Header().Add("Vary", "Accept-Encoding")
+	}
+	fw := &flushWriter{w: w, code: code}
+	if format != "" {
+		cw := newCompressWriter(w, format)
+		defer cw.close()
+		fw.w = cw
+	}
+
+	if err := streamer.Stream(fw); err != nil && !fw.wroteHeader {
+		w.Header().Del("Content-Length")
+		writeError(err, w, r)
+		return
+	}
+	if !fw.wroteHeader {
+		fw.w.WriteHeader(code)
+	}
+}
+
+// flushWriter writes the header of the response before the first chunk of
+// data, and flushes every chunk to the client.
+type flushWriter struct {
+	w           http.ResponseWriter
+	code        int
+	wroteHeader bool
+}
+
+func (fw *flushWriter) Write(b []byte) (int, error) {
+	if !fw.wroteHeader {
+		fw.wroteHeader = true
+		fw.w.WriteHeader(fw.code)
+	}
+	n, err := fw.w.Write(b)
+	if f, ok := fw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return n, err
+}
+
+/*
+Trailer is implemented by resources implementing http.Handler that send HTTP
+trailers after the body of the response, like a checksum or a count only known
+once all the data has been written.
+
+The names returned by Trailer are declared in the Trailer header before
+ServeHTTP is called, and their values must be set in the header of the
+ResponseWriter once the body has been written.
+
+	func (e *Export) Trailer() []string {
+		return []string{"X-Row-Count"}
+	}
+
+	func (e *Export) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+		count := 0
+		for row := range e.rows {
+			w.Write(row)
+			count++
+		}
+		w.Header().Set("X-Row-Count", strconv.Itoa(count))
+	}
+*/
+type Trailer interface {
+	// Names of the trailer headers that will be set after the body.
+	Trailer() []string
+}
+
+// partialWriter is used when a partial resource implementing http.Handler
+// writes its own response, and makes sure it's sent with status code 206
+// Partial Content.
+type partialWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *partialWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	if code == http.StatusOK {
+		code = http.StatusPartialContent
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *partialWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusPartialContent)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements the http.Flusher interface.
+func (w *partialWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+/*
+StatusCoder is implemented by resources wishing to be written with a specific
+status code in a successful response, like 202 Accepted for a POST request
+starting an asynchronous job, or 203 Non-Authoritative Information for a GET
+request answered with data from a third party.
+
+The status code must be in the 2xx range, or the response is an error with
+status code 500 Internal Server Error.
+
+The status code is ignored by resources implementing http.Handler, which write
+their own.
+*/
+type StatusCoder interface {
+	StatusCode() int
 }
 
 /*
@@ -185,7 +745,8 @@ Getter is implemented by endpoints allowing the GET and HEAD method.
 */
 type Getter interface {
 	// Returns the resource or an error. A nil resource pointer will generate
-	// a response with status code 204 No Content.
+	// a response with status code 204 No Content. The response has status code
+	// 200 OK, unless the resource implements StatusCoder (see WithStatus).
 	Get(RouteVars, *http.Request) (Resource, error)
 }
 
@@ -193,6 +754,22 @@ type Getter interface {
 type getFunc func(RouteVars, *http.Request) (Resource, error)
 
 func (f getFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.serve(MaxRangeSpan, w, r)
+}
+
+// spanLimitedGetFunc is a getFunc serving ranges of at most span units.
+type spanLimitedGetFunc struct {
+	get  getFunc
+	span uint64
+}
+
+func (f *spanLimitedGetFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.get.serve(f.span, w, r)
+}
+
+// serve responds to r with the resource returned by f, and clamps the
+// requested ranges to span units.
+func (f getFunc) serve(span uint64, w http.ResponseWriter, r *http.Request) {
 	resource, err := f(getVars(r), r)
 	if err != nil {
 		writeError(err, w, r)
@@ -211,8 +788,15 @@ func (f getFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	w.Header().Set("Accept-Ranges", strings.Join(ranger.Units(), ", "))
 
+	// Preconditions are evaluated against the whole resource before the
+	// Range header, which is ignored when they don't pass.
+	if !checkPreconditions(resource, w, r) {
+		return
+	}
+
 	// Check if request contains a valid Range header, and check whether it's
-	// a valid range.
+	// a valid range. Headers listing more than MaxRanges ranges are rejected
+	// by ParseRange.
 	rg, err := ParseRange(r.Header.Get("Range"))
 	if err != nil || rg.validate(ranger) != nil {
 		writeResource(resource, w, r)
@@ -222,20 +806,22 @@ func (f getFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// If-Range can either contain an ETag, or a date.
 	// If the precondition fails, the Range header is ignored and the full
 	// resource is returned.
-	if raw := r.Header.Get("If-Range"); raw != "" {
-		date, _ := time.Parse(rfc1123, raw)
-		if !date.Equal(resource.LastModified()) && raw != resource.ETag() {
-			writeResource(resource, w, r)
-			return
-		}
+	if raw := r.Header.Get("If-Range"); raw != "" && !matchIfRange(raw, resource) {
+		writeResource(resource, w, r)
+		return
 	}
 
-	if err := rg.adjust(ranger); err != nil {
+	if err := rg.adjust(ranger, span); err != nil {
 		writeError(err, w, r)
 		return
 	}
 
 	cr, partial, err := ranger.Range(rg)
+	if err == ErrRangeUnavailable {
+		w.Header().Set("Accept-Ranges", "none")
+		writeResource(resource, w, r)
+		return
+	}
 	if err != nil {
 		writeError(err, w, r)
 		return
@@ -245,7 +831,50 @@ func (f getFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if cr.From != 0 || cr.To != (cr.Total-1) {
 		w.Header().Set("Content-Range", cr.String())
 	}
-	writeResource(partial, w, r)
+	writeResource(&rangePart{partial, resource}, w, r)
+}
+
+// rangePart is a part returned by Ranger.Range, written with the ETag, the
+// last modification date, and the TTL of the whole resource, so that a part
+// can always be validated with If-Range and combined with the other parts.
+type rangePart struct {
+	Resource
+	whole Resource
+}
+
+// checkPreconditions evaluates the conditional headers of r against resource,
+// and writes a 304 Not Modified response or a 412 Precondition Failed error if
+// they don't pass, in which case false is returned.
+func checkPreconditions(resource Resource, w http.ResponseWriter, r *http.Request) bool {
+	status, pass := EvaluatePreconditions(resource, r)
+	if pass {
+		return true
+	}
+	if status == http.StatusNotModified {
+		w.WriteHeader(status)
+	} else {
+		writeError(PreconditionFailed(), w, r)
+	}
+	return false
+}
+
+// matchIfRange returns true if the value of an If-Range header matches the
+// current version of resource.
+//
+// Dates are compared to the last modification date of resource with a
+// precision of one second, which is the precision of HTTP dates. ETags are
+// compared with the strong comparison function, which means weak ETags never
+// match.
+func matchIfRange(raw string, resource Resource) bool {
+	if date, err := time.Parse(rfc1123, raw); err == nil {
+		modified := resource.LastModified()
+		return !modified.IsZero() && date.Equal(modified.UTC().Truncate(time.Second))
+	}
+	etag := resource.ETag()
+	if etag == "" || strings.HasPrefix(raw, "W/") || strings.HasPrefix(etag, "W/") {
+		return false
+	}
+	return raw == etag
 }
 
 /*
@@ -257,10 +886,6 @@ Patcher is implemented by endpoints allowing the PATCH method.
 			return nil, rst.NotFound()
 		}
 
-		if r.Header.Get("Content-Type") != "application/www-form-urlencoded" {
-			return nil, rst.UnsupportedMediaType("application/www-form-urlencoded")
-		}
-
 		// Detect any writing conflicts
 		if rst.ValidateConditions(resource, r) {
 			return nil, rst.PreconditionFailed()
@@ -269,6 +894,16 @@ Patcher is implemented by endpoints allowing the PATCH method.
 		// Read r.Body, apply changes to resource, then return it
 		return resource, nil
 	}
+
+Large resources can be answered with only the part that was modified, in a 206
+Partial Content response built with Partial.
+
+The media type of the body can be checked before Patch is called by declaring
+it with the Consumer interface.
+
+	func (ep *endpoint) Consumes() []string {
+		return []string{"application/merge-patch+json"}
+	}
 */
 type Patcher interface {
 	// Returns the patched resource or an error.
@@ -279,13 +914,17 @@ type Patcher interface {
 type patchFunc func(RouteVars, *http.Request) (Resource, error)
 
 func (f patchFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := requirePreconditions(r); err != nil {
+		writeError(err, w, r)
+		return
+	}
 	resource, err := f(getVars(r), r)
 	if err != nil {
 		writeError(err, w, r)
 		return
 	}
-	w.WriteHeader(http.StatusOK)
 	if resource == nil {
+		w.WriteHeader(http.StatusOK)
 		return
 	}
 	writeResource(resource, w, r)
@@ -308,6 +947,30 @@ Putter is implemented by endpoints allowing the PUT method.
 		// Read r.Body, apply changes to resource, then return it
 		return resource, nil
 	}
+
+Clients creating a resource with PUT can send an If-None-Match: * header to
+make sure they don't overwrite one created concurrently. Since only the storage
+can tell which of two concurrent requests won, the loser is reported by
+returning ErrAlreadyExists, which is written as a 412 Precondition Failed error,
+or as a 409 Conflict error for requests without If-None-Match:
+
+	func (ep *endpoint) Put(vars rst.RouteVars, r *http.Request) (rst.Resource, error) {
+		resource, err := NewResourceFromRequest(r)
+		if err != nil {
+			return nil, err
+		}
+		if r.Header.Get("If-None-Match") == "*" {
+			if !database.Insert(vars.Get("id"), resource) {
+				return nil, rst.ErrAlreadyExists
+			}
+			return resource, nil
+		}
+		database.Upsert(vars.Get("id"), resource)
+		return resource, nil
+	}
+
+As with Patcher, the media types accepted in the body of the request can be
+declared with the Consumer interface.
 */
 type Putter interface {
 	// Returns the modified resource or an error.
@@ -318,13 +981,24 @@ type Putter interface {
 type putFunc func(RouteVars, *http.Request) (Resource, error)
 
 func (f putFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := requirePreconditions(r); err != nil {
+		writeError(err, w, r)
+		return
+	}
 	resource, err := f(getVars(r), r)
+	if errors.Is(err, ErrAlreadyExists) {
+		if r.Header.Get("If-None-Match") != "" {
+			err = PreconditionFailed()
+		} else {
+			err = Conflict()
+		}
+	}
 	if err != nil {
 		writeError(err, w, r)
 		return
 	}
-	w.WriteHeader(http.StatusOK)
 	if resource == nil {
+		w.WriteHeader(http.StatusOK)
 		return
 	}
 	writeResource(resource, w, r)
@@ -344,10 +1018,162 @@ Poster is implemented by endpoints allowing the POST method.
 */
 type Poster interface {
 	// Returns the resource newly created and the URI where it can be located, or
-	// an error.
+	// an error. The response has status code 201 Created, unless the resource
+	// implements StatusCoder (see WithStatus).
 	Post(RouteVars, *http.Request) (resource Resource, location string, err error)
 }
 
+/*
+Producer is implemented by endpoints producing a restricted set of media types,
+like an export only available in CSV:
+
+	func (ep *ExportEP) Produces() []string {
+		return []string{"text/csv"}
+	}
+
+Requests accepting none of them are rejected with a 406 Not Acceptable error
+before the endpoint is called. The Accept header of the others is replaced with
+the media type negotiated among them, in which the resources returned by the
+endpoint are encoded. The media types are also advertised in the responses to
+OPTIONS requests.
+*/
+type Producer interface {
+	Produces() []string
+}
+
+// producedTypes returns the media types declared by endpoint, or nil if it
+// doesn't implement Producer.
+func producedTypes(endpoint Endpoint) []string {
+	if producer, implemented := endpoint.(Producer); implemented {
+		return producer.Produces()
+	}
+	return nil
+}
+
+// advertisedTypes returns the media types in which the resources of endpoint
+// can be encoded: the ones it produces, or the ones MarshalResource negotiates
+// over except */*.
+func advertisedTypes(endpoint Endpoint) []string {
+	if types := producedTypes(endpoint); len(types) > 0 {
+		return append([]string(nil), types...)
+	}
+	types := append([]string(nil), alternatives[:len(alternatives)-1]...)
+	return append(types, registeredTypes()...)
+}
+
+// restrictAccept replaces the Accept header of r with the media type it
+// negotiates among the ones produced by endpoint, or returns a 406 Not
+// Acceptable error if none of them is acceptable.
+func restrictAccept(endpoint Endpoint, r *http.Request) *Error {
+	types := producedTypes(endpoint)
+	if len(types) == 0 {
+		return nil
+	}
+	contentType := types[0]
+	if accept := ParseAccept(r.Header.Get("Accept")); len(accept) > 0 {
+		if contentType = accept.Negotiate(types...); contentType == "" {
+			return NotAcceptable(types...)
+		}
+	}
+	r.Header.Set("Accept", contentType)
+	return nil
+}
+
+/*
+Consumer is implemented by endpoints declaring the media types they accept in
+the body of requests.
+
+	func (ep *PeopleEP) Consumes() []string {
+		return []string{"application/json"}
+	}
+
+POST, PUT, and PATCH requests with a body in another media type are rejected
+with a 415 Unsupported Media Type error before the endpoint is called. A media
+type can be declared with a wildcard subtype, like text/*.
+
+The media types accepted by a Poster are advertised in the Accept-Post header
+of responses to OPTIONS requests, and the ones accepted by a Patcher in their
+Accept-Patch header.
+*/
+type Consumer interface {
+	Consumes() []string
+}
+
+/*
+RangeAdvertiser is implemented by endpoints serving resources which implement
+Ranger, to declare the units of these resources before any of them is
+requested.
+
+	func (ep *PeopleEP) RangeUnits() []string {
+		return []string{"items"}
+	}
+
+The units are advertised in the Accept-Ranges header of responses to OPTIONS
+requests, which lets clients know that they can request parts of the resource
+without issuing a GET request first. An empty list is advertised as none.
+*/
+type RangeAdvertiser interface {
+	RangeUnits() []string
+}
+
+/*
+RangeSpanLimiter is implemented by endpoints serving resources which implement
+Ranger, to override MaxRangeSpan for these resources.
+
+	func (ep *VideosEP) MaxRangeSpan() uint64 {
+		return 1 << 24 // 16MB per request.
+	}
+
+A value of 0 disables the limit for the endpoint.
+*/
+type RangeSpanLimiter interface {
+	MaxRangeSpan() uint64
+}
+
+// consumedTypes returns the media types declared by endpoint, or nil if it
+// doesn't implement Consumer.
+func consumedTypes(endpoint Endpoint) []string {
+	if consumer, implemented := endpoint.(Consumer); implemented {
+		return consumer.Consumes()
+	}
+	return nil
+}
+
+// checkContentType returns a 415 Unsupported Media Type error if the body of
+// r is in a media type endpoint doesn't consume. Requests without a body are
+// always accepted.
+func checkContentType(endpoint Endpoint, r *http.Request) *Error {
+	method := strings.ToUpper(r.Method)
+	if method != Post && method != Put && method != Patch {
+		return nil
+	}
+	types := consumedTypes(endpoint)
+	if len(types) == 0 {
+		return nil
+	}
+
+	ct := r.Header.Get("Content-Type")
+	if ct == "" && r.ContentLength == 0 {
+		return nil
+	}
+	if mediaType, _, err := mime.ParseMediaType(ct); err == nil {
+		for _, t := range types {
+			if strings.EqualFold(t, mediaType) || (strings.HasSuffix(t, "/*") && strings.HasPrefix(mediaType, strings.ToLower(t[:len(t)-1]))) {
+				return nil
+			}
+		}
+	}
+
+	err := UnsupportedMediaType(types...)
+	switch method {
+	case Post:
+		err.Header.Set("Accept-Post", strings.Join(types, ", "))
+	case Patch:
+		err.Header.Set("Accept-Patch", strings.Join(types, ", "))
+	}
+	return err
+}
+
 // postFunc is an adapter to use ordinary functions as HTTP POST handlers.
 type postFunc func(RouteVars, *http.Request) (Resource, string, error)
 
@@ -379,6 +1205,10 @@ type Deleter interface {
 type deleteFunc func(RouteVars, *http.Request) error
 
 func (f deleteFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := requirePreconditions(r); err != nil {
+		writeError(err, w, r)
+		return
+	}
 	if err := f(getVars(r), r); err != nil {
 		writeError(err, w, r)
 		return
@@ -395,7 +1225,29 @@ func optionsHandler(endpoint Endpoint) http.Handler {
 		}
 
 		w.Header().Set("Allow", strings.Join(AllowedMethods(endpoint), ", "))
-		w.Header().Set("Content-Type", strings.Join(alternatives, ";"))
+		if types := producedTypes(endpoint); len(types) > 0 {
+			w.Header().Set("Content-Type", strings.Join(types, ";"))
+		} else {
+			w.Header().Set("Content-Type", strings.Join(offeredTypes(nil), ";"))
+		}
+		if types := consumedTypes(endpoint); len(types) > 0 {
+			if _, implemented := endpoint.(Poster); implemented {
+				w.Header().Set("Accept-Post", strings.Join(types, ", "))
+			}
+			if _, implemented := endpoint.(Patcher); implemented {
+				w.Header().Set("Accept-Patch", strings.Join(types, ", "))
+			}
+		}
+		if advertiser, implemented := endpoint.(RangeAdvertiser); implemented {
+			if units := advertiser.RangeUnits(); len(units) > 0 {
+				w.Header().Set("Accept-Ranges", strings.Join(units, ", "))
+			} else {
+				w.Header().Set("Accept-Ranges", "none")
+			}
+		}
+		if m := getMux(r); m != nil && m.OptionsMaxAge > 0 {
+			w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", int(m.OptionsMaxAge.Seconds())))
+		}
 		w.WriteHeader(http.StatusNoContent)
 	})
 }
@@ -414,9 +1266,24 @@ func (h *endpointHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	methodHandler := getMethodHandler(h.endpoint, r.Method, r.Header)
 	if methodHandler == nil {
 		if allowed := AllowedMethods(h.endpoint); len(allowed) > 0 {
-			methodHandler = MethodNotAllowed(r.Method, allowed)
+			writeError(MethodNotAllowed(r.Method, allowed), w, r)
 		} else {
-			methodHandler = NotFound()
+			writeError(NotFound(), w, r)
+		}
+		return
+	}
+	if method := strings.ToUpper(r.Method); method != Options && method != Delete {
+		if err := restrictAccept(h.endpoint, r); err != nil {
+			writeError(err, w, r)
+			return
+		}
+		if err := checkContentType(h.endpoint, r); err != nil {
+			writeError(err, w, r)
+			return
+		}
+		if err := validateBody(h.endpoint, r); err != nil {
+			writeError(err, w, r)
+			return
 		}
 	}
 	methodHandler.ServeHTTP(w, r)
@@ -427,9 +1294,16 @@ func (h *endpointHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func getMethodHandler(endpoint Endpoint, method string, header http.Header) http.Handler {
 	switch strings.ToUpper(method) {
 	case Options:
-		return optionsHandler(endpoint)
+		// An endpoint that doesn't allow any method is treated as if it did not
+		// exist.
+		if len(AllowedMethods(endpoint)) > 0 {
+			return optionsHandler(endpoint)
+		}
 	case Head, Get:
 		if i, supported := endpoint.(Getter); supported {
+			if limiter, implemented := endpoint.(RangeSpanLimiter); implemented {
+				return &spanLimitedGetFunc{i.Get, limiter.MaxRangeSpan()}
+			}
 			return getFunc(i.Get)
 		}
 	case Patch:
@@ -454,7 +1328,33 @@ func getMethodHandler(endpoint Endpoint, method string, header http.Header) http
 
 var supportedMethods = []string{Head, Get, Patch, Put, Post, Delete}
 
+// sortMethods returns methods upper cased and without duplicates, in the order
+// of supportedMethods followed by OPTIONS, and then in alphabetical order.
+func sortMethods(methods []string) []string {
+	set := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		if method = strings.ToUpper(strings.TrimSpace(method)); method != "" {
+			set[method] = true
+		}
+	}
+
+	sorted := make([]string, 0, len(set))
+	for _, method := range append(supportedMethods, Options) {
+		if set[method] {
+			sorted = append(sorted, method)
+			delete(set, method)
+		}
+	}
+	var custom []string
+	for method := range set {
+		custom = append(custom, method)
+	}
+	sort.Strings(custom)
+	return append(sorted, custom...)
+}
+
 // AllowedMethods returns the list of HTTP methods allowed by this endpoint.
+// HEAD is allowed exactly when GET is, and served by the Getter of endpoint.
 func AllowedMethods(endpoint Endpoint) (methods []string) {
 	for _, method := range supportedMethods {
 		if getMethodHandler(endpoint, method, nil) != nil {
@@ -1,12 +1,20 @@
 package rst
 
 import (
+	"bufio"
 	"bytes"
+	"compress/gzip"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -63,6 +71,54 @@ func TestValidateConditions(t *testing.T) {
 	test(resource.LastModified().Add(-4*time.Hour), resource.ETag(), true) // true, false
 }
 
+func TestEvaluatePreconditions(t *testing.T) {
+	resource := NewEnvelope(nil, testTimeReference, "v2", 0)
+	before := testTimeReference.Add(-time.Hour).Format(rfc1123)
+	after := testTimeReference.Add(time.Hour).Format(rfc1123)
+
+	var test = func(method string, header map[string]string, status int) {
+		r, _ := http.NewRequest(method, "/", nil)
+		for name, value := range header {
+			r.Header.Set(name, value)
+		}
+		got, pass := EvaluatePreconditions(resource, r)
+		if got != status || pass != (status == http.StatusOK) {
+			t.Fatal(method, header, "Got:", got, pass, "Wanted:", status)
+		}
+	}
+
+	test(Get, nil, http.StatusOK)
+
+	// If-Match
+	test(Put, map[string]string{"If-Match": "v1, v2"}, http.StatusOK)
+	test(Put, map[string]string{"If-Match": "*"}, http.StatusOK)
+	test(Put, map[string]string{"If-Match": "v1"}, http.StatusPreconditionFailed)
+	test(Put, map[string]string{"If-Match": "W/v2"}, http.StatusPreconditionFailed)
+
+	// If-Unmodified-Since is ignored when If-Match is present.
+	test(Put, map[string]string{"If-Unmodified-Since": after}, http.StatusOK)
+	test(Put, map[string]string{"If-Unmodified-Since": before}, http.StatusPreconditionFailed)
+	test(Put, map[string]string{"If-Match": "v2", "If-Unmodified-Since": before}, http.StatusOK)
+	test(Put, map[string]string{"If-Match": "v1", "If-Unmodified-Since": after}, http.StatusPreconditionFailed)
+
+	// If-None-Match
+	test(Get, map[string]string{"If-None-Match": "W/v2"}, http.StatusNotModified)
+	test(Head, map[string]string{"If-None-Match": "v1, v2"}, http.StatusNotModified)
+	test(Get, map[string]string{"If-None-Match": "v1"}, http.StatusOK)
+	test(Put, map[string]string{"If-None-Match": "*"}, http.StatusPreconditionFailed)
+
+	// If-Modified-Since is ignored when If-None-Match is present, and for
+	// methods other than GET and HEAD.
+	test(Get, map[string]string{"If-Modified-Since": after}, http.StatusNotModified)
+	test(Get, map[string]string{"If-Modified-Since": before}, http.StatusOK)
+	test(Get, map[string]string{"If-None-Match": "v1", "If-Modified-Since": after}, http.StatusOK)
+	test(Get, map[string]string{"If-None-Match": "v2", "If-Modified-Since": before}, http.StatusNotModified)
+	test(Put, map[string]string{"If-Modified-Since": after}, http.StatusOK)
+
+	// If-Match failures take precedence over If-None-Match.
+	test(Get, map[string]string{"If-Match": "v1", "If-None-Match": "v2"}, http.StatusPreconditionFailed)
+}
+
 func TestAllowedMethods(t *testing.T) {
 	supported := AllowedMethods(&allInterfaces{})
 	expected := []string{Head, Get, Patch, Put, Post, Delete}
@@ -73,6 +129,298 @@ func TestAllowedMethods(t *testing.T) {
 	}
 }
 
+func TestRequirePreconditions(t *testing.T) {
+	var called bool
+	mux := NewMux()
+	mux.RequirePreconditions = true
+	mux.Handle("/text", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return Text(testCannedContent), nil
+	}))
+	mux.Handle("/text/put", putFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		called = true
+		return nil, nil
+	}))
+	mux.Handle("/text/delete", deleteFunc(func(vars RouteVars, r *http.Request) error {
+		called = true
+		return nil
+	}))
+
+	var test = func(method, path string, header map[string]string, expected int) {
+		called = false
+		r, _ := http.NewRequest(method, path, nil)
+		for key, value := range header {
+			r.Header.Set(key, value)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal(method, header, "status code. Got:", w.Code, "Wanted:", expected)
+		}
+		if expected == http.StatusPreconditionRequired && called {
+			t.Fatal(method, "the endpoint should not be called")
+		}
+	}
+
+	test(Put, "/text/put", nil, http.StatusPreconditionRequired)
+	test(Delete, "/text/delete", nil, http.StatusPreconditionRequired)
+	test(Put, "/text/put", map[string]string{"If-Match": `"v1"`}, http.StatusOK)
+	test(Delete, "/text/delete", map[string]string{"If-Unmodified-Since": testTimeReference.Format(rfc1123)}, http.StatusNoContent)
+	test(Get, "/text", nil, http.StatusOK)
+
+	test(Put, "/text/put", map[string]string{"If-None-Match": "*"}, http.StatusOK)
+	test(Delete, "/text/delete", map[string]string{"If-None-Match": "*"}, http.StatusPreconditionRequired)
+
+	mux.RequirePreconditions = false
+	test(Put, "/text/put", nil, http.StatusOK)
+}
+
+func TestPutAlreadyExists(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		created = make(map[string]bool)
+	)
+	mux := NewMux()
+	mux.Handle("/people/{id}", putFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if id := vars.Get("id"); !created[id] {
+			created[id] = true
+		} else if r.Header.Get("If-None-Match") == "*" || r.Header.Get("X-Create-Only") != "" {
+			return nil, fmt.Errorf("inserting %s: %w", id, ErrAlreadyExists)
+		}
+		return Text(testCannedContent), nil
+	}))
+
+	var test = func(path string, header map[string]string, expected int) {
+		r, _ := http.NewRequest(Put, path, strings.NewReader(testCannedContent))
+		for key, value := range header {
+			r.Header.Set(key, value)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal(path, header, "status code. Got:", w.Code, "Wanted:", expected)
+		}
+	}
+
+	create := map[string]string{"If-None-Match": "*"}
+	test("/people/42", create, http.StatusOK)
+	test("/people/42", create, http.StatusPreconditionFailed)
+	test("/people/42", nil, http.StatusOK)
+	test("/people/42", map[string]string{"X-Create-Only": "1"}, http.StatusConflict)
+}
+
+// locatedResource is a resource served with a Content-Location header.
+type locatedResource struct {
+	Resource
+	location string
+}
+
+func (l *locatedResource) ContentLocation() string {
+	return l.location
+}
+
+func TestContentLocation(t *testing.T) {
+	var location string
+	mux := NewMux()
+	mux.Handle("/people/me", putFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return &locatedResource{Text(testCannedContent), location}, nil
+	}))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var test = func(expected string) {
+		r, _ := http.NewRequest(Put, server.URL+"/people/me", strings.NewReader(testCannedContent))
+		resp, err := http.DefaultClient.Do(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatal("status code. Got:", resp.StatusCode, "Wanted:", http.StatusOK)
+		}
+		if got := resp.Header.Get("Content-Location"); got != expected {
+			t.Fatal("Content-Location. Got:", got, "Wanted:", expected)
+		}
+		if got := resp.Header.Get("ETag"); got == "" {
+			t.Fatal("ETag should be set in the response to PUT")
+		}
+	}
+
+	location = "/people/42"
+	test(server.URL + "/people/42")
+	location = "42"
+	test(server.URL + "/people/42")
+	location = "https://example.com/people/42"
+	test(location)
+	location = ""
+	test("")
+}
+
+func TestContentLocationExternalURL(t *testing.T) {
+	mux := NewMux()
+	mux.ExternalURL = "https://api.example.com"
+	mux.Handle("/people/me", putFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return &locatedResource{Text(testCannedContent), "/people/42"}, nil
+	}))
+
+	r, _ := http.NewRequest(Put, "http://10.0.0.1/people/me", strings.NewReader(testCannedContent))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	if got, wanted := w.Header().Get("Content-Location"), "https://api.example.com/people/42"; got != wanted {
+		t.Fatal("Content-Location. Got:", got, "Wanted:", wanted)
+	}
+}
+
+// csvExport is an endpoint only producing CSV.
+type csvExport struct{}
+
+func (ep *csvExport) Produces() []string {
+	return []string{"text/csv"}
+}
+
+func (ep *csvExport) Get(vars RouteVars, r *http.Request) (Resource, error) {
+	if accept := r.Header.Get("Accept"); accept != "text/csv" {
+		return nil, BadRequest("", "Accept should be restricted to text/csv. Got: "+accept)
+	}
+	return Blob("text/csv", []byte("id,name\n1,rst\n")), nil
+}
+
+func TestProducer(t *testing.T) {
+	mux := NewMux()
+	mux.HandleEndpoint("/export", &csvExport{})
+
+	var test = func(method, accept string, expected int) *httptest.ResponseRecorder {
+		r, _ := http.NewRequest(method, "/export", nil)
+		if accept != "" {
+			r.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal(method, accept, "status code. Got:", w.Code, "Wanted:", expected, w.Body.String())
+		}
+		return w
+	}
+
+	test(Get, "application/json", http.StatusNotAcceptable)
+	test(Get, "application/xml, application/json", http.StatusNotAcceptable)
+	test(Get, "text/csv", http.StatusOK)
+	test(Get, "application/json, text/*;q=0.5", http.StatusOK)
+	test(Get, "*/*", http.StatusOK)
+	test(Get, "", http.StatusOK)
+
+	w := test(Options, "application/json", http.StatusNoContent)
+	if got := w.Header().Get("Content-Type"); got != "text/csv" {
+		t.Fatal("OPTIONS Content-Type. Got:", got, "Wanted: text/csv")
+	}
+}
+
+// jsonWriter is an endpoint only accepting JSON in PUT and PATCH requests.
+type jsonWriter struct {
+	called bool
+}
+
+func (ep *jsonWriter) Consumes() []string {
+	return []string{"application/json"}
+}
+
+func (ep *jsonWriter) Put(vars RouteVars, r *http.Request) (Resource, error) {
+	ep.called = true
+	return nil, nil
+}
+
+func (ep *jsonWriter) Patch(vars RouteVars, r *http.Request) (Resource, error) {
+	ep.called = true
+	return nil, nil
+}
+
+func TestConsumer(t *testing.T) {
+	endpoint := &jsonWriter{}
+	mux := NewMux()
+	mux.HandleEndpoint("/people/1", endpoint)
+
+	var test = func(method, contentType, body string, expected int) *httptest.ResponseRecorder {
+		endpoint.called = false
+		r, _ := http.NewRequest(method, "/people/1", strings.NewReader(body))
+		if contentType != "" {
+			r.Header.Set("Content-Type", contentType)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal(method, contentType, "status code. Got:", w.Code, "Wanted:", expected)
+		}
+		if called := expected != http.StatusUnsupportedMediaType; endpoint.called != called {
+			t.Fatal(method, contentType, "endpoint called. Got:", endpoint.called, "Wanted:", called)
+		}
+		return w
+	}
+
+	test(Put, "application/xml", "<person/>", http.StatusUnsupportedMediaType)
+	test(Put, "", "{}", http.StatusUnsupportedMediaType)
+	test(Put, "application/json; charset=utf-8", "{}", http.StatusOK)
+	test(Put, "", "", http.StatusOK)
+	w := test(Patch, "text/plain", "name=rst", http.StatusUnsupportedMediaType)
+	if got := w.Header().Get("Accept-Patch"); got != "application/json" {
+		t.Fatal("Accept-Patch of the error. Got:", got, "Wanted: application/json")
+	}
+
+	r, _ := http.NewRequest(Options, "/people/1", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	if got := w.Header().Get("Accept-Patch"); got != "application/json" {
+		t.Fatal("Accept-Patch. Got:", got, "Wanted: application/json")
+	}
+	if got := w.Header().Get("Accept-Post"); got != "" {
+		t.Fatal("Accept-Post. Got:", got, "Wanted: none")
+	}
+}
+
+// getterOnly is an endpoint which only implements Getter.
+type getterOnly struct{}
+
+func (ep *getterOnly) Get(vars RouteVars, r *http.Request) (Resource, error) {
+	return Text(testCannedContent), nil
+}
+
+func TestHeadGetterOnly(t *testing.T) {
+	if got, expected := AllowedMethods(&getterOnly{}), []string{Head, Get}; !reflect.DeepEqual(got, expected) {
+		t.Fatal("AllowedMethods. Got:", got, "Wanted:", expected)
+	}
+
+	mux := NewMux()
+	mux.HandleEndpoint("/text", &getterOnly{})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var test = func(method string) *http.Response {
+		r, _ := http.NewRequest(method, server.URL+"/text", nil)
+		resp, err := http.DefaultTransport.RoundTrip(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatal(method, "status code. Got:", resp.StatusCode, "Wanted:", http.StatusOK)
+		}
+		if b, _ := ioutil.ReadAll(resp.Body); method == Head && len(b) > 0 {
+			t.Fatal("HEAD body. Got:", string(b), "Wanted: empty body")
+		}
+		return resp
+	}
+
+	get, head := test(Get), test(Head)
+	if got, expected := head.Header.Get("Content-Length"), get.Header.Get("Content-Length"); got == "" || got != expected {
+		t.Fatal("HEAD Content-Length. Got:", got, "Wanted:", expected)
+	}
+	for _, name := range []string{"Content-Type", "ETag", "Last-Modified"} {
+		if got, expected := head.Header.Get(name), get.Header.Get(name); got != expected {
+			t.Fatal("HEAD", name, "Got:", got, "Wanted:", expected)
+		}
+	}
+}
+
 func TestResourceHTTPHandlerInterface(t *testing.T) {
 	rr := newRequestResponse(Post, testServerAddr+"/chunked", nil, bytes.NewReader(testMBText))
 	if err := rr.TestStatusCode(http.StatusOK); err != nil {
@@ -84,7 +432,7 @@ func TestResourceHTTPHandlerInterface(t *testing.T) {
 	if err := rr.TestHasHeader("Etag"); err != nil {
 		t.Fatal(err)
 	}
-	if err := rr.TestHasHeader("Expires"); err != nil {
+	if err := rr.TestHeader("Cache-Control", "no-cache"); err != nil {
 		t.Fatal(err)
 	}
 	if err := rr.TestBody(bytes.NewReader(testMBText)); err != nil {
@@ -92,6 +440,59 @@ func TestResourceHTTPHandlerInterface(t *testing.T) {
 	}
 }
 
+func TestResourceHTTPHandlerCompression(t *testing.T) {
+	defer func() {
+		HandlerCompression = false
+	}()
+	HandlerCompression = true
+
+	var test = func(format string) {
+		header := make(http.Header)
+		header.Set("Accept-Encoding", format)
+		rr := newRequestResponse(Post, testServerAddr+"/chunked", header, bytes.NewReader(testMBText))
+		if err := rr.TestStatusCode(http.StatusOK); err != nil {
+			t.Fatal(err)
+		}
+		if err := rr.TestHeader("Content-Encoding", format); err != nil {
+			t.Fatal(err)
+		}
+		if vary := strings.Join(rr.resp.Header["Vary"], ", "); !strings.Contains(vary, "Accept-Encoding") {
+			t.Fatal("Vary header. Got:", vary, "Wanted: Accept-Encoding")
+		}
+		if decompressed, err := decompress(rr.resp.Body, format); err != nil {
+			t.Fatal(err)
+		} else if !bytes.Equal(testMBText, decompressed) {
+			t.Fatal(format, "data was decompressed but did not match the expected value")
+		}
+	}
+	test("gzip")
+	test("deflate")
+
+	// Handlers setting their own Content-Encoding are not compressed again.
+	w := httptest.NewRecorder()
+	cw := newCompressWriter(w, "gzip")
+	cw.Header().Set("Content-Encoding", "br")
+	cw.Write(testCannedBytes)
+	cw.close()
+	if !bytes.Equal(w.Body.Bytes(), testCannedBytes) {
+		t.Fatal("data written with a custom Content-Encoding was modified")
+	}
+}
+
+func TestTrailer(t *testing.T) {
+	rr := newRequestResponse(Get, testServerAddr+"/trailer", nil, nil)
+	if err := rr.TestStatusCode(http.StatusOK); err != nil {
+		t.Fatal(err)
+	}
+	if err := rr.TestBody(bytes.NewReader(testCannedBytes)); err != nil {
+		t.Fatal(err)
+	}
+	// Trailers are only available once the body has been read.
+	if count := rr.resp.Trailer.Get("X-Content-Count"); count != strconv.Itoa(len(testCannedBytes)) {
+		t.Fatal("trailer X-Content-Count. Got:", count, "Wanted:", len(testCannedBytes))
+	}
+}
+
 func TestGetMethodHandler(t *testing.T) {
 	var test = func(method string, header http.Header, expected reflect.Type) {
 		all := &allInterfaces{}
@@ -131,6 +532,105 @@ func TestOptionsHandler(t *testing.T) {
 	}
 }
 
+func TestOptionsMarshalers(t *testing.T) {
+	const vendorType = "application/vnd.myapp+json"
+	Marshalers[vendorType] = json.Marshal
+	defer delete(Marshalers, vendorType)
+
+	mux := NewMux()
+	mux.HandleEndpoint("/people", &peopleCollection{})
+	mux.HandleDiscovery("/")
+
+	r, _ := http.NewRequest(Options, "/people", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	if got := w.Header().Get("Content-Type"); !strings.Contains(got, vendorType) {
+		t.Fatal("Content-Type. Got:", got, "Wanted:", vendorType)
+	}
+
+	r, _ = http.NewRequest(Get, "/", nil)
+	r.Header.Set("Accept", "application/json")
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	if !strings.Contains(w.Body.String(), vendorType) {
+		t.Fatal("Discovery manifest. Got:", w.Body.String(), "Wanted:", vendorType)
+	}
+}
+
+// consumingPoster is a Poster accepting JSON and XML bodies.
+type consumingPoster struct{ echoEndpoint }
+
+func (ep *consumingPoster) Consumes() []string {
+	return []string{"application/json", "application/xml"}
+}
+
+// consumingGetter declares media types, but doesn't accept POST requests.
+type consumingGetter struct{ getterOnly }
+
+func (ep *consumingGetter) Consumes() []string {
+	return []string{"application/json"}
+}
+
+func TestOptionsAcceptPost(t *testing.T) {
+	var test = func(endpoint Endpoint, expected string) {
+		r, _ := http.NewRequest(Options, "/", nil)
+		w := httptest.NewRecorder()
+		optionsHandler(endpoint).ServeHTTP(w, r)
+		if got := w.Header().Get("Accept-Post"); got != expected {
+			t.Fatal("Accept-Post. Got:", got, "Wanted:", expected)
+		}
+		if _, present := w.Header()["Accept-Post"]; expected == "" && present {
+			t.Fatal("Accept-Post should be omitted. Got:", w.Header())
+		}
+	}
+
+	test(&consumingPoster{}, "application/json, application/xml")
+	test(&echoEndpoint{}, "")
+	test(&consumingGetter{}, "")
+	test(&getterOnly{}, "")
+}
+
+type rangedGetter struct {
+	getterOnly
+	units []string
+}
+
+func (ep *rangedGetter) RangeUnits() []string {
+	return ep.units
+}
+
+func TestOptionsAcceptRanges(t *testing.T) {
+	var test = func(endpoint Endpoint, expected string) {
+		r, _ := http.NewRequest(Options, "/", nil)
+		w := httptest.NewRecorder()
+		optionsHandler(endpoint).ServeHTTP(w, r)
+		if got := w.Header().Get("Accept-Ranges"); got != expected {
+			t.Fatal("Accept-Ranges. Got:", got, "Wanted:", expected)
+		}
+	}
+
+	test(&rangedGetter{units: []string{"items"}}, "items")
+	test(&rangedGetter{units: []string{"bytes", "items"}}, "bytes, items")
+	test(&rangedGetter{}, "none")
+	test(&getterOnly{}, "")
+}
+
+func TestOptionsNotFound(t *testing.T) {
+	rr := newRequestResponse(Options, testServerAddr+"/unregistered", nil, nil)
+	if err := rr.TestStatusCode(http.StatusNotFound); err != nil {
+		t.Fatal(err)
+	}
+
+	mux := NewMux()
+	mux.HandleEndpoint("/empty", struct{}{})
+	r, _ := http.NewRequest(Options, "/empty", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	if w.Code != http.StatusNotFound {
+		t.Fatal("status code. Got:", w.Code, "Wanted:", http.StatusNotFound)
+	}
+}
+
 func TestGetHandler(t *testing.T) {
 	var test = func(method string) *requestResponse {
 		header := make(http.Header)
@@ -184,6 +684,75 @@ func TestExpires(t *testing.T) {
 	}
 }
 
+func TestCacheHeaders(t *testing.T) {
+	var test = func(ttl time.Duration, cacheControl string, expires bool) {
+		rr := newRequestResponse(Get, fmt.Sprintf("%s/ttl/%d", testServerAddr, int(ttl.Seconds())), nil, nil)
+		if err := rr.TestStatusCode(http.StatusOK); err != nil {
+			t.Fatal(err)
+		}
+		if err := rr.TestHeader("Cache-Control", cacheControl); err != nil {
+			t.Fatal(ttl, err)
+		}
+		if _, exists := rr.resp.Header["Expires"]; exists != expires {
+			t.Fatal(ttl, "Expires header. Got:", exists, "Wanted:", expires)
+		}
+	}
+
+	test(time.Minute, "", true)
+	test(0, "no-cache", false)
+	test(-time.Minute, "no-store", false)
+}
+
+// Validators a resource can't provide must not be written in the response.
+func TestMissingValidators(t *testing.T) {
+	header := make(http.Header)
+	header.Set("If-None-Match", "")
+	rr := newRequestResponse(Get, testServerAddr+"/unvalidated", header, nil)
+	if err := rr.TestStatusCode(http.StatusOK); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"ETag", "Last-Modified"} {
+		if values, exists := rr.resp.Header[name]; exists {
+			t.Fatal(name, "header. Got:", values, "Wanted: none")
+		}
+	}
+	if err := rr.TestBody(bytes.NewReader(testBlobContent)); err != nil {
+		t.Fatal(err)
+	}
+
+	// If-Range can't match a resource without an ETag.
+	header = make(http.Header)
+	header.Set("Range", "bytes=0-3")
+	header.Set("If-Range", `""`)
+	rr = newRequestResponse(Get, testServerAddr+"/unvalidated", header, nil)
+	if err := rr.TestStatusCode(http.StatusOK); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// A resource without a last modification date must never be considered
+// unmodified.
+func TestZeroLastModified(t *testing.T) {
+	var test = func(name, value string) {
+		header := make(http.Header)
+		header.Set(name, value)
+		if name == "If-Range" {
+			header.Set("Range", "bytes=0-3")
+		}
+		rr := newRequestResponse(Get, testServerAddr+"/unvalidated", header, nil)
+		if err := rr.TestStatusCode(http.StatusOK); err != nil {
+			t.Fatal(name, err)
+		}
+		if values, exists := rr.resp.Header["Last-Modified"]; exists {
+			t.Fatal("Last-Modified header. Got:", values, "Wanted: none")
+		}
+	}
+
+	test("If-Modified-Since", time.Now().UTC().Format(rfc1123))
+	test("If-Modified-Since", time.Time{}.Format(rfc1123))
+	test("If-Range", time.Time{}.Format(rfc1123))
+}
+
 func TestGetConditional(t *testing.T) {
 	var test = func(method string, date time.Time, expected int) *requestResponse {
 		header := make(http.Header)
@@ -203,6 +772,207 @@ func TestGetConditional(t *testing.T) {
 	test(Get, testTimeReference.Add(-24*time.Hour), http.StatusOK)
 }
 
+// If-None-Match takes precedence over If-Modified-Since.
+func TestGetConditionalPrecedence(t *testing.T) {
+	resource := testPeople[len(testPeople)-1]
+	url := testServerAddr + "/people/" + resource.ID
+
+	var test = func(etag string, date time.Time, expected int) {
+		header := make(http.Header)
+		header.Set("If-None-Match", etag)
+		header.Set("If-Modified-Since", date.UTC().Format(rfc1123))
+		rr := newRequestResponse(Get, url, header, nil)
+		if err := rr.TestStatusCode(expected); err != nil {
+			t.Fatal(etag, date, err)
+		}
+	}
+
+	test("outdated", time.Now(), http.StatusOK)
+	test(resource.ETag(), testTimeReference.Add(-24*time.Hour), http.StatusNotModified)
+
+	// If-Match failures are reported before anything else.
+	header := make(http.Header)
+	header.Set("If-Match", "outdated")
+	header.Set("If-None-Match", resource.ETag())
+	rr := newRequestResponse(Get, url, header, nil)
+	if err := rr.TestStatusCode(http.StatusPreconditionFailed); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetRangeNotModified(t *testing.T) {
+	resource := Blob("text/plain", testCannedBytes)
+	mux := NewMux()
+	mux.Handle("/blob", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return resource, nil
+	}))
+
+	var test = func(rg, etag string, expected int) {
+		r, _ := http.NewRequest(Get, "/blob", nil)
+		r.Header.Set("Range", rg)
+		r.Header.Set("If-None-Match", etag)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal(rg, etag, "status code. Got:", w.Code, "Wanted:", expected)
+		}
+		if expected == http.StatusNotModified && (w.Body.Len() > 0 || w.Header().Get("Content-Range") != "") {
+			t.Fatal(rg, "304 with a partial body. Got:", w.Body.String(), w.Header().Get("Content-Range"))
+		}
+	}
+
+	test("bytes=0-3", resource.ETag(), http.StatusNotModified)
+	test("bytes=0-3", "outdated", http.StatusPartialContent)
+	// Unsatisfiable ranges are not reported to clients with a fresh copy.
+	test("bytes=100000-200000", resource.ETag(), http.StatusNotModified)
+	test("bytes=100000-200000", "outdated", http.StatusRequestedRangeNotSatisfiable)
+}
+
+func TestGetMaxRanges(t *testing.T) {
+	defer func(original int) { MaxRanges = original }(MaxRanges)
+	MaxRanges = 10
+	mux := NewMux()
+	mux.Handle("/blob", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return Blob("text/plain", testCannedBytes), nil
+	}))
+
+	var ranges []string
+	for i := 0; i < 11; i++ {
+		ranges = append(ranges, fmt.Sprintf("%d-%d", i*2, i*2+1))
+	}
+	r, _ := http.NewRequest(Get, "/blob", nil)
+	r.Header.Set("Range", "bytes="+strings.Join(ranges, ","))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatal("status code. Got:", w.Code, "Wanted:", http.StatusOK)
+	}
+	if !bytes.Equal(w.Body.Bytes(), testCannedBytes) {
+		t.Fatal("body. Got:", w.Body.String(), "Wanted:", testCannedContent)
+	}
+	if cr := w.Header().Get("Content-Range"); cr != "" {
+		t.Fatal("Content-Range. Got:", cr, "Wanted: none")
+	}
+}
+
+// looseRanger returns parts with validators of their own.
+type looseRanger struct {
+	*blob
+}
+
+func (lr *looseRanger) Range(rg *Range) (*ContentRange, Resource, error) {
+	part := Blob(lr.contentType, lr.data[rg.From:rg.To+1])
+	return &ContentRange{rg, lr.Count()}, part, nil
+}
+
+func TestGetRangeETag(t *testing.T) {
+	resource := &looseRanger{Blob("text/plain", testCannedBytes).(*blob)}
+	resource.lastModified = testTimeReference
+	mux := NewMux()
+	mux.Handle("/blob", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return resource, nil
+	}))
+
+	var test = func(ifRange string) {
+		r, _ := http.NewRequest(Get, "/blob", nil)
+		r.Header.Set("Range", "bytes=0-3")
+		if ifRange != "" {
+			r.Header.Set("If-Range", ifRange)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != http.StatusPartialContent {
+			t.Fatal(ifRange, "status code. Got:", w.Code, "Wanted:", http.StatusPartialContent)
+		}
+		if etag := w.Header().Get("ETag"); etag != resource.ETag() {
+			t.Fatal("ETag. Got:", etag, "Wanted:", resource.ETag())
+		}
+		if modified, expected := w.Header().Get("Last-Modified"), testTimeReference.UTC().Format(rfc1123); modified != expected {
+			t.Fatal("Last-Modified. Got:", modified, "Wanted:", expected)
+		}
+	}
+
+	test("")
+	test(resource.ETag())
+}
+
+func TestGetMaxRangeSpan(t *testing.T) {
+	defer func(span uint64, reject bool) {
+		MaxRangeSpan, RejectLongRanges = span, reject
+	}(MaxRangeSpan, RejectLongRanges)
+	MaxRangeSpan = 10
+	data := bytes.Repeat(testCannedBytes, 10)
+	mux := NewMux()
+	mux.Handle("/blob", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return Blob("text/plain", data), nil
+	}))
+
+	var test = func(rg string, expected int, contentRange string) {
+		r, _ := http.NewRequest(Get, "/blob", nil)
+		r.Header.Set("Range", rg)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal(rg, "status code. Got:", w.Code, "Wanted:", expected)
+		}
+		if got := w.Header().Get("Content-Range"); got != contentRange {
+			t.Fatal(rg, "Content-Range. Got:", got, "Wanted:", contentRange)
+		}
+	}
+
+	total := len(data)
+	test("bytes=5-", http.StatusPartialContent, fmt.Sprintf("bytes 5-14/%d", total))
+	test("bytes=0-999999999", http.StatusPartialContent, fmt.Sprintf("bytes 0-9/%d", total))
+	test("bytes=0-9", http.StatusPartialContent, fmt.Sprintf("bytes 0-9/%d", total))
+
+	RejectLongRanges = true
+	test("bytes=0-999999999", http.StatusRequestedRangeNotSatisfiable, fmt.Sprintf("*/%d", total))
+	test("bytes=0-9", http.StatusPartialContent, fmt.Sprintf("bytes 0-9/%d", total))
+}
+
+// spanLimitedEndpoint serves a blob with its own maximum range span.
+type spanLimitedEndpoint struct {
+	data []byte
+	span uint64
+}
+
+func (ep *spanLimitedEndpoint) Get(vars RouteVars, r *http.Request) (Resource, error) {
+	return Blob("text/plain", ep.data), nil
+}
+
+func (ep *spanLimitedEndpoint) MaxRangeSpan() uint64 {
+	return ep.span
+}
+
+func TestGetEndpointMaxRangeSpan(t *testing.T) {
+	defer func(span uint64) { MaxRangeSpan = span }(MaxRangeSpan)
+	MaxRangeSpan = 10
+	data := bytes.Repeat(testCannedBytes, 10)
+	mux := NewMux()
+	mux.Handle("/default", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return Blob("text/plain", data), nil
+	}))
+	mux.Handle("/limited", EndpointHandler(&spanLimitedEndpoint{data, 5}))
+	mux.Handle("/unlimited", EndpointHandler(&spanLimitedEndpoint{data, 0}))
+
+	total := len(data)
+	var test = func(path, contentRange string) {
+		r, _ := http.NewRequest(Get, path, nil)
+		r.Header.Set("Range", "bytes=0-99")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != http.StatusPartialContent {
+			t.Fatal(path, "status code. Got:", w.Code, "Wanted:", http.StatusPartialContent)
+		}
+		if got := w.Header().Get("Content-Range"); got != contentRange {
+			t.Fatal(path, "Content-Range. Got:", got, "Wanted:", contentRange)
+		}
+	}
+	test("/default", fmt.Sprintf("bytes 0-9/%d", total))
+	test("/limited", fmt.Sprintf("bytes 0-4/%d", total))
+	test("/unlimited", fmt.Sprintf("bytes 0-99/%d", total))
+}
+
 // Get with invalid Range header should behave like a normal Get.
 func TestGetInvalidRangeHandler(t *testing.T) {
 	var test = func(method string) {
@@ -269,6 +1039,109 @@ func TestIfRangeGetHander(t *testing.T) {
 	test("blablabla", http.StatusOK)
 }
 
+func TestPartialGetRangerHTTPHandler(t *testing.T) {
+	// Full response written by the original resource.
+	rr := newRequestResponse(Get, testServerAddr+"/ranged-handler", nil, nil)
+	if err := rr.TestStatusCode(http.StatusOK); err != nil {
+		t.Fatal(err)
+	}
+	if err := rr.TestHeader("Accept-Ranges", "bytes"); err != nil {
+		t.Fatal(err)
+	}
+	if err := rr.TestBody(bytes.NewReader(testCannedBytes)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Partial response written by the resource returned by Range.
+	header := make(http.Header)
+	header.Set("Range", "bytes=0-4")
+	rr = newRequestResponse(Get, testServerAddr+"/ranged-handler", header, nil)
+	if err := rr.TestStatusCode(http.StatusPartialContent); err != nil {
+		t.Fatal(err)
+	}
+	if err := rr.TestHeader("Content-Range", fmt.Sprintf("bytes 0-4/%d", len(testCannedBytes))); err != nil {
+		t.Fatal(err)
+	}
+	if err := rr.TestBody(bytes.NewReader(testCannedBytes[:5])); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Partial responses must never be compressed.
+func TestPartialGetCompression(t *testing.T) {
+	defer func() {
+		HandlerCompression = false
+	}()
+	HandlerCompression = true
+
+	var test = func(url string, size int, content []byte) {
+		header := make(http.Header)
+		header.Set("Accept-Encoding", "gzip")
+		header.Set("Range", fmt.Sprintf("bytes=0-%d", size-1))
+		rr := newRequestResponse(Get, url, header, nil)
+		if err := rr.TestStatusCode(http.StatusPartialContent); err != nil {
+			t.Fatal(url, err)
+		}
+		if err := rr.TestHeader("Content-Encoding", ""); err != nil {
+			t.Fatal(url, err)
+		}
+		if err := rr.TestBody(bytes.NewReader(content[:size])); err != nil {
+			t.Fatal(url, err)
+		}
+	}
+
+	test(testServerAddr+"/large-blob", CompressionThreshold*2, testMBText)
+	test(testServerAddr+"/ranged-handler", 5, testCannedBytes)
+}
+
+func TestPartialGetRangeUnavailable(t *testing.T) {
+	header := make(http.Header)
+	header.Set("Range", "bytes=0-4")
+	rr := newRequestResponse(Get, testServerAddr+"/unseekable", header, nil)
+	if err := rr.TestStatusCode(http.StatusOK); err != nil {
+		t.Fatal(err)
+	}
+	if err := rr.TestHeader("Accept-Ranges", "none"); err != nil {
+		t.Fatal(err)
+	}
+	if err := rr.TestHeader("Content-Range", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := rr.TestBody(bytes.NewReader(testBlobContent)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMatchIfRange(t *testing.T) {
+	lastModified := testTimeReference.Add(450 * time.Millisecond)
+	var test = func(etag, ifRange string, expected bool) {
+		resource := NewEnvelope(nil, lastModified, etag, 0)
+		if got := matchIfRange(ifRange, resource); got != expected {
+			t.Errorf("etag %s, If-Range %s. Got: %t Wanted: %t", etag, ifRange, got, expected)
+		}
+	}
+
+	test(`"strong"`, `"strong"`, true)
+	test(`"strong"`, `"other"`, false)
+	test(`W/"weak"`, `W/"weak"`, false)
+	test(`W/"weak"`, `"weak"`, false)
+	test(`"strong"`, lastModified.UTC().Format(rfc1123), true)
+	test(`"strong"`, lastModified.Add(time.Second).UTC().Format(rfc1123), false)
+}
+
+func TestIfRangeWeakETag(t *testing.T) {
+	header := make(http.Header)
+	header.Set("Range", "bytes=0-4")
+	header.Set("If-Range", (&weakBlob{Blob(testBlobContentType, testBlobContent).(*blob)}).ETag())
+	rr := newRequestResponse(Get, testServerAddr+"/weak", header, nil)
+	if err := rr.TestStatusCode(http.StatusOK); err != nil {
+		t.Fatal(err)
+	}
+	if err := rr.TestBody(bytes.NewReader(testBlobContent)); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestPartialGetNotSatisfiableHandler(t *testing.T) {
 	var test = func(method string) {
 		header := make(http.Header)
@@ -352,3 +1225,234 @@ func TestPost(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestResponseTransformer(t *testing.T) {
+	defer func() {
+		ResponseTransformer = nil
+	}()
+	ResponseTransformer = func(resource Resource, r *http.Request) interface{} {
+		return map[string]interface{}{
+			"data": resource,
+			"meta": map[string]string{"etag": resource.ETag()},
+		}
+	}
+
+	p := testPeople[len(testPeople)-1]
+	header := make(http.Header)
+	header.Set("Accept", "application/json")
+	rr := newRequestResponse(Get, testServerAddr+"/people/"+p.ID, header, nil)
+	if err := rr.TestStatusCode(http.StatusOK); err != nil {
+		t.Fatal(err)
+	}
+	if err := rr.TestHeader("ETag", p.ETag()); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(map[string]interface{}{
+		"data": p,
+		"meta": map[string]string{"etag": p.ETag()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rr.TestBody(bytes.NewReader(b)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResponseTransformerMarshaler(t *testing.T) {
+	defer func() {
+		ResponseTransformer = nil
+	}()
+	ResponseTransformer = func(resource Resource, r *http.Request) interface{} {
+		return map[string]interface{}{"data": resource}
+	}
+
+	binary := []byte{0x1f, 0x8b, 0xff, 0x00}
+	mux := NewMux()
+	mux.Handle("/people", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return NewCollection([]string{"Ada", "Grace"}, 0, 2, time.Time{}, "", 0), nil
+	}))
+	mux.Handle("/blob", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return Blob("application/octet-stream", binary), nil
+	}))
+
+	var test = func(path string, expected []byte) {
+		r, _ := http.NewRequest(Get, path, nil)
+		r.Header.Set("Accept", "*/*")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatal(path, "status code. Got:", w.Code, "Wanted:", http.StatusOK)
+		}
+		if !bytes.Equal(w.Body.Bytes(), expected) {
+			t.Fatal(path, "body. Got:", w.Body.String(), "Wanted:", string(expected))
+		}
+	}
+
+	test("/people", []byte(`{"data":["Ada","Grace"]}`))
+	test("/blob", binary)
+}
+
+// streamResource writes a first chunk, and waits for the client to receive it
+// before it writes the second one.
+type streamResource struct {
+	received chan bool
+}
+
+func (s *streamResource) ETag() string            { return "" }
+func (s *streamResource) LastModified() time.Time { return time.Time{} }
+func (s *streamResource) TTL() time.Duration      { return 0 }
+func (s *streamResource) ContentType() string     { return "text/plain; charset=utf-8" }
+
+func (s *streamResource) Stream(w io.Writer) error {
+	io.WriteString(w, "first\n")
+	select {
+	case <-s.received:
+	case <-time.After(2 * time.Second):
+		return errors.New("first chunk was not received")
+	}
+	_, err := io.WriteString(w, "second\n")
+	return err
+}
+
+type streamEndpoint struct {
+	resource *streamResource
+}
+
+func (ep *streamEndpoint) Get(vars RouteVars, r *http.Request) (Resource, error) {
+	return ep.resource, nil
+}
+
+func TestStreamer(t *testing.T) {
+	resource := &streamResource{make(chan bool)}
+	mux := NewMux()
+	mux.HandleEndpoint("/stream", &streamEndpoint{resource})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var test = func(encoding, expected string) {
+		r, _ := http.NewRequest(Get, server.URL+"/stream", nil)
+		r.Header.Set("Accept-Encoding", encoding)
+		resp, err := http.DefaultClient.Do(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		if resp.ContentLength != -1 {
+			t.Fatal("Content-Length. Got:", resp.ContentLength, "Wanted: unknown")
+		}
+		if got := resp.Header.Get("Content-Encoding"); got != expected {
+			t.Fatal("Content-Encoding. Got:", got, "Wanted:", expected)
+		}
+
+		var body io.Reader = resp.Body
+		if expected == "gzip" {
+			if body, err = gzip.NewReader(resp.Body); err != nil {
+				t.Fatal(err)
+			}
+		}
+		reader := bufio.NewReader(body)
+		if line, err := reader.ReadString('\n'); err != nil || line != "first\n" {
+			t.Fatal("First chunk. Got:", line, err)
+		}
+		resource.received <- true
+		if line, err := reader.ReadString('\n'); err != nil || line != "second\n" {
+			t.Fatal("Second chunk. Got:", line, err)
+		}
+	}
+
+	test("identity", "")
+	test("gzip", "gzip")
+}
+
+func TestSizedStreamer(t *testing.T) {
+	data := bytes.Repeat(testCannedBytes, 100)
+	mux := NewMux()
+	mux.Handle("/file", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return ReaderAt("application/octet-stream", bytes.NewReader(data), int64(len(data)), time.Now()), nil
+	}))
+
+	var test = func(method, encoding, contentLength string) {
+		r, _ := http.NewRequest(method, "/file", nil)
+		r.Header.Set("Accept-Encoding", encoding)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if got := w.Header().Get("Content-Length"); got != contentLength {
+			t.Fatal(method, encoding, "Content-Length. Got:", got, "Wanted:", contentLength)
+		}
+		if method == Get && encoding == "identity" && !bytes.Equal(w.Body.Bytes(), data) {
+			t.Fatal(method, encoding, "body. Got:", w.Body.Len(), "bytes. Wanted:", len(data))
+		}
+	}
+
+	size := strconv.Itoa(len(data))
+	test(Get, "identity", size)
+	test(Head, "identity", size)
+	test(Get, "gzip", "")
+	test(Head, "gzip", "")
+}
+
+// failingStreamer announces a length, but fails before writing anything.
+type failingStreamer struct{}
+
+func (s *failingStreamer) ETag() string             { return "" }
+func (s *failingStreamer) LastModified() time.Time  { return time.Time{} }
+func (s *failingStreamer) TTL() time.Duration       { return 0 }
+func (s *failingStreamer) ContentType() string      { return "application/octet-stream" }
+func (s *failingStreamer) Size() int64              { return 1 << 20 }
+func (s *failingStreamer) Stream(w io.Writer) error { return ServiceUnavailable(0) }
+
+func TestSizedStreamerError(t *testing.T) {
+	mux := NewMux()
+	mux.Handle("/file", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return &failingStreamer{}, nil
+	}))
+
+	r, _ := http.NewRequest(Get, "/file", nil)
+	r.Header.Set("Accept-Encoding", "identity")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatal("Status code. Got:", w.Code, "Wanted:", http.StatusServiceUnavailable)
+	}
+	if got := w.Header().Get("Content-Length"); got != "" {
+		t.Fatal("Content-Length. Got:", got, "Wanted: none")
+	}
+}
+
+// closingStreamer counts the number of times it was closed.
+type closingStreamer struct {
+	failingStreamer
+	closed int
+}
+
+func (s *closingStreamer) ETag() string { return `"v1"` }
+func (s *closingStreamer) Close() error { s.closed++; return nil }
+
+func TestStreamerClosed(t *testing.T) {
+	resource := &closingStreamer{}
+	mux := NewMux()
+	mux.Handle("/file", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return resource, nil
+	}))
+
+	var test = func(header, value string, expected int) {
+		resource.closed = 0
+		r, _ := http.NewRequest(Get, "/file", nil)
+		r.Header.Set(header, value)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal(header, "status code. Got:", w.Code, "Wanted:", expected)
+		}
+		if resource.closed != 1 {
+			t.Fatal(header, "the streamer was closed", resource.closed, "times. Wanted: 1")
+		}
+	}
+
+	test("If-None-Match", `"v1"`, http.StatusNotModified)
+	test("If-Match", `"v2"`, http.StatusPreconditionFailed)
+	test("Accept", "*/*", http.StatusServiceUnavailable)
+}
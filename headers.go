@@ -4,6 +4,8 @@
 package rst
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
@@ -122,10 +124,125 @@ func (accept Accept) Negotiate(alternatives ...string) (contentType string) {
 	return
 }
 
+// EncodingClause represents a clause in an HTTP Accept-Encoding header.
+type EncodingClause struct {
+	Coding string
+	Q      float64
+}
+
+// AcceptEncoding represents a set of clauses in an HTTP Accept-Encoding header.
+type AcceptEncoding []EncodingClause
+
+// ParseAcceptEncoding parses the raw value of an Accept-Encoding header, and
+// returns its clauses in the order in which they appear.
+func ParseAcceptEncoding(header string) AcceptEncoding {
+	accept := make(AcceptEncoding, 0)
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		clause := EncodingClause{
+			Coding: strings.ToLower(strings.Trim(params[0], " ")),
+			Q:      1.0,
+		}
+		if clause.Coding == "" {
+			continue
+		}
+		for _, param := range params[1:] {
+			sp := strings.SplitN(param, "=", 2)
+			if len(sp) == 2 && strings.Trim(sp[0], " ") == "q" {
+				clause.Q, _ = strconv.ParseFloat(strings.Trim(sp[1], " "), 32)
+			}
+		}
+		accept = append(accept, clause)
+	}
+	return accept
+}
+
+// Q returns the quality value of coding in accept. Codings that are not listed
+// get the value of the * clause, or 0, except identity which is always
+// acceptable unless it is explicitly excluded.
+func (accept AcceptEncoding) Q(coding string) float64 {
+	if q, listed := accept.lookup(coding); listed {
+		return q
+	}
+	if coding == "identity" {
+		return 1.0
+	}
+	return 0
+}
+
+// lookup returns the quality value of coding, and whether it is listed in
+// accept either by name or through the * clause.
+func (accept AcceptEncoding) lookup(coding string) (q float64, listed bool) {
+	wildcard, hasWildcard := 0.0, false
+	for _, clause := range accept {
+		switch clause.Coding {
+		case coding:
+			return clause.Q, true
+		case "*":
+			wildcard, hasWildcard = clause.Q, true
+		}
+	}
+	return wildcard, hasWildcard
+}
+
+// Negotiate returns the alternative with the highest quality value in accept,
+// or an empty string if none is acceptable. Codings with a quality value of 0
+// are never returned.
+//
+// Identity, the absence of encoding, only competes with the alternatives when
+// it's listed in accept, and loses ties. When several alternatives have the
+// same quality value, the first one in the list wins.
+func (accept AcceptEncoding) Negotiate(alternatives ...string) (coding string) {
+	best, _ := accept.lookup("identity")
+	for _, alternative := range alternatives {
+		if q := accept.Q(alternative); q > 0 && (q > best || (q == best && coding == "")) {
+			coding, best = alternative, q
+		}
+	}
+	return
+}
+
 var (
 	rangeRe = regexp.MustCompile("^(\\w+)=(\\d+)-(\\d+)?$")
 )
 
+/*
+MaxRanges is the maximum number of ranges a client can request in a single
+Range header. ParseRange rejects headers listing more ranges, and the requests
+sending them receive the full resource with a 200 OK, which prevents clients
+from amplifying the work of the server with thousands of tiny ranges. A value
+of 0 disables the limit.
+
+Only single ranges are served as partial responses. Requests for several ranges
+within the limit receive the full resource as well.
+*/
+var MaxRanges = 10
+
+/*
+MaxRangeSpan is the maximum number of units a client can request in a single
+range, which protects resources backed by expensive storage from huge reads. A
+range spanning more units is clamped to its first MaxRangeSpan units, and the
+client receives a Content-Range header describing the part it got.
+
+	rst.MaxRangeSpan = 1 << 20 // 1MB per request for bytes.
+
+A value of 0 disables the limit. Endpoints implementing RangeSpanLimiter
+override it for the resources they serve.
+*/
+var MaxRangeSpan uint64
+
+// RejectLongRanges makes ranges spanning more than the maximum span fail with
+// a 416 Requested Range Not Satisfiable error instead of being clamped.
+var RejectLongRanges = false
+
+// countRanges returns the number of ranges listed in the Range header raw.
+func countRanges(raw string) int {
+	if raw == "" {
+		return 0
+	}
+	return strings.Count(raw, ",") + 1
+}
+
 // Range is a structured representation of the Range request header.
 //
 type Range struct {
@@ -152,18 +269,26 @@ func (r *Range) validate(ranger Ranger) error {
 /*
 adjust will correct r to fall within the boundaries of ranger. If r does not
 overlap the current extend of ranger, a RequestedRangeNotSatifiable error will
-be returned.
+be returned. Ranges longer than span units are clamped, or rejected when
+RejectLongRanges is set. A span of 0 disables the limit.
 
 Range entities are always adjusted before they are passed to Ranger.Range
 implementer.
 */
-func (r *Range) adjust(ranger Ranger) error {
+func (r *Range) adjust(ranger Ranger, span uint64) error {
 
 	count := ranger.Count()
 	if r.From > count {
 		return RequestedRangeNotSatisfiable(&ContentRange{Total: count})
 	}
 	r.To = uint64(math.Min(float64(r.To), float64(count-1)))
+
+	if span > 0 && r.To-r.From >= span {
+		if RejectLongRanges {
+			return RequestedRangeNotSatisfiable(&ContentRange{Total: count})
+		}
+		r.To = r.From + span - 1
+	}
 	return nil
 }
 
@@ -175,8 +300,13 @@ ParseRange parses raw into a new Range instance.
 	ParseRange("items=39-")		// (OK)
 	ParseRange("bytes 50-100")	// (ERROR: syntax)
 	ParseRange("bytes=100-50")	// (ERROR: logic)
+
+An error is also returned when raw lists more than MaxRanges ranges.
 */
 func ParseRange(raw string) (*Range, error) {
+	if MaxRanges > 0 && countRanges(raw) > MaxRanges {
+		return nil, errors.New("too many ranges in Range header value")
+	}
 	m := rangeRe.FindStringSubmatch(raw)
 	if m == nil || len(m) < 4 {
 		return nil, errors.New("malformed Range header value")
@@ -222,3 +352,29 @@ func (cr *ContentRange) String() string {
 
 	return fmt.Sprintf("%s %d-%d/%d", cr.Unit, cr.From, cr.To, cr.Total)
 }
+
+/*
+JSONHeader returns the compact JSON encoding of v, suitable for the value of
+headers structured in JSON, like Report-To or NEL:
+
+	group, err := rst.JSONHeader(map[string]interface{}{
+		"group":     "csp-endpoint",
+		"max_age":   10886400,
+		"endpoints": []map[string]string{{"url": "https://example.com/reports?app=web&env=prod"}},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	mux.Header().Add("Report-To", group)
+
+Unlike json.Marshal, characters like & and < are not escaped.
+*/
+func JSONHeader(v interface{}) (string, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
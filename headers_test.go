@@ -41,7 +41,7 @@ func TestParseRange(t *testing.T) {
 func TestAcceptAdjust(t *testing.T) {
 	from, to := uint64(15), uint64(100000)
 	rg := &Range{"resources", from, to}
-	rg.adjust(testPeopleResourceCollection)
+	rg.adjust(testPeopleResourceCollection, 0)
 
 	if from != rg.From {
 		t.Fatal("from did not match. Got:", rg.From, "Wanted:", from)
@@ -86,3 +86,30 @@ func TestAcceptNegociate(t *testing.T) {
 	test([]string{"text/n3", "text/plain"}, "text/plain")
 	test([]string{"text/n3", "application/rdf+xml"}, "text/n3")
 }
+
+func TestAcceptEncodingNegotiate(t *testing.T) {
+	var test = func(header, expected string) {
+		if coding := ParseAcceptEncoding(header).Negotiate("gzip", "deflate"); coding != expected {
+			t.Errorf("%s: expected %q. Got %q", header, expected, coding)
+		}
+	}
+
+	// Highest quality value.
+	test("gzip;q=0.1, deflate;q=0.9", "deflate")
+	test("deflate;q=0.5, gzip", "gzip")
+	test("gzip, deflate", "gzip")
+	test("br, deflate", "deflate")
+
+	// Excluded codings.
+	test("gzip;q=0", "")
+	test("gzip;q=0, deflate", "deflate")
+	test("*;q=0", "")
+	test("*, gzip;q=0", "deflate")
+
+	// Identity and wildcard.
+	test("*", "gzip")
+	test("identity", "")
+	test("identity;q=0.5, gzip;q=0.8", "gzip")
+	test("identity, gzip;q=0.5", "")
+	test("", "")
+}
@@ -0,0 +1,263 @@
+package rst
+
+import (
+	"bytes"
+	"container/list"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StoredResponse is a response recorded by IdempotencyHandler. Accept and
+// AcceptEncoding are the headers of the request it answered, which the
+// representation in Body depends on.
+type StoredResponse struct {
+	Status         int
+	Header         http.Header
+	Body           []byte
+	Accept         string
+	AcceptEncoding string
+}
+
+/*
+IdempotencyStore is implemented by the storage backends used by
+IdempotencyHandler to record the responses to requests with an
+Idempotency-Key header.
+
+Implementations must be safe for concurrent use.
+*/
+type IdempotencyStore interface {
+	// Begin reserves key for a new request. It returns the response stored
+	// for key if there is one, and false if a request with the same key is
+	// still being processed.
+	Begin(key string) (resp *StoredResponse, ok bool)
+
+	// Complete stores resp for key during ttl, and releases the reservation.
+	Complete(key string, resp *StoredResponse, ttl time.Duration)
+
+	// Cancel releases the reservation of key, without storing a response.
+	Cancel(key string)
+}
+
+type storedEntry struct {
+	key     string
+	resp    *StoredResponse
+	expires time.Time
+}
+
+// memoryIdempotencyStore is an IdempotencyStore keeping responses in memory.
+type memoryIdempotencyStore struct {
+	mu       sync.Mutex
+	max      int
+	entries  map[string]*list.Element
+	lru      *list.List // Most recently used first.
+	inFlight map[string]bool
+}
+
+// NewMemoryIdempotencyStore returns an IdempotencyStore that keeps up to
+// maxEntries responses in memory. It's only suitable for services running in
+// a single process.
+//
+// Expired responses are removed whenever a response is stored, and the least
+// recently used ones are evicted when the store is full, since clients choose
+// the keys and would otherwise make it grow without limit.
+func NewMemoryIdempotencyStore(maxEntries int) IdempotencyStore {
+	return &memoryIdempotencyStore{
+		max:      maxEntries,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+		inFlight: make(map[string]bool),
+	}
+}
+
+func (s *memoryIdempotencyStore) Begin(key string) (*StoredResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, exists := s.entries[key]; exists {
+		if entry := e.Value.(*storedEntry); time.Now().Before(entry.expires) {
+			s.lru.MoveToFront(e)
+			return entry.resp, true
+		}
+		s.remove(e)
+	}
+	if s.inFlight[key] {
+		return nil, false
+	}
+	s.inFlight[key] = true
+	return nil, true
+}
+
+func (s *memoryIdempotencyStore) Complete(key string, resp *StoredResponse, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.inFlight, key)
+	if e, exists := s.entries[key]; exists {
+		s.remove(e)
+	}
+
+	now := time.Now()
+	for e := s.lru.Back(); e != nil; {
+		previous := e.Prev()
+		if !now.Before(e.Value.(*storedEntry).expires) {
+			s.remove(e)
+		}
+		e = previous
+	}
+
+	if s.max <= 0 {
+		return
+	}
+	s.entries[key] = s.lru.PushFront(&storedEntry{key, resp, now.Add(ttl)})
+	for s.lru.Len() > s.max {
+		s.remove(s.lru.Back())
+	}
+}
+
+func (s *memoryIdempotencyStore) Cancel(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.inFlight, key)
+}
+
+// Len returns the number of responses held by s, expired or not.
+func (s *memoryIdempotencyStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lru.Len()
+}
+
+// remove deletes the entry e from s.
+func (s *memoryIdempotencyStore) remove(e *list.Element) {
+	delete(s.entries, s.lru.Remove(e).(*storedEntry).key)
+}
+
+/*
+IdempotencyHandler returns a handler that makes POST requests to h safe to
+retry.
+
+The first response to a POST request with an Idempotency-Key header is
+recorded in store for ttl, and replayed to the requests sent with the same
+method, path, and key. A request arriving while the first one is being
+processed receives a 409 Conflict error, and a retry whose Accept or
+Accept-Encoding header differs from the ones of the recorded request, and
+which would expect another representation, receives a 422 Unprocessable
+Entity error.
+
+	store := rst.NewMemoryIdempotencyStore(10000)
+	mux.Handle("/payments", rst.IdempotencyHandler(rst.EndpointHandler(&PaymentsEP{}), store, 24*time.Hour))
+
+Responses with status code 5xx are not recorded, so that requests can be
+retried after a server failure.
+*/
+func IdempotencyHandler(h http.Handler, store IdempotencyStore, ttl time.Duration) http.Handler {
+	return &idempotencyHandler{h, store, ttl}
+}
+
+type idempotencyHandler struct {
+	handler http.Handler
+	store   IdempotencyStore
+	ttl     time.Duration
+}
+
+func (h *idempotencyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	idempotencyKey := r.Header.Get("Idempotency-Key")
+	if idempotencyKey == "" || strings.ToUpper(r.Method) != Post {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	key := strings.Join([]string{strings.ToUpper(r.Method), r.URL.Path, idempotencyKey}, " ")
+	stored, ok := h.store.Begin(key)
+	if !ok {
+		writeError(Conflict(), w, r)
+		return
+	}
+	if stored != nil {
+		if stored.Accept != r.Header.Get("Accept") || stored.AcceptEncoding != r.Header.Get("Accept-Encoding") {
+			writeError(NewError(
+				http.StatusUnprocessableEntity,
+				"Idempotency key reused",
+				"The Idempotency-Key header was sent with a request accepting other representations.",
+			), w, r)
+			return
+		}
+		replayResponse(stored, w)
+		return
+	}
+
+	recorder := &recordingWriter{ResponseWriter: w}
+	completed := false
+	defer func() {
+		if !completed {
+			h.store.Cancel(key)
+		}
+	}()
+	h.handler.ServeHTTP(recorder, r)
+
+	if resp := recorder.response(); resp.Status < 500 {
+		resp.Accept = r.Header.Get("Accept")
+		resp.AcceptEncoding = r.Header.Get("Accept-Encoding")
+		h.store.Complete(key, resp, h.ttl)
+		completed = true
+	}
+}
+
+// replayResponse writes resp in w.
+func replayResponse(resp *StoredResponse, w http.ResponseWriter) {
+	for key, values := range resp.Header {
+		w.Header()[key] = values
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(resp.Status)
+	w.Write(resp.Body)
+}
+
+// recordingWriter is an http.ResponseWriter that keeps a copy of the response
+// written to the embedded http.ResponseWriter.
+type recordingWriter struct {
+	http.ResponseWriter
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (w *recordingWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+		w.header = make(http.Header)
+		for key, values := range w.Header() {
+			w.header[key] = append([]string(nil), values...)
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements the http.Flusher interface.
+func (w *recordingWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *recordingWriter) response() *StoredResponse {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	return &StoredResponse{
+		Status: w.status,
+		Header: w.header,
+		Body:   w.body.Bytes(),
+	}
+}
@@ -0,0 +1,94 @@
+package rst
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyHandler(t *testing.T) {
+	count := 0
+	mux := NewMux()
+	mux.Handle("/payments", IdempotencyHandler(postFunc(func(vars RouteVars, r *http.Request) (Resource, string, error) {
+		count++
+		return Text(strconv.Itoa(count)), "/payments/" + strconv.Itoa(count), nil
+	}), NewMemoryIdempotencyStore(100), time.Minute))
+
+	var test = func(key string, body string, replayed bool) {
+		r, _ := http.NewRequest(Post, "/payments", nil)
+		if key != "" {
+			r.Header.Set("Idempotency-Key", key)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != http.StatusCreated {
+			t.Fatal("status code. Got:", w.Code, "Wanted:", http.StatusCreated)
+		}
+		if w.Body.String() != body {
+			t.Fatal("body. Got:", w.Body.String(), "Wanted:", body)
+		}
+		if location := w.Header().Get("Location"); location != "/payments/"+body {
+			t.Fatal("Location header. Got:", location, "Wanted:", "/payments/"+body)
+		}
+		if got := w.Header().Get("Idempotent-Replayed") == "true"; got != replayed {
+			t.Fatal("replayed. Got:", got, "Wanted:", replayed)
+		}
+	}
+
+	test("a", "1", false)
+	test("a", "1", true)
+	test("b", "2", false)
+	test("", "3", false)
+	test("", "4", false)
+	test("a", "1", true)
+
+	// Retries accepting another representation are rejected.
+	r, _ := http.NewRequest(Post, "/payments", nil)
+	r.Header.Set("Idempotency-Key", "a")
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatal("status code. Got:", w.Code, "Wanted:", http.StatusUnprocessableEntity)
+	}
+}
+
+func TestMemoryIdempotencyStore(t *testing.T) {
+	store := NewMemoryIdempotencyStore(2)
+	if _, ok := store.Begin("key"); !ok {
+		t.Fatal("expected first Begin to succeed")
+	}
+	if _, ok := store.Begin("key"); ok {
+		t.Fatal("expected Begin to fail while the first request is in flight")
+	}
+
+	store.Cancel("key")
+	if _, ok := store.Begin("key"); !ok {
+		t.Fatal("expected Begin to succeed after Cancel")
+	}
+
+	store.Complete("key", &StoredResponse{Status: http.StatusCreated}, -time.Second)
+	if resp, ok := store.Begin("key"); !ok || resp != nil {
+		t.Fatal("expected expired response to be discarded")
+	}
+
+	store.Cancel("key")
+
+	// Expired responses are swept, and the least recently used ones evicted.
+	store.Complete("expired", &StoredResponse{Status: http.StatusCreated}, -time.Second)
+	for _, key := range []string{"a", "b", "c"} {
+		store.Begin(key)
+		store.Complete(key, &StoredResponse{Status: http.StatusCreated}, time.Minute)
+	}
+	if n := store.(*memoryIdempotencyStore).Len(); n != 2 {
+		t.Fatal("Entries. Got:", n, "Wanted:", 2)
+	}
+	if resp, _ := store.Begin("a"); resp != nil {
+		t.Fatal("expected the least recently used response to be evicted")
+	}
+	if resp, _ := store.Begin("c"); resp == nil {
+		t.Fatal("expected the most recent response to be kept")
+	}
+}
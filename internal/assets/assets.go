@@ -122,86 +122,86 @@ var _esc_data = map[string]*_esc_file{
 
 	"/internal/assets/error.html": {
 		local: "internal/assets/error.html",
-		size:  42463,
-		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\xe4]ݓ۸\x91\u007f\u07ff\x82\x99M.\xeb\xb5ġ\xbeG\x92=\x95\xdc&W٪l.\x95\xf8\x1e\xae6~\xa0HHbL\x91\nI\xcdث\xd2\xff~\xf8F㋤4㼜\x9d\xac%\xf4\x0f\x8dFw\x03݄\x00\xf0ݯ\xfe\xf0\xdf?|\xf8\u07ff\xfe1\xd87\x87\xfc\xf1\x9b" +
-			"w\xe4\x9f \x8f\x8b\xdd\xfb;T\xdc=~\x13\xe0?\xef\xf6(N\xd9G\xfa\xb5ɚ\x1c=\x9e\xcfA\xf8\xf7&nN\xf5\a\xf4\xb9\t.\x97w\xf7\x8c\xa0\x80\a\xd4\xc4A\x11\x1f\xd0\xfb\xbb\xa7\f=\x1f˪\xb9\v\x92\xb2hPѼ\xbf{\xce\xd2f\xff>EOY\x82\x86\xf4\xcb Ȋ\xac\xc9\xe2|X'q\x8eޏ\xee\xdc" +
-			"̪rS65`U\x94Y\x91\xa2σ\xa0(\xb7e\x9e\x97ϰb\xdd|\xc9Q\xd0|9\xe2\x9a\r\x16\xf5>\xa9k@'\u007f\xee\xbf\xff\x95\xf6\xfd\xfb\xe0?K\xdcBS\xc5\xc7\xe0i\x12N\xc2Q\xf0ݾi\x8e\xab\xfb\xfb\x1dj6\x82\x16&\xe5\xe1\x8dQ\xf1\x87\xf2\xf8\xa5\xcav\xfb&\x18G\xa3\xd1\x10\xffg\x16|x\xce" +
-			"\x9a\x06U\x83\xe0\xc7\"\t\r\xfc\x9fq\xef\x8b\x1a\xa5\xc1\t\xf7\xa0\n~\xfa\xf1\x03k\xaa&me\xcd\xfe\xb4!\xad\xdc7ϛ\xfa^6|\xbf\xc9\xcb\xcd\xfd!\xae1\xd7\xfb?\xff\xf8\xc3\x1f\xff\xf2\xf7?\x1a\x82\xdck_\x89Y\xcf[\xac\xae\xe16>d\xf9\x97U\x1d\x17\xf5\xb0FU\xb6]\x0f\x0f\xf5\x90\xe8eXg\xbf\xa0a\x9c" +
-			"\xfe\xf3T7\xabQ\x14\xfdf=|F\x9bOY\xe3\xa6^6e\xfa\xe5|\x88\xab]V\xac\xa2K\\5Y\x92\xa3A\\g)\x1a\xa4\xd8XY^\x0f\xb6\xd9.\x89\x8fMV\x16\xe4\xe3\xa9B\x83-\xee\x03V\x05q)\xf2Ϯ*O\xc7\xc1!Ί\xc1\x01\x15\xa7A\x11?\rj\x94\xd0\x1a\xf5\xe9\x80\xd9\u007f9\xa7Y}\xcc\xe3/" +
-			"+\xdc\xe9\xe4\xd3%>\xa5Y9H\xe2\xe2)\xae\aǪ\xdcU\xa8\xae\aO\xb8\xd5R\"\xb3\"\xcf\n4\xa4\x15\xd6O\x88\x88\x86\x9d*γ]\xb1\xda\xc45\"T\xc6hU\x94\xcdw?\x13G\xaaʼ\xfe\xf8F\xb2(\xca\x02\xad\xf7\x88X\x12\xf7\xee\xe7}\x96\xa6\xa8\xf88h\xd0\x01\x93\x1b\xa4\xe1.\xf1y\x13'\x9fH_\x8a" +
-			"t\x98\x94yY\xad\xb0\x99\x8a\xfa\x18W\xd8?/\xf1*\xc6=z\xc2\xcaY\xedK,ι<5D\x04\xa2\xb6ͦ\xfa\x99\x8e\x9b\x8f\xe7MYa\x9d\f\xb1o7\xe5a5:~\x0eR\xfc\x11\xa5\x97\xcd\x00[\xbd,v̂\xcfL\xa8M\x99\xa7\x97t[\xb0B\xea䫬\xc1}L.\xfb\x11/\xc4&[\x8d\xd1a-\xac\x14" +
-			"\xce\x17\xe8\x10D\x17\xfc\xfd\x13\x10y\xf5\xedv\x1b\xad\x99\xdc\xdfFQt\xa9\x0fq\x9e\x03\x1e\x0f\xd8\xdc\xf5\t\x8bq:\x82\xd2\xc5\xec7k\xaag\xa1\xa6\xf5\xb1\xac3b\xbaU\x85\xb0\x92p\x8f\xbd\xca'\x9c\x9a\xf2\xb8\x1aF\xe1\f\x1d\b\xf33\xef7.\x19\x93\xa2\xec\xb0\xe3\x1a\xc1j\xaa\x9fv\xd4R\xab\n\xbbϛ3Q\xe2\x16\x0f" +
-			"\xf3\x153˅\xf9\x96p\xc6\x11\xee\xe34:~\xbe\xec\xab\xf3\xf0P\xfe\x825\xfa\x99H\x9c\x15\xbb\x15\x9f2H\x91to\x0f\xd9S,}\xe2\x88[\x94\x82ħ\xa6\xbc$%v\xfdO\x9b\x14\xbb%\x1a\xd4\xf1\xe1\xa8\r\xb9CY\x94\xd8#\x124\b\xe4ǵ\xd2&\x96\xfa\xb29a\x15\x14\x83\xac8\x9e\x9aAyl\xd8\xe8\xc0*\xc3" +
-			"#b@F!\xf6\xa7\xf8\xcc\f\x95\x15{<|\x1b\xcaA~\x91ÑqR\xf2=eu\xb6ɑh\x81\xb1<ӁM=u[V\a\xe6\xcb\x1cA\x03\x01\x15\xe4g6u\xb2\xf2\xbb\x8f\x03X\x88\xc7\x1ej\x8c2l\xcbC\x86\v\xcfB\xbf\xf1\xf1\x88b\xdcH\x82V\x8c\xc9:9U5\xee\xc2\x11O\xdbx2\xe0M\xfe\x8c\x87" +
-			"T\x8ceL?\xc2\xc6e\xe1\x99WJ\xd16>\xe5\r\xaf\xb4ZQ\vo\xcb\xe4T\x0f\xb3\xa2\xc0s\n\xadg\x97KgZ\x1f\xe34%V\x8d.\x14z\x86>\\`=\xc4\xf9\x05\xf6'٣\xe4\x13\xb6\xbc\xd9\xf5\x18O\x1f\xa0\x97\xc0]\xe4@\xc6>f\xf8\x1f \xb9K\r\xe1xc\xc5\xe9\xb0A\xd5\xddG\xdc-\xde\x1c\xedӰ" +
-			">f\xc5\x10z\x8d\x17\x8f\xa7\x1c\x1d\u007f\xe6=\xa6\x9e\xab\xd9\x0f\x1b+ٻ\xedG\x1cf\x9b\xa1<\xb5:\xf6\xe2\x81\xe5\x94A\xc9\xcfJ\x86\t\x11#wu\xd9[%EIY\xc5dZr\xf5\x88\xfa<\xed\x12\xf6d\xe1#d\xee\xad\xcb<K\x83o\x93\x88\xfc\x95\x03+\x18\x1f\x81\x8d\xc2Ɍ̧\xe1|\xcc\xfe]\x90\x89+G;" +
-			"T\xa4.w\x93#X\x9f6\xc4@\xb7\xa7\xf7\x86\xb8\xbe\x88\vx\xd8\xe7\xf1\xb1F+\xf1a\xcd\td&\xe1\r\xa4\x83f\u007fV\r~\xff\xca\xdey\xf9~\xb5Ax\xaa@\x83\xefW\xf1\x16;\xd4k\xf3Wy\n\x9b\x13\xf1,\xae\xb2\x90\xf88\xdcc\xdd\xe4D?<\xcaV\xbbM\xfc]4\xa0\u007f߰\x8c\x04N\xb9w\u007fB\xf9\x13" +
-			"\"\x01(\xf8\v:\xa1\xbb\x81\xfc>\xf8}\x85\xf3\xcc\x01H\x83@\xabS\xdc*\x9c\x14F\xe1t\xfc0[\x8c\xa6\x13\x11$'\x93\xc9\xda\n\xf98\x8an\x99\x17\x0f\xb4iV\xcd\xdcP68\u007f\xb3vE\tl\x9a\x97]Ĭ\x8f[^ěŚ\xce\xdcʳy\x1a\xc2\xd2\v\x9cf\xd0yOT\x19Of\xe3EbU\xa1\t'" +
-			"˄8^\xa4%\xcd>+x\xee\xb1\x16e3<$\x88\xb7\x06\xc2\x1clj\xad\xb0\x01Y\xf7\x05rXn\xb7x(\xad\x86x\xa4\x18\x819\xa2A\xddH\t\x0e8\x84\xe3\xc0\x14b\xd2\x10\x87\x93cY\xd48qГ><\xfc>\xb3G\x04\x96\x98©\x8bU$f@b\xd0\r\xc9\xdc|\xaaWs,\x01%\xe34\xfa\xb0)p" +
-			"F*\xc7F\x8b\x89\x9dv]\xdb3C\x9a\xa6k\xbd\xbd)\xf4V\x12UY2\x84s\xa9 \x1c\xd7\x01\xc2\xc9\x0f\x9e\xb7\xc9T\xbc\x1e\x96]\x88\x0e\xb23\xd5\xedRS\x92U\x89\x9aP\xb8\xd43\x9c\xdc\xe1L\x89\xd9hH\x92\xb21\x19v\xfc;\xcf\xcah\x91\x9c\xd28\x03\x82\x05\nA\b\x9b\xb1\xae\x86e\x91\u007f9\xcbT0\xde`2" +
-			"\x0e>k.\xd8Q\xa6P#\xd9\xcaj8\x82\xd3\xea\xda\xc8\xef\xd6I\x9e\x1dqN\x994x\xac\a\xfc\u007fo\xa48\xb2Q\xe6\x93d\xce\x14\t\xb7\x83\xc2\\]\x8aW\xe3G\xd8,\xe1\xc2\x11MA\xad\xc9i\u007fm&RL$\xaa\xd9\xfdh\xb0\x1f\x0f\xf6\x93\xc1~:\xd8\xcf\x06\xfb\xf9 \xc4E!.\vqa\x88KC\\\x1c\xee\xe7" +
-			"\xfe\xe1\xcf\xe7\xfcY\x14\x19N9Zk\xc9\x1en+\xa0\xb99nQ|\x98\x88\x0fS\xf1a&>\xcc\xf9\x87PV\ve\xbdPV\fe\xcdPV\re]\\5\x94M\x86\xb2\xcdP6\x1a\xcaVC\xd9l\xa8\xda\rUáj9TM\x87\xaa\xedP5\x1e\x82G\x90g\x98\x9a\xe9\n\x12\xf3\xf1b\xb1\xb8P\xa5S[\x84\xcc\x1e" +
-			"\xb8\xa1\x0e\xaf\x1eѧ\x84\x91\xa5&\xa0%K\xcfJq\xb0w\x0e-\x85.\x85\xa9\u038307\x9f\xe1\x118\xa5\xaeB=\x85;\xd1\x1cJ?\xf2I?\xb5\xcc\b\xac\xe8p\x85y`\x9a.tY1t\x1btnK\xbf \xd2S݃\xc2\t\x99{\x99)`)\x95\x98Y\x06<\xa1Ni?\x88\x1c0\xf6?\x90R\xaa\x8e\xb3\x1e\x9b" +
-			"/\\;\xa0\x94Ě\xa3\f3A\x14P݄9\x8aӳc\"\x035\xe7\xe2+\xf7\xb2\x895\x06\xa7\x97\xdf\x1dP\x9a\xc5\xc1w\ăM\x14\x8b9\x96\xee͙5\x00z\x82\xa7\xb1˅\xeb\xcaz\x88\xc6z\"\x0fރ\xd0x\xfc\x96a&\xd9>\xa0\x89\x9c\x06C\xfc\xe4~\ti\xd4\xceі?\xac\xb1\x98I\xbes\x12]" +
-			"k\x824Z\xc0\x89\t\"\xcfW\x90\xcaJ8\x99\xac\xe5d\xdb/\x90\u038b8\xa0(\x9f\xab\xf8x~\xdeg\r\xa2i&ɗI\x91\x90\xab|FU\x82\xa3\x92\xf9$)\t\x1cx\xc2\t\xb7\x13(\tB\xe2\xf8H\x171~\xb1\x90\x8a¡\a\x1cT\xd23\x98\x00X\xf1\xb1\xca誑\x96/]b\x8dȗaD~\xf40\x8f" +
-			"\x96\x11\xaf^\x9f\x92\x04\xd52w\x9a$\x8b\xf9$\x15\xd59Ѩ\xbe\x99M\xc7\t\xaf\x9e\x15\xdbR\xd6\x1d-\xa2\x87\xad\xa8K(F\xc5\xe9l<_\xf2\x8a\xcfqU`\xab\v\xdaC<O'\x1bQ\x97\x13\xf5\xea\xf3\xf9l$\xdbM\xe3b\xa7H\xf1r:\x9d\x8eEmF\xd3+?L'\xb3\xc9\xf4\x12nv\xa6\xc2h\xb2c\xf9\xa6" +
-			"T\xa3\xaa\xc0\x19\xdaX\xa1O\f\x15ڴA\xe9v\x1b\xa5\x0f\x8c\xa1\xaeV\x1b\x9b\x8c\xd0x3\xa1\f\xa9~\x1dܖ(\xddr\xf1\x80\xa2m`\xbc\xc5PDY\t\x8d\xfb\x06\"㦫ށ]\xa0d3\xa3\f\xb9\r\x1c\x981\xceM\x11\xe3\xa7\x19Æ\xa2\xe9f\xb9\xc1>q\x8cwd\n\"\x8b\xa4\"g\x153\xd8R\xa5M" +
-			"d\x95\vOv 9\x83\xab\x87 -;\xe5\x832\x87\xe1$rŒS\x1eP \xf9/\xfe\\\xd2Ϫ\x1e\x87b\xcb\xe6Y\x8d\xc7tA\x17\x1dS)\x1f\x99\x95Vd\xf6\xac\xc5z$}*ah\x96\xa7v`\x85P\x94:\x9c\xd1)\\U~\xcc3\xf7\n\xaf\xc6t\xa62I69\x92\x92K\xda\xda{\xa2\xc0K\xda\f\xd2\xf4" +
-			"\xec~(\xc0D\xc7⫌-\xac7-q\"͇\xfb\xb2\xca~\xc1,\xe2< \xcc\xf22n\xe8<.\xf2\xe291\"\xce\xce\xe3\x8a\x15\x9bS\xba\x95\x14S\x80,D9NH\xeb\xac^\xbb&k\xa3y]\xee\xd1\x03\xe9=\\\x89\x1e\xd0\xcfi\xdc\xc4C\\\v\x03\xf1\xc3\x1a_\xa3\xe6\x8bp{\x94\x1f\x1d\x1eǞ\x19\x036\x1b" +
-			"\xf3\xdfr\xb2\xfa\x00\x82\xe0\x12?\x96xC\x00\xb5\xe6\xbfNe#\xfd\x84\xfae\x0027\x1a\xdc\xcd\x18\xbe\bgj\x00\b'\x80\xee\xaf\x18\a\xc7U\x1ec\x8fJ\xf6Y\x9e\x0e@\xf9)\xf7\x10JH\xb0\x86\x02\x00\xf2_6@\tK\x04@\x01\xcf\t\xf4g[m\x99\xbdc݁(\xd6jR\xacɘ-;\xcaCH8\xf3շ" +
-			"\xd5o\xff1\x8eF\xd3\xe0\x1fQ\xf4\xfb\xe8\xb7x*\x93x\xfc@\x8e\xfd\xab\x86,\xc2\xe3)\xcfy֡\x0f\xb3\x11\x1cy||\x8b'M1\x0e\x81Q4{E\x96\xbb\xbb\xc4\xf0\xf6\x17\be`\\\\<ʁL4\x88\x8bG\u0603\x89G\xd9N\r\v\xb1\xe9\xaaZ{\xcf\x18\xc4߱6\x16\x10\xd1ҭ6\x16\x10\x02<\x88\xf8" +
-			"N@\xfd\xe8\xb7\x17\xec\x03\xe4G9\u007f\xe6\xcd'|\xfbyNM\xb8\xed\xbf\xa5\xfc\x84\x8a\xbc\x1c\xfcT\x16qR\x0e~(\v\xecVq=\xb8\xfb\xa1<U\x19\xaa\x82\xbf\xa0绁\xfc\x91\x85\xf2\x923\n~T\b\xa6\xda\xfcA\xe6$\x91i,Ƴ)r-\x01-\xb7\xe3\xed\xd4^\xef\xb9`\x11\xfb\xb1\xf6\xe5U\x13\x83\xe9\xe4h" +
-			",\xa0\xef\xe3\x14\xcf\xefYQ\xa3\x06\xcf}d\xa5\x04\xff\x03\x17>\xc3\xf1\xecͺ?\x94\x88\x1c@\xb1#8\x97\x92u#3ҹ\xe4\xa1\xe1\xda\xf8N\u007f\x12\xd3'7\xd1ƒN\xd1\xc6\x03\x1alw\xe2_\x90{\xc6\xfa\x19n*\x14\u007fZ\xd1\xff\xe2i\"g\x85$\xba\xf12\xf2\xbdcuvF\xfe:\x16\xf2\x92$q\x18\x16\xf7" +
-			"%\xd0\x1c'r,\xd5\xea?\xc5\xc1Ћ\xabS\xf1lA\xc0/\xc3F\xb38\xb3\"\xd5\xea\xa4\"+\xfcd\xe9\x9f,\xe8q\x85LH\xb2'\x93\x80\xe1\x97\x15\x83]B2\x06\xe3\x8c\xfc\xc2\xc5\xc7[e\xae_\xb19\x96\x16h\xf3\xf3\xc8ΕHQK\x1e\xa3\xda\xe2\xe53\x9a;\xd8\x15\x96˱\xb3\xc2r\xe1\xa90\x1aG\x91\xb3\xc6" +
-			"hĪ(\xc2p\x9b\x9f\xb2\xf4\xd5z\x1bV峖\x10\rG\xcaW9pȐ\u0600\xc3\xcf\xf5p4\b\xe8\xc7\xfa ?\x1eR\xf91\xdfɏ\x18;Vر\u008e\x15v\xac\xb0\x13\x85\x9d(\xecDa'\n;Uة\xc2N\x15v\xaa\xb03\x85\x9d)\xecLag\n;Wع\xc2\xce\x15v\xae\xb0\v\x85](\xec" +
-			"Ba\x17\n\xfb\xa0\xb0\x0f\n\xfb\xa0\xb0\x0f\n\xbbTإ\xc2.\x15v\xa9\xb0\xa3\b\x18#\x02\u0588\x809\"\x80\x87ƃփ\xe6\x03\xf6\x1b\x01\x03\x8e\x80\x05G\xc0\x84\xa3\xf1\xd9\xde\xf9@\\\x1b,t\xf7sE\xc3\xc1\x80\xff\x00\xf7\x00\xd6\a\xc6\x05\xb6\x03\xa6\x01\x9a\a\x8a\x85z\x83:\xa1\xfd\x05\xcf&\x17P\xaa~WP\xa5#" +
-			"1\xaaG\xe1\x9c\xfdY\x00jĩ\x0f\x93p\xc2\xff(\xeaR\xce \xaa쁗\xcd\xe7\x0ev\vN\x9c=8\xb8\xcd\x05\x11H7\xe3eS\x97pSN\x9c\xb8d\x9bp\xe2\x18\xc8&\x15\xe0\x92M\xe8\xc1%\x1aM\x9d\xb0\xfe\xb8\x99\xa1\xfe\x18i\xc4IN%2H\xc4!NMRȒ#\xa0:)\xe1\x81\x13\x9c:\xa5\x88\x05G" +
-			"8\x15K\x11s\x810e\x9fq\x82S\xc5\x141\xe5\b\xa7\x9e)b\xc2\x11cSr\xa92\xaf\xe4Bs^\xc1\x85\xde\xd8/`\x92R\xef\x89A\xd8H\xd4\xedA(#F\xf1\x98\x83 \"\x86\xf0X\x03#\x96\f\xa0\x1b\x03\x97?\xb0r\x8f-0`\xc1\x00\x1eS`\xc0\x9c\x03L\xa9g\xac\xdcc\b\f\x982\x80\xc7\x0e\x180a\x80" +
-			"\xb1)\xb3P\x94Wf\xae/\xaf\xc8\\[\x9a\r\xd8o\xc4\xc4\n\xdaJ\x044\x86\x80\x8c4\x88\xd3*\x02\x1aiP\xa7y8t\xa9!\xa1\x9d8\xe0A\x038\rƑ\v\r\xe9\xb4\x1cG\xceu\xa4\xddי\x06pڒ#\xa7\x1a\xd2iT\x8e\x9chȱ\xddS\xc3\x04-=\xd5-\xd1\xd2Ѩ\xf7\xba\x98\x91E\x81$\t\xe4@" +
-			" \xc5\x01\x19\fHP@\xfe\x01\xd2\v\x90=\xc0\xdc\x00\xc6}\x1aӭx\xc7J\xcdxG\xeby\xe3\x1dm\xc1\x1b\xef\x88(f\xbc#\x92z\xe3\x1d\xe9\x917ޑ\x9e\x9b\xf1\x8e(\xc6\x1b\xef\x88\x02\xbd\xf1\x8e(ڌw\xc4\x0e\xdexG\xba\xea\x8bw\x98\xe6\x8bw\x92\xe4\x8fw\x12\xe2\x8fw\x02b\xc5;A\xf0\xc7;\x81\xf0\xc7;" +
-			"\x81\xb0\xe2\x9d \xf8\xe3\x9d@\xf8\xe3\x9d@X\xf1N\x10\xfc\xf1N\xea\xc5\x17\xef\x04\xc0\x8ew\x94\xe2\x8cw\x92\xe2\x8dw\x12\xe1\x8dw\x02a\xc6;Q\xee\x8dw\x02\xe0\x8dw\x02`\xc6;Q\xee\x8dw\x02\xe0\x8dw\x02`\xc6;Q\xee\x8dwR\x1d\x9ex'\xe8V\xbcÄ\xaex\a ]\xf1\x0e@\xbb❂z\xe2\x9d\x02t\xc5" +
-			";\x85\xec\x8aw\n\xe9\x89w\n\xd0\x15\xef\x14\xb2+\xde)\xa4'\xde)@W\xbc\x03\xfam\x8fw\nhƻ\xd6\xf5\x10m\xad\x00,\x05\x80'}\xf0 \x0f\x9e\xd3\xc1c8x\xca\x06\x0f\xd1\xe0\x19\x19>\x00Ç[\xfa\xe0j\x05<Vj\x06<Z\xcf\x1b\xf0h\vހGD1\x03\x1e\x91\xd4\x1b\xf0H\x8f\xbc\x01\x8f\xf4\xdc\f" +
-			"xD1ހG\x14\xe8\rxD\xd1f\xc0#v\xf0\x06<\xd2U_\xc0\xc34_\xc0\x93$\u007f\xc0\x93\x10\u007f\xc0\x13\x10+\xe0\t\x82?\xe0\t\x84?\xe0\t\x84\x15\xf0\x04\xc1\x1f\xf0\x04\xc2\x1f\xf0\x04\xc2\nx\x82\xe0\x0fxR/\xbe\x80'\x00v\xc0\xa3\x14g\xc0\x93\x14o\xc0\x93\bo\xc0\x13\b3\xe0\x89ro\xc0\x13\x00o\xc0\x13" +
-			"\x003\xe0\x89ro\xc0\x13\x00o\xc0\x13\x003\xe0\x89ro\xc0\x93\xea\xf0\x04<A\xb7\x02\x1e&t\x05<\x00\xe9\nx\x00\xda\x15\xf0\x14\xd4\x13\xf0\x14\xa0+\xe0)dW\xc0SHO\xc0S\x80\xae\x80\xa7\x90]\x01O!=\x01O\x01\xba\x02\x1e\xd0o{\xc0S\xc0\x1e\x01\x0f\xac\xe7kK\xe2`\xc5\x1b,h\x83\xf5j\xb0\x1c\rV\x9b" +
-			"\xc1b2X+\x06K\xc1p\x99\x17.\xe1\xb2\xe5Y3\xe2\xb1R3\xe2\xd1zވG[\xf0F<\"\x8a\x19\xf1\x88\xa4ވGz\xe4\x8dx\xa4\xe7f\xc4#\x8a\xf1F<\xa2@o\xc4#\x8a6#\x1e\xb1\x837⑮\xfa\"\x1e\xa6\xf9\"\x9e$\xf9#\x9e\x84\xf8#\x9e\x80X\x11O\x10\xfc\x11O \xfc\x11O \xac\x88'\b" +
-			"\xfe\x88'\x10\xfe\x88'\x10V\xc4\x13\x04\u007fēz\xf1E<\x01\xb0#\x1e\xa58#\x9e\xa4x#\x9eDx#\x9e@\x98\x11O\x94{#\x9e\x00x#\x9e\x00\x98\x11O\x94{#\x9e\x00x#\x9e\x00\x98\x11O\x94{#\x9eT\x87'\xe2\t\xba\x15\xf10\xa1+\xe2\x01HW\xc4\x03Ю\x88\xa7\xa0\x9e\x88\xa7\x00]\x11O!\xbb\"\x9e" +
-			"Bz\"\x9e\x02tE<\x85\xec\x8ax\n\xe9\x89x\n\xd0\x15\xf1\x80~\xdb#\x9e\x02Z\x11\x8f\x1f\xcdk;\xf6\xcdO\xbe\xcbmRd\xe7\xe1\x03\xf8\xe1\x8f\xef\x8b!E\x89\xdc\u07b56\xb7y7{\xc7\xceo\xda88\xe9c\x1c\xfcq\xecndu\x1e\x1bz\x97CS\xe1\x0f\x03QD\x8e\xcd\x19Ed\xab\x91Q$+\xa6v\xc5\xd4" +
-			"\xae\xa8\xb6\x97<\xf8wv\x18G\xc1\xb0\x82<G\x8b\xd24u\xf4\xc0<J\xc6\xfak\xecK\x1c;\xb9pۼ\x15\xdcV۬\x12\x9b\xfc@\xaf\xb1]\xe8\xd1\xcc.\x1c%\xeb4/\xcb֖Ӟ-\xa7\xfd[\x96\xa7\xe2\xe8\xce\xd7\v4\xde[\xfa_Hwj+\b=\xdeNO<2\".)Rz\x93\x85\xc3\xc7 \xd1\xf26" +
-			"H\xb4\xfc\xce\xc96mc\xeb\":\xbcr&\xc7Đu_\x9e\x1et\xfb\x9dD\xb9\xba\xa7hv\xef\x14\xcd\ue703g\xda\xc2\xd3A\x03={\x05\xe9\x95\x14\xfae\x14|n\x19+\x9d\xd5M\x95\x1d\x81p\xab\xa2\xd93\x87\xfb\xaeL\xd37.WY\x92\xbf\xa2>\xdd\xff\xaej{w\xd7\xd3=[l\xb2\rp\xd9\xcfI\x1e\xd7\xf5\xf7" +
-			"\xef\xef\xc8\xec|\xf7\xd1:\xc6\xc7\xf2|\xba7M\xecC\x13ΐ\x9f\x0e\x05gԤ\x06\x9f\x01/\xdf\xdf\xce\x1f\x91\xadX\xd6T\x19\x8as\x88\u058ciR\x94!M\x8a2\x98\x97\x9bEQ\xae\xe6\xe1Ƌ\x1d\xb3\xb9\x83¹9(&7+\xa88(&\xb7\xbd\xdf\xeeNgQ*\xe2ǝ=\xa8}\x0f\x94\x06\x01\x03\xcc垏" +
-			"\xba*\xdb9\xb9:\x85\x1e\xc8_\x97\x97\xf0\x030.71I\xc0OL\x12p\x14/C\x9b\x04\\\xc5\xc3P\x94\xbb\x9c\xc5A\x12\xf6u\x90,\x86\xb6\xbf8H\x16C\x97r\xf9y\"\xaf\xc7hg\x8c\xfc.\xd3\x03\xa6c:\x9dFWj\a/g\xcf\"\xb4L\xe6.\xb7!'\x9d\\>\xa3\x95\x03\x87\xd1ʁ\xb7\xb8\xf9\xec=|\xf6" +
-			"N>\xb4\xd0\xe5!f\xb9\xb0\xa6Y\xae\xf3\xb1\x1d\xc3,\xd7\xf98\x15\xc7\x0e\x85y]B\x1d\x14\xf3\xfbC\x17\x06\x00:=\x01\xa8\xad\x8d\x8b\xab+\xc9\x14M\xb6\x13\x97\x0f\xf0\xf3i.70I\xc0\x13L\x12p\x06/C\x9b\x04\\\xc2\xc3P\x94\xbb\x1c\xc3A\x126u\x90,\x86\xb6\x878H\x16Cg\xb0a\xc7\xfd\xbc~\xa2\x1d\x01\xf4" +
-			"\xbbJ\x0f\x98\x8e\xe9t\x18]\xa9\x1d\xbc\x9c=\x8b\xb7\xe3$q\xb9\r;\x86\xe8\xf2\x1a\x83\x02\x9cƠ\x00\x9f\xf1q\xb3(\xc0c\xdc\xdcx\xb1\xcb_l\x8a\xb0\xaeM1\xb9\xd9\xcebSLnN\x85\xb2\x93\x9c^W\x81\xa7;\xfd\x9eҍ\xd2 \x9d~\xa2\xa9\xb2\x9d\x933/\xd9$\x89\xf4\x12x\a\x8b<l\xf0\x99\xef\xafW[\x9a" +
-			"\xa30\x1a\xfdF,\xfb\xd7I\x85P\x11\xc4E\x1a|\xa7V\"\x16\xf3\x05\xfd\x01\xc0b\xeb]\xa8\xa0ۢ\xc1\t\a~Б\\P(K\xd9\xd9!RDD\xc2\b\xb2\x92\u008eBl\xe2\xca}M\x8bݵG\xfeDk\x9dl\xf5\x00]\x8fM\x0e\x90\xfd\xf4\xe7\x00ُ\x81mͥ}\x9ak\x03\x81'D\xe79~w=\xeb\xa1" +
-			"د\x1b\xe7\xb3%\\\x82\xf0\n\xe7|n\xbe\xb6\xa6R\xe7\xb55\x95\x8eo\x96\xf6\xea\x9a\xca\x1a\xb0\xe6Y;\vy\xa5\xa6\xc1\xb1\xd5\xeb\x14}]E\xa0\xe7\xeb*\x025\xdf(\xea\xb5\x15\x81\x92\xc1\xd1]\xed,j/%\x8biV1i\x1b\xb4\xb6\x00\xd7Wt\xb5xM\x97\xf5\x8aƍ\xa2\xd1\xe5\x12\xfe\xf3t\xc0\xdf*\xb5*M\xef" +
-			"d\t\xe0\x89'\x8e\x9eDr]Z\x9cos\x84\x0er\x97\x82\xe4\x19\x90k`\xd47r#\x8c\xc6\x00B\x8fg\xc7ܯߤ\xa2\x9dC\x1cG\x11\xa8\xfe\xb8\xaf\xc0\xaa\xa5L\xf8g\xe4/Y\xba/\xd8I\xb1@\xd5\x18\x84\xc6\xf91@s]\x1b\x06\xba\x01\x0f\xd8\xc1evg\xf8\xb3\xb68\xdb\x1a\x9f>\x90s\x997\x88\xa9\x9d!\xa2" +
-			"\xf7\x05\xe8g\x88\xe6\x91.\xb9m\x0ep\xe7Є\x1e\xaa\x8bsT5괽\xed\x06\xf0\xe2/\x15U\xfdg\x18\xc9\xd1I\xc65\xd8O\xf5;\x17\f_` \xf6\xcf0ϊO\xf6\r\v\x8c\xf8x\x1c\xf0\x0f'\xc7]\x14\x1c\xf2\xf6\b\x1b\x9bI)\x86iV\x1f\xb2\x9a\xde\xfd5Ћ\xc8=^\xc6\x19\xf6\x89\xbb\"\xf6\x81\xbc\xac]" +
-			"\xf59\xc5q\xfe\x8b\xdenKn\x8b\xe4'\b\xa9G\xbbT\xd0yI\xcaZ]\x00I\v\xe7d=@\x9eue\x17\xd4\xe8\xac\x02\xe7\xf0H\x96\xec\x1a\x15\x1d\n\r\x90\xe87\xda0J\xeb\x95+\x86l\x9b\x04=l\xe5\xad\\\xfc\x02\x1c\xc0\xc7-X<G#\xa4\xb5甊_\x97\xc3(]\xb7\xb7\x18\x82mc\x92\xe5\n\xc1\xf8\xed:" +
-			":+\xb7l\xdb\x05\x1a\x91\xfb]t\xa8C<q\x1d\x0fw\x91\xf6\xbb`\f\xe9\x88l\xa9T\x1b\xbf\xbdG\xe3\xe4\x16\x8e\xdc\x16\x93D\x06\xd2!\x9b\xb8\xed\xe7\x18\x17\xc8\x1cBl\x80\xf7\xbb\xf3\xb0uػN\x89G\x01\xa9L\xfe\xaf\x9dB\x8f\xf4\x03\xeb~\x14\x17yH\u007f\xa6\x82\xb3\x94 \x90䂸\x81v_\xc8h\xd6r\x1f\x8e\xa3" +
-			"\x0fD\xa3t\x94\xc23\xf8\x80F\xe6[@2\xda\xc6\xcf_Uy\xc4\xfd\xc0S\xac\xf8\x84\xab\xedv92\xc3\x1f\xabG\xafRi\xbb\x8e&2/(\xf3\xb3y\x8c\xddm\xb0\xbb\"\\ji?\x17\xef\xf8\xe1UפWQ\x9c\xec\xd6\xd5#\xbbǇ\xddg-\x8a\x98\xa4\t\xbf\xcd\x16B\xecY\xde\xe2\x12\x80\xcfC\xfcXs\xe8\xc1\xd6" +
-			"\xaa#\x86\x94\xbc\x9a2\x88\x1cG\xf2ͦa\xe6n\xb1\xd4\x1f\b:E\xea\xcdK\xfb\x11\xf7e\x8e\xab\xb5\xaf\x12F\xbby\x98uw\xf7\xa4\x1f'3'}\r\xef\x12#\xf1m\x9b\xad=\xca\xe4\xb6\x17\x97Z\xb1\xdf\xdc\xf5A\xe4\x02su4,\xad\x80\xdf\xec4ݫ<\xcf\x1a\x04\xe4\x16\x88\xd7't\xb4a\xe1\x9cm\x05斔\xf6" +
-			"\xd3\xe8\xb0I\xa7_\x9b\xc2h\x1b\x0f\xecz\xe7\x17\xba\xadŰs\xf7í\x92^\xc9X\a\x92`\xf5Ud\xea`|\xf6\xab\xb0m\xc2x\x81z\x03s\x01\xe4+i\xbb\xb5\x9d\xabt\xf4j\x12\xbf\xa4\x9d+\xfb\xbe\xff7鸥\x9d+\xfb\xfeJ\x12_\xd7N\x8b\xfb\xbf\xd0\xc5\x1dq\xf0kx\xb8\xaf\x99\xab\x1d\xef5\xe4}A3W" +
-			"\xbbݿE\xbf\xfef\xaev\xba\u007f\x8b~\xf7\x8e\xa4\xa9\xc7\xf4\xddG6\xb0Hh\xd5:\xbfJ\xb6or\xe5\xfd\x85\x05ՋE\xbd\x8a\xa9\x06#\x89ݫ\v\xd3\xca\xf4ܪ\xb7\x8e$\xf8V\xb5\xf6\x0ev/\xd0r{\xa0뭟W\x12\xf5\xf66\xae\xe9q\xcf\xf0\xf6\"\xad\xb6\x05\xe3+z\xfc*\xa2^\xd3F\xbb\xa3\xbfē\xbf" +
-			"\xf2t\xd1\x1aѮ\xf2\xb1\xaf<\x95\xf4\x16\xb4\xd3þ\xbaF[b\xeeU\xfe\xf5\xd55\xea\n\xb6]\x93\xb1Z\x99|\xeb|\xce\a\x14 \xa7ށ\xb7\x00\xeb\xed\x1a\x04\x9d[\xceG\xc0\xea}\x12\x8c\xc1\xb55̓\x04\x9a\xbc\xe2\xb7Ȯ\x95\n\xd7\xce\x02'#\xffv\x82\x9e\r\xf4dн\t\xa1w{\xbd\x18to]\xe8\xdd^" +
-			"/\x06\xdd\x1b\x1e\xae\xd7g\x1b\x83\xeem\x12\xd7\xeb\xb3_{\xee\xcd\x15\xb7\xe8\xb3uwF\xb7\xb7\xf6\x98\xafn\xab߹\x8f\xe3\x16W\xedњg\xf3\xc7-\x8eڣ5ώ\x91[\xdc\xf4\x1aM\xde\xd8Z\x9f\xfa\x1d{Snsі\xbd-튵\x8e\x92]\xab\xd9v\x06\xf6v\x96\x9b\xdb\xebŠC\xbc\xfdK\xfbg2\xe8\x10" +
-			"\xef\x9a\xf6\xdc\f\xac};]\xed\x1bۅ\xaen\xbe\xad~\xc7\x1e\xa3\xeb]\xb7gk\x9e\xcdW/\xea\x9bߒ\x9e\x1d[/\xea[\x97\x1d\xc1\x96X\x91\n\x99?\x04\x8b\x1f\xb8\\\xbf\x8a\xb2c\xbd\x80\x1e8\u007f\xdf7\u007fќ\xbak\xbd\xd5+\xcbM46R\xfe\xe6\xee\xee\x9d\x13\xfb\xd6\xfa=\xca\xca{{\xd6\x03?\x12w\xa5\xc2:" +
-			"G\xeb\xc7<\x8f\xc4\fg6\x1c8rp\xfbU;\xa0m\xfe\x9a⳱w\xc7\x02<\x1aJMz_aߛg\x9b\xf2]\xbb\xe8\xba\xf8\x05\xe1&Nw\xe8l\b\xe5z\xb7\x81\x87\x91[\xc7.\xb9\xf8C\x98-\x9axk\x95\xae\v\xfe\x9a*\x1d\xe3Qqۋ\xae\xd6׳\xbdR\xcb}X\x1a\x8a\x16\xa2\xb9Oa;\x19ݪ" +
-			"h]:\xb9Aͱ\x0f\xcd\xc0\xf8ܙ\xeeRs\x88\u07b2í\x9d\xf3\xb5N݃\xa5\xa1n!\x9a\xed\"|˝\x93\xd7ͮ\xad\t\xc8\xf6\xdc9\xb6\xd6A\x80O\xd7tםK\xd7\xfe\x1d{-l\xafTt'?S\xcb\\([\xcb|\xf3\xa0\xcd\xe8V\x15\xeb\xa2\xc9\r\x84\x8e}\x82\x06ƣh\xb6\x8b\xd05\x1e\xfd;" +
-			"\x10\xdb9_\xa9\xeb>,\xcdɚ\x8bf\xc9,\xb6D:yݪq]@\xb1'ұ\xf5Q\x87x\xf4\xcd\xf6E\xba\xf4\xed\xdfS\xd9\xca\xf8Ju\xf7\xe0hj\x9b\vf\xbf\x86\x90o\xf1t\xb1\xbaU\xd9B\xbcg\x94\xe34N\x1d\xd5\x1a\x83\x8d\xe2\xab\xd1ҳû\xff\xebrЄ\xfc\xed\xb7\xffS\xbc\x8a\xa8\xcf.\xd0.," +
-			"\xebY\x00^\x12g\xe5A\xba\vh\fF\x82\xc10W\xbbEɛg\u05ce\xcd\xff\x14W\x1f$ni\xc1\xe8B0}[\xdf6\xfb,_\x14&\v\xf8\x1b\xb6\xcc\xf7\xeeI\xa0E\xe0\x15\xe4\xe6\u007f\xc5R\x96\x98\x10v>\xc0\x06\xf2r\x0e\xaf\xb0f\x05\x84|\xe6\xc5ʙ$\x15\x14\xe9/\xf7\xba\v\xee\xf4\xdb..\xdd\x1d\xb5\xfa\xe3" +
-			"\x91\x1eHiK\xc6Š\xefD\xc4κ\xc7\xed\xd2\xd7ײ\xd7>Z\xaf\x887^\xfec\xbd\x1f\xe8\x02\xdf]\xc7n\xf2`\xaf4\xfbUv8\x96U\x13\x17\r\x87\x10&\xe0\xce@\r\xb0\xcfR\xf5\x92+r\x13\x88F\xad\xf7\xe5\xb3.\x98F\xce\n\xfe\xfe\xf03\xfd7˳F\x1cs\xe4/r\xa5\xec\xc9\xf6\xdfUt\x1f\x05\xf1\xda" +
-			"\xbaV\x8a]\x10\xa5\xbd\x85\xab\xfb\rS\xe4Y\x875\xe3\x13}m\t\xa4\t\x1eo\xb1\xb5\xd5)\a\xfc\x05\xa5\x97ߑc\x98O\x19z&0~\xac3EOY\x82\xd8F\xc9K\xc8{;\xfc\\\x0f\xe4\xe7\xfa\xa0>\x1fR\xf5\x19\x8fK\xafZ\x15\x1ff\xfb\x01,a\xef\x02u\x14\x99\xd8\xfa\xe0(1k\xcb\"\x13{H\x1d%fm" +
-			"Ydb\xf3\x9d\xa3Ĭ-\x8b\f\x0f7\xf5!\xaf۴\x0e\xdb*\x1d\xf8\xbd\x90\x8ea\x17\x92\r\xee\xa6\xf2҆x\x9cj\x9c\xf6\xd0\xc2M\xea\xafH\xeeǁ5{u\xc15ίg\xc2\xdf<\xab\xbf9\xf6V6\x86H\xb0\xd0\xcd\xd28\x0ff\x1e\x92^.GZC8\xe8\xf4\xb4\x1b@Zv3i-vã\x11\xd8ͪ" +
-			"\xe8\xb7\xdb\xd5\x1d\xebo\xcd\xebY_a\xe3[\x99\xdfnyv\xf7\xbb\xd9\xd0h\xb4\\j-\x1dҾ\xa6\aH\xcb\xf4&\xad\xc5\xf4x\xf2\x05\xa6\xb7*\xf60}\xef\x9e\xdd`\xfb\xfe\xbco1\xfe\xb5\xdco\xb7\xbe\xbc\b\xd9\x11\xe7:\xec\f\x90\x96\x9dMZ\x8b\x9d\xf3\x1d\xb4\xb3U\xb1\x87\x9d\x1d}\xb8\xc1\xa2..\xb7\xd8\xce\xcf\xe7j" +
-			"+Y\x13>K}`\xfc2c\xef\xd53\tg\t\xa6\xd6\x1e,;\xfc\x93\xf3\x04c\xb6\aO\xa97^\xbb%\xe1R\x19ױʊ\xa6+\x11a O\x9d\x0e'\xd7\xc1\x96\x9f;\xc8-\xaeN\xd1\xd0\xdb]\xd5-\x87\xd7\xd1\xfdr/W\x97;Ǆ\x017\x9c\xff\x9a\x96\xbaǍ\xb3\xc2\v\xfav\xd3\b㜸õ\xfb\xd2\xe5" +
-			"\x9bo\x02\xf0\xe7ۺ\xc1\xcf5AX\xa1\x04?\xc2\x04\xe1\xf6T$AX\xc4\a\x14\x9c5 \xf9\x03O\x8e\a\xf3(Z\xbb\x11\xfc\xedс\xfe\xa2\xe8\x80\u007f\x1b\x04\xf2\x95\xd1v}\xf9\xa2\xfd\x80\xdfJc!\xe0\xed*\x01\xbb^\xc5\x06\xe9/\xed\x0f\xe4[\xfb-\xa0\xd0R\x00U\xech\x94\x8e\xec\x80^\xa6\xa3\x11\xbb\xb4\x99\xe5^=" +
-			"\xd2s\xa7\xecU\xe1\xa8\xfa\xff\xa1\x89\x90\x0e\x9f\x0e\xbf\xa2\xaf¶ \xda2\x03;c{}\xf3\x1e\xb7\xceQC\x96;\x88&\xc92\x14Y\x16[\u007fcp\x17\x9f\xde\xddӫ\x91\x1e\xbfa_\xe8N\x06\xf6\x99\xfe\x12\xaepi\xf6\x14\xd0kN\xdf\xdf\xc9\xcb!\xee\x1e5\xae\x10#Wl\f\f\xc5\xedG\x02v\x8cw\x88\xae\x82\x12\xe0" +
-			"\xf9\x1c\x84\u007fCq]\x16\xc1\xe5\x82e\x199\xaa\x1e\x1f\xdfդ\xe9\x1dG\xff\xeb\x84\xea&\xfc\t5\xfb2ŵ\xfe\x03\x1d\xea\xe3\x1a\x92\xfeZ\x95M\xe9\xa4\xf0\u007f\xff\xe7o?\xd2\xe68\xdbw\xf7Gg\xb3\xa4\xe6\x0fe\x8a06\x18\x06\xe4\xdbߛ\xb89\xd5\x1f\xb0C\xd2\xfa\xfej\u007f@uRe\xf4\b\xa5\v\xf9\xee\x1e\xeb\r" +
-			"h\xda\xf8J\x94\x9a\xa5L\xa3\xa8h\xee\xba4\x8c[̶T:\xec0\x97\x8bm!\u008c\xbaӝ\xb2B\x81\xf2@[\xc3w\x99\r\x98W[\xbe\xbe{|\xb7\x1f?\xd2\x06\xb1\xd5ƏF\x0f\xfc\xf5\x89\x8b9\x1a\xa2\xe02\x17X\xfa\xdb\xfd\xa9\xa0~\x9az\xe0\xbc\xe3\x15Y\x12\xf7\xf4]\xe3\x9eg\x82;\x1fQ\xecZ\x11\xed\xba\x86" +
-			"\x96\xa6\xcc\xee\x90(\x83\xb5\x80G\\!\x8a\xc8\xd8d.\xfd_\x98HG*\xf52\f\xf1)\xc8\xcb\x1d\x0fv\x83;\x99x\xee\x1e\xffL\xa7\x1f\xdc\x04\xfd\xa0\xd8{\xe4\xc0l\xae\x92\xe3\xdd}\x9e\xb5\xa9\x1b\x15\xa9G\xc7\xef\xee\xcb\xdcc\xd7\xe3\xe3\x87=\n\xd8t\xd6Tx\xae\x0f\xb2:\xe09C\xb0AI|\xaaQ\xf0\x8e\xbcB\xff\xb1" +
-			"\"#\xfb\xf4\x19\x8f\x9f\xcdi\xf7\ue796\x114Y\xf0\xc7c\x9a\x81\x9a\xea\x848-t\x0fB\xbb\x93\x8e\"\xac ܝ\xc0L'䀩\xd8d\xf1\xbaC\x86\xcf@\xaf7h\xbe\xee\xd4\xd8G\xb5\xb4\x98&\xccBj\xf6\x85\xe5\xd0\xf4:A\x9f\xf0\x8d\x1eqlz%X\xb2K\x93\uf08e!Ԥ\x8f\u007f*\x89z\x9b\xb4\x1b\t" +
-			"\xbbOjю\xb7U\xc4\xd4ꕥ\xfd\x1b:\x94\r\n~\x9f\xa6\x15\xaa\xeb\xeb\xe5f\xf5I\xf5\x17J/\xa7\xd2_\u007fB_\x06\xc1\xaf\x9f\xe2\xfc\x84\x82\xd5{\xa0\"\x1a\xb3[\xe7ض\x06\x80\xf4\xa4\x89Nq\x01\x9e\v\xc6D\xba\\H\xffI\xf5Mu\xff(\a\xf1\xcb\xfa\xde6\xafy\x9c\x14\x13\xe8i\b\xd74s]t\x16N" +
-			"\xe3\x8aG\x1f\xf6x\xf6#)S\xf0\x1c\xd7\xc1\x0e\xe1\xe0\x1f7(\r6_pi\xf2\x89\x10Ȝ\xd9:\xbd\x19±\x8f\xacSx\x1ej\x0ex\xde\xfe\xbf\x00\x00\x00\xff\xff\x15:(-ߥ\x00\x00",
+		size:  42540,
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\xe4}[\x93\xe36\x96\xe6{\xfd\nvzj\xdb\xe5\"\x99\xd4=%eeL\xaf\xed\x8dqD\xbbw\xa2\xed}\xd8\xf0\xd4\x03D@\"\xa7\xc0K\x13Pf\x96\x15\xfc\xef\x1b\xb8\x90\x04@\x80\xa42\xd3~٪n\x97\x04|888\xe7\x00\x1f\b\x01\xe0\xfd_~\xf8\xdf\xdf\xff\xfa\u007f\xff\xf3G/\xa1" +
+			"\x19~xw\xcf\xfe\xf10\xc8O\x9fnP~\xf3\xf0\xce\xf3<\xef>A\x00\x8a\x8f\xfc+M)F\x0f\x97\x8b\x17\xfeB\x01=\x93_\xd13\xf5\xea\xfa\xfeVdt\xc0\fQ\xe0\xe5 C\x9fn\x1eS\xf4T\x16\x15\xbd\xf1\xe2\"\xa7(\xa7\x9fn\x9eRH\x93O\x10=\xa61\n\xf8\x17\xdfK\xf3\x94\xa6\x00\a$\x06\x18}\x9a\xdd\u0605" +
+			"Uš\xa0D\x11\x95\x17i\x0eѳ\xef\xe5ű\xc0\xb8xR\v\x12\xfa\x15#\x8f~-ѧ\x1b\x8a\x9e\xe9mL\x88\x92\xcf\xfe\xdc~\xf7\x17\xed\xfbw\xde\xff,\nJh\x05J\xefq\x11.\u0099\xf7mBi\xb9\xbb\xbd=!zh\xf2¸\xc8>\x18\x05\xbf/ʯUzJ\xa87\x8ff\xb3`\x1e\xcdVޯO)\xa5" +
+			"\xa8\xf2\xbd\x9f\xf284\xf0\u007fOc\x94\x13\x04\xbds\x0eQ\xe5\xfd\xfcӯ\xa2*\xc2\xeaJir>\xb0Zn\xe9Ӂܶ\x15\xdf\x1epq\xb8\xcd\x00\xa1\xa8\xba\xfd\xfbO\xdf\xff\xf8\x8f_~4\x14\xb9վ2\xb7^\x8eEN\x83#\xc8R\xfcuG@N\x02\x82\xaa\xf4\xb8\x0f2\x120\xbb\x04$\xfd\x1d\x05\x00\xfe\xf7\x99\xd0\xdd" +
+			",\x8a\xde\xef\x83't\xf8\x92R{n}(\xe0\xd7K\x06\xaaS\x9a\xef\xa2\x1aT4\x8d1\xf2\x01I!\xf2!\xa2 \xc5\xc4?\xa6\xa7\x18\x944-r\xf6\xf1\\!\xffX\x14\xcc\x14,\xa4\xd8?\xa7\xaa8\x97~\x06\xd2\xdc\xcfP~\xf6s\xf0\xe8\x13\x14\xf3\x12\xe4\x9ce\xa0\xfaz\x81))1\xf8\xba;\xe0\"\xfeR\x833L\v?" +
+			"\x06\xf9# ~Y\x15\xa7\n\x11\xe2?\xa6\x10\x15-2\xcdq\x9a\xa3\x80\x17\xd8?\"\xa6\x1a\xc0\x01\xc0\xe9)\xdf\x1d\x00A,W\b\xda\xe5\x05\xfd\xf67\x16HU\x81\xc9\xe7\x0f\xad\x88\xbc\xc8\xd1>A̓\xbb\xa8\xfe-I!D\xf9g\x9f\xa2\xacĀ\"\rW\x83\xcb\x01\xc4_X[r\x18\xc4\x05.\xaa\x1d\xad@NJP\xa1\x9c" +
+			"\xd6`\ab\x9a>\"\x1f\xec\x92\xe2\x11U\x97\xe2L\x99\n\xccl\x87C\xf5\x1b\xef7\x9f/\x87\xa2\x82\xa8\n\x0e\x05\xa5E\xb6\x9b\x95\xcf\x1e,(E\xb0>\xf8\x84VE~\x12\x1e|\x12J\x1d\n\fkx\xccE\"\x0f\xf2]J\x01N\xe3:\x99\xc9\xc4\xf4w\xb4\x9b\xa3l\xdfx)\\oP\xe6Eu\x06\xaa/\x8aʻo\x8e\xc7" +
+			"h/\xf4\xfe&\x8a\xa2\x9ad\x00cE\xc6]\xf4\xbe&\xe7\x83OΥ\x92\xbaY\xbd\xdfs;7fڗ\x05I\x99\xebv\x15\u0080\xb5\xd8i|&\x89\x16\xe5.\x88\xc2\x15ʘ\xf0\x8blw\x10\x85s\x96\x94f'i\x91]T\x93\xc7\x13\xf7Ԯ*\n\xfa\xe1\u008cx\xc4\xc5\xd3N\xb8\xa5\x16\xb1\xd5\x04\xe3\fe\xde2*\x9f" +
+			"뤺\x04Y\xf1{p(\x9e\x99\xc6i~\xda\xc9!\x83%\xb5\xe1\xed\xc8v$\xb71QV\xa8S\x04\x9ciQ\xc7\x05D\xfe\x97\x03\xf4\xcb\n\xf9\x04d\xa5\xd6\xe5\xb2\"/H\tb\xe4{\xed\xc7}g\xcd\x19\xca\xeaÙ\xd2\"\xf7Ӽ<S\xbf(\xa9\xe8\x1d\x04a\x14S\x9f\xf5BP!p\x11\x8eJ\xf3\x04U)\xe5\x12\xda" +
+			"/mw\x14\x92:\xfd\x1eS\x92\x1e0jj\x10\"/\xbcc\xf3H=\x16U&bY\"8\x11pE~\x13C\xa7H\xbf\xf9쫉\x15\"\x88\x1ai\xe4|\xc8Rz\xf3\xf9\xd2\xd8\x17\x94%\x02\x15\xc8c\xb4\x13B\xf6\xf1\xb9\"E\xb5+\x8b4\xa7\xa8\x92U\xfe\x06S\x02\x0e\x18\xc1\xcfj\xe5m\xe2E\x16\x82\xe8\bΘ\xcaB" +
+			"\xbb\x1d\xf7\xf0\xb1\x88\xcf$H\xf3\x1cUB\x97~z\x1bL\xfb\x12@ȼ\x1a\xd5\x1czQc8/\xaa\f\xe0ZmO\x9c\xa0\xf8ˡx6\x9b\x0e`Z(\xadT¥\xed\xc8\xcf{3\xfe\x94,{\xaa\xa1\x9c\xac,?g\aT\xdd|\xde\xed\x9a\xeax\x9b\x02R\xa6y\xa0F\x8d\x13_\x9c\xa9\x8e\xbf\xc8\x16\xf3\xc8\xd5\xfc\x87@" +
+			"\x15'v\xff\xb1\x809\xa6\b\xc3\xfd\x9bw,\xab\x0e\x9d\xfe\"%\x88\x99\x1a\xd8\xd6dg\x11\x88\xe2\xa2\x02lX\xb2\xb5\x88\xc7<o\x12A\xb4\x89\x116\xf6\x92\x02\xa7\xd0\xfb&\x8e\xd8߶cy\xf3R\xf1Q\xb8X\xb1\xf14\\\xcfſ\x1b6patB9\xb4\x85[ۃ\xf5a\xa3\xe9\xe8\xfdᝲЗ\x92\x18\xaf`P" +
+			"\x12\xb4k>\xece\x06\x1bId\x05Чɥ\xab\xf0\xbb7\x8e\xce\xfa\xbb\xdd\x01\x1d\x8b\n\xf9\xdf\xed\xc0\x91\xa2\xea\xad\xe5w\xf3\x141&Fe\x17M\x14\x94A\x92\x9e\x12\xcc\xec#Y\xb6:\x1d\xc0\xb7\x91\xcf\xff~\x103\x12uȽ\xf9\x0f\x84\x1f\x11# \xef\x1f\xe8\x8cn\xfc\xf6\xbb\xff\xb7*\x05\xd8W\xa6AJ\xad\xcb\xf2Y#" +
+			"\xb6Y\xb8\x9c߭6\xb3\xe5\xa2!\xc9\xc5b\xb1\xefQ\xfe7\xc7\xe3QD\xb1\xaf\r\xb3\xddȭꦎߢ\xde&E\xadZ\xa6\xd5ͨ\xff\xcdb\xb1\x01\x87͞\x89T\"[NC\xc4\xf4\xc2\a;>\xee5E\xe6\x8b\xd5|\x13\xf7\x8a\xf0\t\xa7\x98\tI|3-\xa1I\x9a˹ǾI[\x95\xcf\x1e\x8bV\xafq\a" +
+			"/\x12Ti~\x12\xcdo\x90Aq<\x12Dw\xc1\xbc|6\x889\xe2\xa4nL\t\xb2\x14B\x8c\xea0\xcdNA\x85HY\xe4$}D\xfa\xa4o\x9f\x81g\xf1\x88 &\xa6\xea\xd0%\n27\xa0\xa6\xd3\x05ll>\x93ݺ|\x16\xd949g\x87\x1c\xa4\xb8\xed\x1b\x03.\xb6\xfau\xdf\x1f\x19 \x84{\xbd\xbe\xa5\x1a\xad\x8cU\xc5" +
+			"d\b`\xec\x85s\xe2!@P\x90\xe6l(\xde\a\xc5\x18b$\xdb:\xd5\x1d3S\x9cVq7\xa0H\xadW\xd1{6S\x12>\nؤlκ\x9d\xfc.ge<\xa9\x1dҤ\x00\x86U\f\x82\x10\xaaCR\x05E\x8e\xbf^ک 8\x90\x02\x9f)\xdaK\xc5\xcav\n5kk\xd9\x053uX\xdd\x1b\xf3\xbb}\x8c\xd3r" +
+			"W\xa1\x98~\x1b\xf9\x9e\xfc߇V\x9d\xb6R\x11\x93l\xccl&ܖ\x1c\xfe\xa9S\x8fP@\xd3X*\xc7,\xa5Z\xad\x1d\xf6\xf7\xe6DJ\xa8\xc4-\x9b\xcc\xfcd\xee'\v?Y\xfa\xc9\xcaO\xd6~\x98\xcc\xfc0\x99\xfba\xb2\xf0\xc3d\xe9\x87\xc9\xca\x0f\x93\xb5\xbb\xfb\xcb1\u007f\x15EFP\xce\xf6\xdad\xafNf\x1e\x9f\x9b\xfb\xc9" +
+			"\xbc\xf9\xb0h>,\x9b\x0f\xab\xe6\xc3Z~\b\xdbba[.l\v\x86mɰ-\x1a\xb6e\x93\x99\x17\xb6U\x86m\x9da[i\xd8\xd6\x1a\xb6Ն]\xbdaWq\xd8\xd5\x1cvU\x87]\xddaWy\xa8<\x82<\xa9S3\xdd@\xcdx\xbc\xd9ljnt\xee\x8bP\xf8#L\x16#Q=\xe3O\t\xb3\x9e\x99\x14+\xf5\xec\xdc" +
+			"\x19Nm\x9d\xc5J\xa1\xcd`]\xe3\x15\x9a[\xaf\xde\xd7<Lx\x00\x85M\x10\xadU\xedg.\xed\x97=7*^\xb4\x84\xc2\xda3]\x17ڼ\x18\xda\x1d\xba\xeek\xbfY\xbd\x97\xb6W\x12\x17l\xec\x15\xaePS\xb9\xc6\xc23]\xea|\xc9\xdb\xc1\xf4P\xb9\xff\x8e\xa5rs\\tn\xae\xa5u\x94T\xc65eK3^\xe4qۄ" +
+			"\x18\x01x\xb1\fdJ\xc9u\xf3UF٢\xd7\a\x97\xf5\xbfg\b\xa6\xc0\xfb6Ks9\xbcn\xd6w\xe5\U000c72e8@iɬ|\xaeki\xab\xdeC\xf4\xea=\u007f\xf0\xf6C\xe3\U0007b959\xf8x\x87\x16\xed0\x18\xceQV\x87\x9c\xb51:ʇ5\xc1\x99\xec\xbb\xcc\xe2kMj\x1eO\x90\x991\xca\xd9$M\xc9\x15)2" +
+			"\x9b\xad\xe5\xa4ǯj\xbeL\x92\x80\xbcx\xaa@yyJR\x8a\xf84\x13\xedDR\xa3W\xf1\x84\xaa\x18\x10d>I\xb6\x19\x12x.K;\xb0\xcdh4\x06%_\xc4\xf8\xbd\x87\xecr$4;S\x04/\xca\x00 \x92\xcb*\xe5\xabF\xda|\xa9\x06Z\xa6\\\x86i\xe6Gw\xebh\x1b\xc9\xe2\xe4\x1cǈ\xb4s\xa7E\xbcY/`" +
+			"\r\xb4L\xa3\xf8a\xb5\x9cǲx\x9a\x1f\x8b\xb6\xecl\x13\xdd\x1dk\xd0\xe5\x18\x05\x97\xab\xf9z+\v>\x81*O\xf3S\x93w\a\xd6pq\xa8\x81\x96\xa9\x17_\xafW\xb3\xb6^\b\xf2S\x97\x05\xb6\xcb\xe5r^\x035O/|\xb7\\\xac\x16\xcb:<\x9cL\x83\xf1\xc9N/6[3v\x05\xa4\xc0>\xb6\xb1\xe7\xe1\xd4Z\xb3\x0f\x82" +
+			"\xc7c\x04\xefj\xa0\xa0\x9c\x02\xe3\x19\x9a\x1f\x16\\ \xb7\xafE\xda\x16\xc1\xa3TO1t\x1f\b\x8ep˦)\x87SkqWG\xac\x81\x82r\n<nP|Xq\x81\xd2\a\x16\xcc\x1c\"\x88jЁ\x9c\xe2\xd0\xf2\xb0=l\xeb\xb0\x04'\x14\x88E\xd2f\xceڌ`\xdbn\xda\xc4V\xb9\xbc\xc8S&g\xea\xea\xa12-;c" +
+			"\xbf\xc0*\x9dD6.9c\x8f\x03\xd9\u007f\xcf\xd8+\xf8箜\x84Fu\x88SB\x83s\xce\x17\x1da\xab\x1f\x1b\x95vl\xf4$\xcdz$\u007f*\x11h1O\x1d\xc16J\xf1\xdc`Ň\xf0\xae\xf0\x03N\xed+\xbc\x9a\xd0U7\x93\x14\x83#K\xa9\xe1`\xeb\x99\x01kH}\b/\xf6\x87\x82\x1aR\xcb\xe2k\xcb-\xa25\x03<" +
+			"\x01q\x90\x14U\xfa{\x91S\x80=&\f\x17\x80\xf2q\xbc\x99\x17\xaf\x99\x13c\x8c@%\x92\xcd!\xbd7)\xe6\x806\x11a\x9c\x96$%{\xdb`mT\xaf\xeb=\xbbc\xadWW\xa2}\xfe\x19\x02\n\x82\xa2JOi\x0ep r\x9aE\xb8\x04\xe1r\xefZ\xaf\xf6\xc4h,\u007f\xcbII\xa6\x90\xe06z\xbfwR\x00\xf7\xe6\xbf\xce" +
+			"\x05m\xe3\x84ǥ\xa7\xcc\xdc8\xb9\x9b\x1c\xbe\tW]\ah\x82@\r\xffN\xb0W\xee0 4\x88\x93\x14C_I?cGF\xa1f\xf4\xba\x82\x02\x94\xbfl()b\"\xa0$\xc89\x81\xfel\xab-\xb3\x8f\xac;0\xc3\xf6\xaal\xd6d̚-顚q\x91\xabo\xbb\xbf\xfe\xd7<\x9a-\xbd\xff\x8a\xa2\xbfE\u007f\xad\xc3\x0e" +
+			"\x1fT\xe8\x11UD\x15\x11\x96g\x8c\xe5\xacC\xeff3\xb5\xe7\xc9\xfe\xdd<i6\xfdPq\x8a\xe6\xafhߟ\xc1\xf4\xd5p\xb6WQ\xca\xc0ؤ8\x8c\xa3\n\xd1 6\x19\xe1\x04!\x0ec\xffu\xa8i|Um\xb8e\x02\xe2nؐ\b\x151Ь!\x11*D\x89 \x16;\x1e\x8f\xa3\xbf\xd6\x00\u008a\xb1\xbes\xe6-\a\xfc" +
+			"\xfe\xf3\\7\xe0\x0e\xff\x96\xf23\xcaq\xe1\xff\\\xe4 .\xfc\uf2dc\x14\x18\x10\xff\xe6\xfb\xe2\\\xa5\xa8\xf2\xfe\x81\x9en\xfc\xf6G\x16.\xab\x1dQ\xe6峷\xd4\xc6\x0f6&53\x8d\xcd|\xb5D\xb6%\xa0\xedq~\\\xf6\xd7{\xea/\a8M\xb4k^\xb50\x84.Jc\x01=\x01\xb0xڥ9Aԋ<\xb6R\xe2" +
+			"E\x9e\xba\xf0\x19\xceW\x1f\xf6ӡLeOU;R\xc7R\xb6nd2\x9dM\x1fN\xd7\xc6w\xfe\x93\x98>\xb85ul\xf9\x10m<\xa0\xa9\xf5.\xdc\vrOE\x05\x83C\x85\xc0\x97\x1d\xffo\x000\x16\x89\x8c\xddd\x1a\xfb>\xb2:\xbbb\u007f-\vyq\x1c[\x1c[V\xc8\xd3\x02'\xb2,\xd5\xea?ũ\xd4[V\x88\xab" +
+			"\xb7\x1f\xfaeب6\xaaCV\x8c\xc4U\x811_\xfag\vz\xd2 \v6\xd9k'\x01\xc1ם\x80\xd5!\xeb\x83 e\xbfp\xc9\xfeV\x99\xebWb\x8c\xe5\t\xda\xf8<\xebϕX\xd2\xc0<\xa6\xabK\xa6\xaf\xf8ܡ_`\xbb\x9d[\vl7\x8e\x02\xb3y\x14YK\xccf\xa2H\x97\x11\x1c\xf19\x85o\xd6ڰ*\x9e\xb4" +
+			"\tQ0\xebbU\x02\x03\x81\x8c\v\x1c<\x93`\xe6{\xfc#\xc9ڏ\x19l?\xe2S\xfb\xf1\x99\x04\xf3\x0e;\xef\xb0\xf3\x0e;ﰋ\x0e\xbb谋\x0e\xbb\xe8\xb0\xcb\x0e\xbb\xec\xb0\xcb\x0e\xbb찫\x0e\xbb갫\x0e\xbb\xea\xb0\xeb\x0e\xbb\xee\xb0\xeb\x0e\xbb\uec1b\x0e\xbb鰛\x0e\xbb\xe9\xb0w\x1d\xf6\xae\xc3\xdeuػ" +
+			"\x0e\xbb\xed\xb0\xdb\x0e\xbb\xed\xb0\xdb\x0e;\x8b\x14gD\x8a7\"\xc5\x1d\x91\x82W\x9d\xa7zOu\x9f\u2fd9\xe2\xc0\x99\xe2\xc1\x99\xe2\xc2\xd9\xfc\xd2\xdf\xf9\xc0B[Y\xe8\x9e\x16\x8aF\x80=\x13[x<\x13\x9bs\x9f\x89\xcd5\xcf\xc4fX\xd5n\xaaMx{\x95g\x93ZI\xed~W\xe8RgM\xaf\x9e\x85k\xf1g\xa3\xe4F2" +
+			"\xf7n\x11.\xe4\x9f.wێ ]ڝL[\xaf-\xe262sug\x91\xb6n2\x15\xedV2miSn)3\x176\xdd\x162s\xae\xe8\xd6\x1a\xc0\xa6[c\a\x9bj|\xea4\x9b_\xa4\x9b\xa3\xc8̚\xc9,\xab\x11\x05$\x92\x10\xab%9d+\x11\x9b\x95\x91q'3\xac6刍D\xac\x9cگ\x1b\x84\xa9\xfb" +
+			"Jf,\x9d\xaa/%b\xe1\xd4|!\x11sS\xf3\xd6dN\xcd\x1b\xcb9\x15o\xec&~\x01ksH\xc2\x1c\"z\xa2\xee\x0f\x963\x139\x0ew0D$\x10\x0eo\x90$\xd8\n\x80\xee\f\x92\x04w\"\xdd\xe1\v\x92\x04\x1b\x01p\xb8\x82$\xc1Z\x02L\xadW\"}\xe9Tz)\x00\v\xa7\xce\v\x01\x98\x9b:7\x86r\xea,\xed" +
+			"\xe5TYZK\xf3\x81\xf8\x8d\x98yA[\x89P\x9d\xd1@f\x1a\xc4\xea\x95\x06\x1aiP\xab{$t\xab!7\xab\x1e\xe0N\x03X\x1d&\x91\x1b\ri\xf5\x9cD\xaeud\xbf\xad+\r\xb0\x1ch\xeaRC.\x06Z\xbaА\xf3~K\r\x17\f\xb4T\xf7\xc4@C\xa3\xc9\xebb\xc6,J\x99$)s e\x8a\xa3\xcc`\x94\t\x8a" +
+			"2\xffP\xa6\x17\xca\xecA\x9d\x1b\xa8\xbcO2\x1b߉T\x93\xefx9'\xdf\xf1\x1a\x9c|\xc7T1\xf9\x8ei\xea\xe4;\xd6\"'߱\x96\x9b|\xc7\f\xe3\xe4;f@'\xdf1C\x9b|\xc7\xfc\xe0\xe4;\xd6T\x17ߑ\xcc\xc9wm\x96\x9b\xefZ\x88\x9b\xefH\xe6\xe0;\x92\x8d\xf1\x1d\xc9\xc6\xf8\x8ed\x0e\xbe#\xd9\x18ߑl" +
+			"\x8c\xefH\xe6\xe0\xbb&\xc3\xcdw\xad]\\|\xd7\x00\xfa|G29H\xf7\xf8\xae\xcdq\xf2]\x8bp\xf2\x1d\xc9\xec|G\xb2\x11\xbe#\xd9\bߑ\xcc\xcew$\x1b\xe1;\x92\x8d\xf0\x1d\xc9\xec|פ;\xf9\xae5\x87\x83\xef\x9a\xfc\x1eߑl\x94\xef\x14\xc8\x18\xdf)\xd01\xbe#\xd9\bߑl*ߑl*ߑl\x84\xef" +
+			"H6\x95\xefH6\x95\xefH6\xc2w\x1d`\x8c\xef\x14\xfb\x0e\xf3]\a4\xf9np=D[+P\x96\x02\x94'}\xe5A^yNW\x1eÕ\xa7l\xe5!ZyFV\x1f\x80Շ\xdb\f\xda\bO\xa4\x9a\x84\xc7\xcb9\t\x8f\xd7\xe0$<\xa6\x8aIxLS'\xe1\xb1\x169\t\x8f\xb5\xdc$<f\x18'\xe11\x03:\t\x8f" +
+			"\x19\xda$<\xe6\a'ᱦ\xba\b/\x83N\xc2k\xb3܄\xd7B܄\x97A\a\xe1ep\x8c\xf028Fx\x19t\x10^\x06\xc7\b/\x83c\x84\x97A\a\xe15\x19n\xc2k\xed\xe2\"\xbc\x06\xd0'\xbc\f\xcaQ\xbaGxm\x8e\x93\xf0Z\x84\x93\xf02h'\xbc\f\x8e\x10^\x06G\b/\x83v\xc2\xcb\xe0\b\xe1ep\x84" +
+			"\xf02h'\xbc&\xddIx\xad9\x1c\x84\xd7\xe4\xf7\b/\x83\xa3\x84\xa7@\xc6\bO\x81\x8e\x11^\x06G\b/\x83S\t/\x83S\t/\x83#\x84\x97\xc1\xa9\x84\x97\xc1\xa9\x84\x97\xc1\x11\xc2\xeb\x00c\x84\xa7\xd8w\x98\xf0:\xe0\x04\xc2S\xd6\xf3\xb5%qe\xc5[Y\xd0V֫\x95\xe5he\xb5YYLV֊\x95\xa5`u\x99" +
+			"W]\xc2\xc5'\x1b\xe3\x89T\x93\xf1x9'\xe3\xf1\x1a\x9c\x8c\xc7T1\x19\x8fi\xead<\xd6\"'㱖\x9b\x8c\xc7\f\xe3d<f@'\xe31C\x9b\x8c\xc7\xfc\xe0d<\xd6T\x17\xe3ᓓ\xf1\xda,7\xe3\xb5\x107\xe3ᓃ\xf1\xf0i\x8c\xf1\xf0i\x8c\xf1\xf0\xc9\xc1x\xf84\xc6x\xf84\xc6x\xf8\xe4`\xbc&\xc3" +
+			"\xcdx\xad]\\\x8c\xd7\x00\xfa\x8c\x87Or\x98\xee1^\x9b\xe3d\xbc\x16\xe1d<|\xb23\x1e>\x8d0\x1e>\x8d0\x1e>\xd9\x19\x0f\x9fF\x18\x0f\x9fF\x18\x0f\x9f\xec\x8cפ;\x19\xaf5\x87\x83\xf1\x9a\xfc\x1e\xe3\xe1\xd3(\xe3)\x901\xc6S\xa0c\x8c\x87O#\x8c\x87OS\x19\x0f\x9f\xa62\x1e>\x8d0\x1e>Me<|\x9a" +
+			"\xcax\xf84\xc2x\x1d`\x8c\xf1\x14\xfb\x0e3^\a\xec1\x9e<\x9a7t\xec[\x9e|o\xb7IѢ\xdc\xdd)?\xfc\xc9}1,\xa9\xdb\u07b57\xb7y\xd3Ĳ\xf3\x9bW\xae\x9c\xf41\x0e\xfeXv7\x8a2\x0f\x94\xdf\xe5@\xab\a\x9a\xf8M\x12;6g$\x1d\x8b\x82\x1aImA\xd8/\b\xfb\x05\xbb\xed%w\xee\x9d\x1d\xc6" +
+			"Q0Z\x94\x8e\xa3E\x10BK\ṿd\xa2\xbdƾĹU\x8a\xf4\xcd\xc7F\xda\xee\x98V\xcd&?\xa5\xd5q\x81\x99{\xcb1\x1c\xcf\xd6\xf3\x9c\"\ak\x86\x13k\x86\xd3k\x86\x17ŦQ\xad:\xef#\xff\xaf\x9ao\xb5\x96\x17:\xa2\x9d\x9fx\x14\x99A\\\xe4\x90\xdfda\x8915\xb3\x17mjf/\xee\xacb\xe1\x90X" +
+			"8$\xb6\x8b\xcaU\xdb'\x02\xd1\xfc\xf6\xf4\xa0=\xeeZ\x94\xady]^\xbfu]^\xbfq\x16\x99p@&\x1c\x90\xf9&\xdawZ\xe8\x97Qȱe\xdeٌ\xd0*-\x15\xe5v9MD\xc0}[@\xf8\xe1b\xddA\xb7=n\x9b\xf2|\xff{Wڹ\xbb\x9e\xef\xd9\x12\x83\xad\x17\x17\xf8\xb7\x18\x03B\xbe\xfbt\xc3F\xe7\x9b\xcf" +
+			"\xbdc|b\x9e\xcf\xf7\xa65\xfbК`\xc0\xe7,\x97\x82(4\xe4\xf82=y\xb9|\x84q\u007f\x84\x82as\x0e\xb17b\x9a9\x9d#͜\xceaNi\x89SZ\xe2\x90&\x93-\xa3\xb9%GJ\xb3\xe4\x98\xd2\x12\xa7\xb4\xc4)-q\xfb\xdd\x1a,\x9d\x89\xe4qg\a*\x99\x80\xd2 J\a\xb3\x85\xe7\x83n\xcaaI\xb6F\xa1" +
+			";\xf6\xd7\x16%\xf2\x00\x8c-L\xcc,%N\xcc,%P\x9c\x02\x13\xb7\xc0\xc4%\xb0I\xb7\x05\x8b%\xab\xf1\xaf%\xab'0q\vL\xdc\x02\x13\xf7y\"g\xc4hg\x8c\xdc!3\x01\xa6cF\x83F7\xea\x88,k\xcb\"\xb4\x8d\u05f6\xb0a'\x9dl1\xa3\xa5+\x01\xa3\xa5+\xd1b\x97\x938\xe4$V9<\xd1\x16!fz" +
+			"\xe3M3]\x97\x938\xe4$\x0e9\x89\xfbP\x983$\xba\x83b\xeex\x18\xc3(\x80\xd1HP\xcc6$\xc5֔x\x89\x16ǅ-\x06\xe4\xf94[\x18\x98YJ$\x98YJ08\x05&n\x81\x89K`\x93n\v\fKV\xe3SKVO`\xe2\x16\x98\xb8\x05&\xee\xe3~\xce8ю\x00\xbaCe\x02Lǌ\x06\x8cn\xd4" +
+			"\x11Y֖\x81\xe3<\x8ema#\x8e!ڢ\xc6\xc8Q\x82\xc6\xc8Qb\xc6%-qJK\x1c\xd2d\xb2-^\xfa9\x8dw\xfb9\xa6\xb4\xc4)-qJK\xdc'9\x9d\xa1\xa2\x9e\xeetG\xca8J\x83\x8cƉf\xcaaI\xb6F\xa1C\x1c\xb7Q\xa2\xde\xc1\xd2\x1e6x\x96\xfb\xeb\xbb-\xcdQ\x18\xcd\xde7\xcb\xfe$\xae\x10" +
+			"\xca=\x90C\xef\xdbn%b\xb3\xde\xf0\x1f\x00zb\x9d\v\x15|[\xb4r\xc2A\x1et\f2Ҟs\x94g\x87X\x12S)I\xf9J\x8a8\nq\x00\xd5~\xf0!\xa8\xd3\xe1A\xa4\xf4O\xb6:\x80\xb6\xc7&\v\xa8\xff\xf4g\x01\xf5\x1f\x03\x87\xaa\x83S\xaa\x83S\xaa\x83\xf6s\xfc\xf6r\xbd\x87\xe2h\x1c\xaa\x1aI]\x82\xf0'\x94" +
+			"\xec,wm\xc9Μז\xecl\xfcbmድ\xd5J^\xb4\xb3\x90WZZ9\xb6z\x9d\xa11x\x91\xe6\xd7\x16T\xcc\xfcBU\xe1KUU\v^\xb4\xb3\xa8Q=]\x01E\xc8P\xa7\xed+p}A[\x8d\xf0\xa55\x1a\x8b8l\xd5:\xfc\xefsv(hխJ\xf3;Y<\xf5ēD/\xa2\xf2\xd98\xdff\xa1" +
+			"\x0e\x84\x14\x99\x1e\xbb\x06\xa6\xfb\xc6n\x84\xd1\x04\xa8\xd0\xf2b\x19\xfb\xf5\x9bT\xb4s\x88\xf3(R\x8a?$\x95\xb2j\xd9N\xf8W\xec\xafrR\xcc\xebJ\xf8\xe6\xf91%\xcfvm\x98\xd2\f\xf5\x80\x9d\xba\xccn\xa5\xbf\xde\x16\xe7\xbeŗw\xec\\\xe6\v\xd4\xd4\xce\x10\xf1\xfb\x02\xf43D\xeb\xa8|\x1evGg\xe0\xf5\x82\x1f\xaa\x03\x18" +
+			"U\xb4;m\xbf\x1a\xbc\xf8\xabcU\xf7\x19FvtRH\xf5\x92\xa5~\xe7\x82\x11\v\x02$\xfe\tp\x9a\u007f\xe9߰ 2\x1fJ_~8[\ue890\x90\x8f\xa5Z٪\xd5\"\x80)\xc9R\xc2\xef\xfe\xf2\xf5\xa4\x94\xcd\x00t\v.\xec\x05\xbd0\xc6\x05\xb1\x95\x979\x96\xf3_L\rv\a\xde^H\x0exD\xdbL0zI\xca" +
+			"\xbe\xbb\x00\x92'\xae\xd9z\xc0^\xbf\xa0F\x17\xe5Y\xbbG\xbc\x15ר\xe8P\xd5\x01ƍ6\"g\xf0\xca\x15C\xb7C\x8c\ue3b3\xbd~\x01\x8e\"Ǯ\x18X\xa3\x19\xd2\xea\xb3j%\xaf\xcb\x119c\xb7\xb7\x18\x8a\x1d\x01\x9b\xe5\xee\xf5\xdbutQvݎ\x1b4;\xacL\xa8E\xbd\xe6:\x1e\x913r\x17\x8c\xa1\x1d\xd3\r\xce" +
+			"\xf6\xfa\xed=\x9a$\xbbr춘82\x90\x16ݚ\xdb~J\x90#l;\x96?\xf1\xce\xc3\xc1no;%\x1ey\xac0\xfb\xbfv\n=\xd2\x0f\xac\xbbQR\xe5\x80\xffL\xa5\x8eRM\x06\x9b\\\xb00\xd0\xee\v\x99\xad\x06\xeeñ\xb4\x81Y\x94\xf7R\xf5\f\xbe\x92\xc7\xc6[%˨\xfb!\x84UQ\xc2\xe2)\xf7\xdaO\x01-" +
+			"N'\x8cL\xfa\x13\xe5\xf8U*C\xd7\xd1D\xe6\x05en1\x0f\xc0^\x87\xb8+\xc2f\x96\xe1s\xf1\x96\x1f^uK:\r%\xb3\xed\xb6z\x10\xf7\xf8\x88\xfb\xac\x9b$\xfeO{\xad\xad\n\xe9\x8f\xf2=)\x9e\xf29H)\xca&\x88\xed\x95i\xbaT{5\xa5\x17Y\x8e\xe4\x9bU\xab3\xf7\x9eH\xfd\x81`T\xa5ɲ\xb4\x1fq_" +
+			"\x17\xb8Z\xfd\x18\xb8\xabWg\xdd\xe3-\x99&ɜ\x93\xbeEt5=\xf1㐯\x1dƔ\xbe\x8fj\x05\xffQ\xefD6\xb04\a\x15\xd3\n\xf5[\u007f\x9a\xee4\x9ec\rB\x95\xe65\xafO\x18\xa9\xa3\x87\xb3\xd6\xe5\x99[R\x86O\xa3\xabUZ\xe3\xdaTF\x05Y\xca]^\x19\xb6=\x81\xa3\xbb\x1f^\xaa镂u #\xab" +
+			"?D\xa7\x11\xc1\x17\xb7\t\x87\x06\x8cW\x98\xd73\x17@\xfe k\x0f\xd6s\x95\x8d\xdeL\xe3\xd7\xd4seۓ?\xc9\xc6ɛ\xd98\xf9\x93l\x9cL\f\xffW\x86\xb8\x85\a\xff\x88\bwUsuཅ\xbe\xaf\xa8\xe6\xea\xb0\xfbS웼\x95}\x93?Ǿ\x89e\xd24a\xf8\x9e\xa2[\x87闺\xbc\xc9lߔ*\xdb" +
+			"\xab&T\xafV\xf5*\xa1\x1a\x8cM\xec\xde\\\x99A\xa1\x97A\xbb\x8dL\x82_j\xd6\xc9d\xf7\n+\x0f\x13\xddd\xfb\xbc\x91\xaa/\xaf\xe3\x9a\x16'\u007f\x82U\x93\xb7\xb1j\xf2'X\xd5\xca\xc2o;@Le\xb5W\x06\xf2\xeb\a\x8f\xb7Q\xf4\xc5U\\\x17a\u007f\xb8E\x937\xb1h\xf2\xc7[4\x99\u0380\xb5\xfe\x84ͷ\xd1[\x9f" +
+			"\xf3\x95\x1cEO\xbd\x01\x1f\x15\xac\xb3i*\xe82p>B->e\x82\xe1_[\xc2<I\xa0\xe9\xdb\xfc\x169\xb6Ra\xdbY`\x15\xe4\xdeN0\xb1\x82\x89\x02\xc67!L\xaeo\x92\x80\xf1\xad\v\x93\xeb\x9b$`|\xc3\xc3\xf5\xf6\x84\xd7\xd9\x13\xbe֞\xf0:{\xc2\xd7\xdaspw\xc6x\xb4N\x18\xaf^V~t\x1f\xc7KB" +
+			"uBm\x8e\xcd\x1f/\t\xd4\t\xb59v\x8c\xbc$L\xaf\xb1$|\xa5%\xe1U\x96\x84\xaf\xb4\xe4\xe0ޖa\xc3\xf6\x8e\x92]k\xd9a\x01=Ӽ\xbc\xbeI\x02F\xd4K^۾\xe4\xba\xf6%\xafm_\xd2۷3V\xbf\xb1]\xe8\xea\xea\x87ʏ\xec1\xba>t'\xd6\xe6\xd8|\xf5\xaa\xb6%W\xb5-ye\xdb\xc6\xfc\xd8" +
+			"\xc9\xeb^\x16j\xff1\xc8\xfa\xab\xa88֫\xe4{\xd6\xdf\xf7#\xdb\x06\x9d~\xa9\x8fz\xe1v\x13M\x1f\xd9\xfe\xe6no\x9d\x15\xfb\xb1\xf7{To\xde;\xb1\x9c\xf2#\xf1\xd8TX\x97\xd8\xfb1o\x17\r\xe0̊=\xcb\x1c\xbc\xff\xaa\x1d\xa5n\xf9\x9a⋱w\xa7\ax0\x8c:\xfd\n\xfb\xc92\x87\x8co\xdbE7&\xcf\v" +
+			"\x0f\x00\x9e\xd0\xc5P\xca\xf6n\x03\x87 \xbb\x8d\x1f\x9c6\xb6\xa8ּ\xb5J\xb7\x85|M\x95\x8eq\x98x\xe8EW\xfb\xeb\xc5^i\xe5)\"\rC7\xaa\xd9Oa[\x05\xbd\xd4кv\xed\x065\xcb>4\x03\xe3\ng\xbeKm\u007f\xd5\x0e\xb7a\xc9\xd7\x06\xf5\x04\x91\x86\xb9\x1b\xd5\xfa!\"\xb7\xdcYe\xbd8\xb45\x05Ş;" +
+			"\xcb\xd6:\x15\xe0\xb25\xdfu\xb7\xbfj\xc7ހ\xd8+\r=*ϴ\xb2T\xaaoe\xb9y\xb0/\xe8\xa5&\xd6Uk7\x10Z\xf6\t\x1a\x18\x87\xa1\xc5.\xc2\xfdU;\x10\x87%_i\xeb)\"\xcd\xc1Z\xaa\xd6ӹ\xd9\x12i\x95\xf5R\x8b\xeb\n6{\"-[\x1fu\x88\xc3\xdeb_\xe4\xfe\xaa=\x95\x83\x82\xaf4\xf7\x04\x89" +
+			"\xa6\xb5\xa5b=\x8d\x9b-\x9e6Q/5v\xa3\xde\x13\xc2\xf8\xa2\x1c՚+\x1b\xc5w\xb3\xadc\x87\xf7\xf4\xd7\xe5\xa0\x05\xfb;m\xffg\xf3*\xa2)\xbb@ǰ\xa2e\x9e\xf2\x92\xb8\xde<H\x0f\x01M\xc0\xac\x11\x10\xe0n\xb7({\xf3\xec\u07b2\xf9\x9f\xe3H\xd6\xe2\xb6=\x18_\b\xe6o\xeb;\xa6\xcf\xed\x8b\xc2\xda\x04\xf9\x86-" +
+			"\xf3\xbd{-\xb0\x97!\v\xb4\x9b\xff;\x91m\x8a\t\x11\xe7\x03\xfa@\x99.\xe1U\xf1\xd4B\xd8g\x99\xdc\x05S\x9b\xab$\xe9/\xf7\xba\xf1n\xf4\xdb.\xea\xf1\x86\xf6\xda\xe3\xd0^Ѳ\xaf\x99T\x83յ;\x144\xa9C\xf1\xfaZ\xf1\xda\xc7\xde+⍗\xff\xf4\xde\x0fT\xab\xef\xae\x137y\xf0\xcf\xde_Ҭ,*\nr*" +
+			"!L\x88rg\xa0\x06HRؽ\xe4*/r\xa4咤x\xd2\x15Ӳ\xd3\\\xbe?\xfc\xc2\xffMqJ\x9bc\x8e\xf2E\xae\\\xfc\xb1\xc8\xe9.\xba\x8d<\xb0\xef\xbf3\x8aô\xb7p\x8d\xbfa\x8a=\xeb\x88j\\\xaa\xef{\ni\x8a\x83\xe31}\xeeN9\x1c\xd3g\x04\xeb\u007f\x0f2\x12<\xa6\xe8\x89\xc1\xe4\xb1N\x88\x1e\xd3" +
+			"\x18\x89\x8d\x92u([\x1b<\x13\xbf\xfdL\xb2\xees\x06\xbb\xcf\xf8\xe46k'G\xf8\xdeWSĻ@-I&\x96d\x96\x14\xb3t\x9bdb3hI1K\xb7I&\x16\x9f,)f\xe96Ɉp\xd3\x1e\xedu\x9b\xbdö\x9d\r\xdcQ\xc8\xfb\xb0\r):7\xad\x9cyAU<i\x92\x12\xd5\xc3\x14\xba\v\xc6l\xe8VJ" +
+			"Nj\x82\xad\x9f_/DX\xd4xs\xecK\xc5\x18*\xa9\x89v\x91\xc6y0\xf3\x90\xf4v;\xd3*\"\xd9T\xbf)Ȟ\xdfH6\xd9o$S\xfdF\xb2\xc9~\xbb\xbaaӽy\xbd\xe8+|\xfcR\xe1/\xf7\xbc\xb8\xfbݬh6\xdbn\xb5\x9a28\xd5\xf5\n\xb2\xe7\xfa\fNv}\x06U\xd7g\xf0z\xd7On\xd9\v|" +
+			"?]\xf6K\x9c\u007f\xad\xf4\x97{\xbf\xbd\b\xd9\xc2s#~V\x90=?\xe3\xd3d?\xe3\x93\xeag|\xba\xdeϖ6\xbc\xc0\xa36)/\xf1\x9d[\xce\xd5^\xea\r\xf8b\xea\xa3\xf2\x97ɽW\x8f$R$ɮ\x119\x12\x9fRf\x06\xaf\x91\xd9\xdaM\x96\x1e\x98pu3\xae\xb2Js:6\x11\x11 G\x99\x91 \xd7\xc1\xbd8" +
+			"\xb7d\x0f\x84:G\xab\xd1n+\xde\vx\x1d=m\xeeek\xf2h\x9f0\xe0F\xf0_S\xd3x\xbf\xb1\x16xE\xdb^\xd4ä$\x19pñT\xbf{\xe7)\u007f\xbe!\x14\xc4_\xbc\xb0BqQA/<\x9e\xf3\xd8\vs\x90!\xef\xa2\x01\xd9\x1f\xf5丷\x8e\xa2\xbd\x1d!\xdf\x1e\xed\xe9/\x8a\xf6\xe47\xdfk_\x19\xdd/" +
+			"߾h\xdf\x13\x8d\xe9#\xd4\xdbU<q\xbdJ\x1f\xa4\xbf\xb4\xdfk\xdf\xda\xdf\x036V\xf2T\x13[*\xe5=\xdb\xe3\x97\xe9h\x99c\xd6L\xb1ӎ\xfcܩxU8\xaa\xfe\xff\xb0DȻ\xcfH\\\xf1Wa\xf7 \xda2\x838c{}\xf5\x8e\xb0ƈRTqK\xb2e(\xb6,\xb6\u007fgHo>\xdd\xdf\xf2\xab\x91\x1e" +
+			"މ/|'\x83\xf8\xcc\u007f\t\xefp0}\xf4\xf85\xa7\x9fn\xda\xcb!n\x1e4\xa9*\xa6]\xb110\x1c\x97\xcc\x1aX\tN\x88\xaf\x822\xe0\xe5\xe2\x85\xffD\x80\x14\xb9W\xd7\xf7\xb7\xc9\xccR\xb4|\xb8'\xac\xea\x93D\xff\xeb\x8c\b\r\u007fF4)\xa0W\xd7\xff\x03e\xa4ܫY\xffY\x15\xb4\xb0\xe6\xc8\u007f\xff\xcf?\u007f\xe2" +
+			"\xd5I\xb1\xf7\xb7\xa5\xb5ZV\xf2\xfb\x02\"\xaf\xae\xbd\xc0c\xdf~\xa1\x80\x9eɯ\xe8\x99\xf2\xf2\x96b\x97\x8b\x97\x1e\xbd\xf0\xa7\x1f\x18\xa0|\xf8\xb1\xaa\x8a\xca\xfb針w\x1f\x17\x10q\x91\"\xef\x96\u007fg2.\x17\x0f\xe5\xac%.\x1d~@$\xaeR~\x1e\xd3V\xed\xfd-L\x1f\x1f\u07b9\xbe2\x0f\xa5P\xb8\a\xe5\xf4f\xcc]" +
+			"\xb2\x05\xbf\xf0\xe83\x94j\x85\xf1ؼ\xe9\\\x9a#\xeci?\b\xd8b@\x89\x15m-\xfc\xe6\xe1>\x99?\xf0\n\xefo\x93\xf9\x83\xd1\x02wy\x16\xaf\x96\x8a8\xb8\xc0\r\x96o\x048\xe7<\xe8\xa1\x03.\x1b^\xb1\xf5uG\xdb5\xe98m\xa4\xcb\xee)\xee(\xd1\xee~\x18\xa8\xcal\x0e\xa3\xac\x9b\x87{R\x82\xbcIb\x1d]\xf4" +
+			"\x8f\xffu\xcec\xf6M\x84l\tr\x97\x81\x9c\xd2S\x8c\f\xe9l\x14\xbby\xf8;\x1f\xcb.^\xc8?t\xe2\x1dz\xa4\x18]\xa5\xc7\xfd-N\x87̍r\xe8\xb0\xf1\xfdm\x81\x1d~-\x1f~M\x90'\xc6FZ\x81\x18y)\xf1\xe4\x04\xc4;\xa0\x18\x9c\t\x92\x9d\xadb\xc3\xc4\xf99\xfc\x01\x1d\xce'\xd9\xe1\x18\x9a\xfdz@\v\t\xa2" +
+			"\xd5\x19ɼ\xd0>\x10\xf4\x1biI\xea:\xb1\xbd\xc3Tb\xe4y\xdb.#\x87\xb3\xb7\xeb4\u007f\xec8;Ŵ<Y\x1eA\x17Z\x8b/ʍ\x85.\xe5\xa9N_\xfd\xfc\xaa\x11)n`\xbe\xf1F\xba\x10\x85\x0f\xffQ0\xf3R8\x8eT\x9b\xcfJ\xf1\x86\x0f\x15\xbc\xbf\xa5\xd5\x1bk\xfbO\x94\x15\x14y\u007f\x83\xb0B\x84\\\xaf\xb7(" +
+			"ϊ\xbfR\xfbv(\xfd\xb7/\xe8\xab\xef\xfd\xdb#\xc0g\xe4\xed>)&\xe2\x13\x80\xc11v\xa8\x02E{VŨ\xba\n^*&T\xaak\xd6~V\xfcP\xddvL\xfc\xba\xb6\x0f\x8dk\x8e \xbd\xbf\x15G+l\xc3\xccu\xec\xdc\x04\x8d\x8d\x8f~MR\xe2\xb1\xf9\x97\xf7\x04\x88wB9\xaa\x00E\xd0;|\xf5J\x10\u007fa\x19" +
+			"l\xcc\x1c\x1c\xde,}\xf7\xfeV4\xea\xfe6\xa1\x19~x\xf7\xff\x06\x00=\x10\xcd\xf2,\xa6\x00\x00",
 	},
 
 	"/internal/assets/recover.jpg": {
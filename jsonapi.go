@@ -0,0 +1,121 @@
+package rst
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// JSONAPIMediaType is the media type of JSON:API documents.
+const JSONAPIMediaType = "application/vnd.api+json"
+
+/*
+JSONAPIResource is implemented by resources which can be encoded as a resource
+object of the JSON:API specification (https://jsonapi.org) by MarshalJSONAPI.
+
+	func (a *Article) Type() string            { return "articles" }
+	func (a *Article) ID() string              { return a.Slug }
+	func (a *Article) Attributes() interface{} { return a.Content }
+
+Relationships are included when the resource implements JSONAPIRelater.
+*/
+type JSONAPIResource interface {
+	Type() string
+	ID() string
+	Attributes() interface{}
+}
+
+// JSONAPIRelater is implemented by JSON:API resources with relationships. The
+// values of the map are encoded as the relationship objects, like
+// map[string]interface{}{"data": map[string]string{"type": "people", "id": "9"}}.
+type JSONAPIRelater interface {
+	Relationships() map[string]interface{}
+}
+
+type jsonAPIResourceObject struct {
+	Type          string                 `json:"type"`
+	ID            string                 `json:"id"`
+	Attributes    interface{}            `json:"attributes,omitempty"`
+	Relationships map[string]interface{} `json:"relationships,omitempty"`
+}
+
+type jsonAPIErrorSource struct {
+	Pointer string `json:"pointer"`
+}
+
+type jsonAPIErrorObject struct {
+	ID     string              `json:"id,omitempty"`
+	Status string              `json:"status"`
+	Title  string              `json:"title"`
+	Detail string              `json:"detail,omitempty"`
+	Source *jsonAPIErrorSource `json:"source,omitempty"`
+}
+
+/*
+MarshalJSONAPI encodes v in a JSON:API document. It's meant to be registered
+in Marshalers, to serve JSON:API documents to the clients asking for them:
+
+	rst.Marshalers[rst.JSONAPIMediaType] = rst.MarshalJSONAPI
+
+A JSONAPIResource, or a slice of them, is encoded in the data member of the
+document. An *Error is encoded in the errors member, with an error object for
+each one of its invalid fields if any, whose pointer is relative to the
+attributes of the resource. An error is returned for other values.
+*/
+func MarshalJSONAPI(v interface{}) ([]byte, error) {
+	switch t := v.(type) {
+	case *Error:
+		return JSONMarshal(map[string]interface{}{"errors": jsonAPIErrors(t)})
+	case JSONAPIResource:
+		return JSONMarshal(map[string]interface{}{"data": jsonAPIObject(t)})
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("rst: %T can't be encoded in JSON:API", v)
+	}
+	objects := make([]*jsonAPIResourceObject, rv.Len())
+	for i := range objects {
+		resource, implemented := rv.Index(i).Interface().(JSONAPIResource)
+		if !implemented {
+			return nil, fmt.Errorf("rst: %s can't be encoded in JSON:API", rv.Index(i).Type())
+		}
+		objects[i] = jsonAPIObject(resource)
+	}
+	return JSONMarshal(map[string]interface{}{"data": objects})
+}
+
+func jsonAPIObject(resource JSONAPIResource) *jsonAPIResourceObject {
+	object := &jsonAPIResourceObject{
+		Type:       resource.Type(),
+		ID:         resource.ID(),
+		Attributes: resource.Attributes(),
+	}
+	if relater, implemented := resource.(JSONAPIRelater); implemented {
+		object.Relationships = relater.Relationships()
+	}
+	return object
+}
+
+func jsonAPIErrors(err *Error) []*jsonAPIErrorObject {
+	status := strconv.Itoa(err.Code)
+	if len(err.Errors) == 0 {
+		return []*jsonAPIErrorObject{{
+			ID:     err.ID,
+			Status: status,
+			Title:  err.Reason,
+			Detail: err.Description,
+		}}
+	}
+	objects := make([]*jsonAPIErrorObject, len(err.Errors))
+	for i, field := range err.Errors {
+		objects[i] = &jsonAPIErrorObject{
+			ID:     err.ID,
+			Status: status,
+			Title:  err.Reason,
+			Detail: field.Message,
+			Source: &jsonAPIErrorSource{"/data/attributes" + field.Pointer},
+		}
+	}
+	return objects
+}
@@ -0,0 +1,83 @@
+package rst
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// article is a resource encoded in JSON:API documents.
+type article struct {
+	slug  string
+	Title string `json:"title"`
+}
+
+func (a *article) ETag() string            { return a.slug }
+func (a *article) LastModified() time.Time { return testTimeReference }
+func (a *article) TTL() time.Duration      { return 0 }
+func (a *article) Type() string            { return "articles" }
+func (a *article) ID() string              { return a.slug }
+func (a *article) Attributes() interface{} { return a }
+
+func (a *article) Relationships() map[string]interface{} {
+	return map[string]interface{}{
+		"author": map[string]interface{}{"data": map[string]string{"type": "people", "id": "9"}},
+	}
+}
+
+func TestMarshalJSONAPI(t *testing.T) {
+	Marshalers[JSONAPIMediaType] = MarshalJSONAPI
+	defer delete(Marshalers, JSONAPIMediaType)
+
+	mux := NewMux()
+	mux.Handle("/articles/{slug}", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		if slug := vars.Get("slug"); slug == "rst" {
+			return &article{slug, "JSON:API in rst"}, nil
+		}
+		return nil, NotFound()
+	}))
+
+	var test = func(path string, code int, v interface{}) {
+		r, _ := http.NewRequest(Get, path, nil)
+		r.Header.Set("Accept", JSONAPIMediaType)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != code {
+			t.Fatal(path, "status code. Got:", w.Code, "Wanted:", code)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != JSONAPIMediaType {
+			t.Fatal(path, "Content-Type. Got:", ct, "Wanted:", JSONAPIMediaType)
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+			t.Fatal(path, err)
+		}
+	}
+
+	var document struct {
+		Data *struct {
+			Type          string `json:"type"`
+			ID            string `json:"id"`
+			Attributes    map[string]string
+			Relationships map[string]struct {
+				Data map[string]string `json:"data"`
+			} `json:"relationships"`
+		} `json:"data"`
+	}
+	test("/articles/rst", http.StatusOK, &document)
+	if d := document.Data; d == nil || d.Type != "articles" || d.ID != "rst" || d.Attributes["title"] != "JSON:API in rst" {
+		t.Fatal("data. Got:", document.Data, "Wanted: the article rst")
+	}
+	if author := document.Data.Relationships["author"].Data; author["id"] != "9" {
+		t.Fatal("relationships. Got:", document.Data.Relationships, "Wanted: the author 9")
+	}
+
+	var errors struct {
+		Errors []map[string]string `json:"errors"`
+	}
+	test("/articles/unknown", http.StatusNotFound, &errors)
+	if len(errors.Errors) != 1 || errors.Errors[0]["status"] != "404" || errors.Errors[0]["title"] == "" {
+		t.Fatal("errors. Got:", errors.Errors, "Wanted: a 404 error object")
+	}
+}
@@ -0,0 +1,78 @@
+package rst
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// ItemStatus is the result of the operation applied to one of the items of a
+// bulk request. Body is the resource resulting from the operation, or the
+// error which made it fail.
+type ItemStatus struct {
+	ID     string      `json:"id,omitempty" xml:"ID,omitempty"`
+	Status int         `json:"status" xml:"Status"`
+	Body   interface{} `json:"body,omitempty" xml:"Body,omitempty"`
+}
+
+/*
+MultiStatus is a resource written with a 207 Multi-Status status code, whose
+payload is the list of the results of a bulk operation that may have partially
+succeeded.
+
+	func (ep *PeopleEP) Patch(vars rst.RouteVars, r *http.Request) (rst.Resource, error) {
+		var updates []*Person
+		if err := rst.DecodeJSON(r, &updates); err != nil {
+			return nil, err
+		}
+		var results rst.MultiStatus
+		for _, update := range updates {
+			p, err := database.UpdatePerson(update)
+			if err != nil {
+				results.Add(update.ID, http.StatusNotFound, rst.NotFound())
+				continue
+			}
+			results.Add(p.ID, http.StatusOK, p)
+		}
+		return results, nil
+	}
+
+Items are listed in the order in which they were added, and an empty MultiStatus
+is encoded as an empty list. The operations of a bulk request are rarely
+cacheable, so MultiStatus has no ETag, no last modification date, and a TTL of
+zero.
+*/
+type MultiStatus []*ItemStatus
+
+// Add appends the result of the operation applied to the item id to ms.
+func (ms *MultiStatus) Add(id string, status int, body interface{}) {
+	*ms = append(*ms, &ItemStatus{id, status, body})
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (ms MultiStatus) MarshalJSON() ([]byte, error) {
+	if ms == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]*ItemStatus(ms))
+}
+
+// ETag implements the rst.Resource interface.
+func (ms MultiStatus) ETag() string {
+	return ""
+}
+
+// LastModified implements the rst.Resource interface.
+func (ms MultiStatus) LastModified() time.Time {
+	return time.Time{}
+}
+
+// TTL implements the rst.Resource interface.
+func (ms MultiStatus) TTL() time.Duration {
+	return 0
+}
+
+// StatusCode implements the rst.StatusCoder interface.
+func (ms MultiStatus) StatusCode() int {
+	return http.StatusMultiStatus
+}
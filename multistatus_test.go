@@ -0,0 +1,73 @@
+package rst
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMultiStatus(t *testing.T) {
+	mux := NewMux()
+	mux.Handle("/people", postFunc(func(vars RouteVars, r *http.Request) (Resource, string, error) {
+		var results MultiStatus
+		results.Add("1", http.StatusOK, Text("updated"))
+		results.Add("2", http.StatusNotFound, NotFound())
+		return results, "", nil
+	}))
+
+	r, _ := http.NewRequest(Post, "/people", strings.NewReader("[]"))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	if w.Code != http.StatusMultiStatus {
+		t.Fatal("Status code. Got:", w.Code, "Wanted:", http.StatusMultiStatus)
+	}
+
+	var items []struct {
+		ID     string                 `json:"id"`
+		Status int                    `json:"status"`
+		Body   map[string]interface{} `json:"body"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &items); err != nil {
+		t.Fatal(err, w.Body.String())
+	}
+	var got []interface{}
+	for _, item := range items {
+		got = append(got, item.ID, item.Status)
+	}
+	if want := []interface{}{"1", 200, "2", 404}; !reflect.DeepEqual(got, want) {
+		t.Fatal("Items. Got:", got, "Wanted:", want)
+	}
+	if message := items[1].Body["message"]; message != NotFound().Reason {
+		t.Fatal("Error of the second item. Got:", message, "Wanted:", NotFound().Reason)
+	}
+
+	r.Header.Set("Accept", "application/xml")
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	if body := w.Body.String(); !strings.HasPrefix(body, xml.Header+"<MultiStatusList><ItemStatus><ID>1</ID><Status>200</Status>") {
+		t.Fatal("XML. Got:", body)
+	}
+}
+
+func TestEmptyMultiStatus(t *testing.T) {
+	mux := NewMux()
+	mux.Handle("/people", postFunc(func(vars RouteVars, r *http.Request) (Resource, string, error) {
+		var results MultiStatus
+		return results, "", nil
+	}))
+
+	r, _ := http.NewRequest(Post, "/people", strings.NewReader("[]"))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	if got := w.Body.String(); got != "[]" {
+		t.Fatal("Body. Got:", got, "Wanted: []")
+	}
+}
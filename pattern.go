@@ -0,0 +1,164 @@
+package rst
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// patternVar is a variable found in a route pattern.
+type patternVar struct {
+	start, end int    // Position of the braces in the pattern.
+	name       string // Name of the variable, without its optional marker.
+	expr       string // Regular expression constraining the variable, if any.
+	optional   bool
+}
+
+// patternVars returns the variables declared in pattern, such as {id} or
+// {id:[0-9]+}. Braces nested in the regular expression of a variable are
+// skipped.
+func patternVars(pattern string) (vars []patternVar) {
+	depth, start := 0, 0
+	for i, c := range pattern {
+		switch c {
+		case '{':
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case '}':
+			if depth--; depth == 0 {
+				name, expr := pattern[start+1:i], ""
+				if j := strings.Index(name, ":"); j >= 0 {
+					name, expr = name[:j], name[j+1:]
+				}
+				v := patternVar{start: start, end: i + 1, name: strings.TrimSpace(name), expr: expr}
+				if strings.HasSuffix(v.name, "?") {
+					v.name, v.optional = strings.TrimSuffix(v.name, "?"), true
+				}
+				vars = append(vars, v)
+			}
+		}
+	}
+	return vars
+}
+
+/*
+expandPattern returns the patterns to register in the router for pattern.
+
+The last segment of a pattern can be made optional with a question mark after
+the name of its variable:
+
+	/reports/{year?}
+
+matches both /reports and /reports/2024. The variable is absent from the
+RouteVars of requests to /reports, which can be checked with RouteVars.Has.
+
+expandPattern panics if an optional variable is not the whole last segment of
+pattern, since the routes it would match would be ambiguous.
+*/
+func expandPattern(pattern string) []string {
+	vars := patternVars(pattern)
+	for i, v := range vars {
+		if !v.optional {
+			continue
+		}
+		if i != len(vars)-1 || v.end != len(pattern) || v.start == 0 || pattern[v.start-1] != '/' {
+			panic(fmt.Errorf("rst: invalid pattern %s: only the last segment of a pattern can be optional", pattern))
+		}
+
+		marker := v.start + 1 + strings.Index(pattern[v.start+1:], "?")
+		required := pattern[:marker] + pattern[marker+1:]
+		prefix := pattern[:v.start-1]
+		if prefix == "" {
+			prefix = "/"
+		}
+		return []string{prefix, required}
+	}
+	return []string{pattern}
+}
+
+/*
+validatePattern panics if the regular expression constraining one of the
+variables of pattern can't be compiled.
+
+Variables can be constrained with a regular expression following their name:
+
+	/users/{id:[0-9]+}
+
+only matches /users/42, and not /users/abc. The expressions are compiled once,
+when the route is registered.
+*/
+func validatePattern(pattern string) {
+	for _, v := range patternVars(pattern) {
+		if v.expr == "" {
+			continue
+		}
+		if _, err := regexp.Compile(v.expr); err != nil {
+			panic(fmt.Errorf("rst: invalid pattern %s: regular expression of variable %s: %s", pattern, v.name, err))
+		}
+	}
+}
+
+// varExprs caches the regular expressions matching the whole values of
+// constrained variables, compiled by varExpr.
+var varExprs sync.Map
+
+// varExpr returns the compiled regular expression matching the whole values of
+// a variable constrained by expr.
+func varExpr(expr string) *regexp.Regexp {
+	if re, cached := varExprs.Load(expr); cached {
+		return re.(*regexp.Regexp)
+	}
+	re, _ := varExprs.LoadOrStore(expr, regexp.MustCompile("^(?:"+expr+")$"))
+	return re.(*regexp.Regexp)
+}
+
+// fillPattern returns the path matched by pattern with the values of vars.
+// An error is returned if a required variable is missing from vars, if it's
+// empty while unconstrained, or if its value doesn't match the regular
+// expression of the variable. Empty optional variables are left out. Values
+// are escaped, except for the slashes of variables spanning several segments.
+func fillPattern(pattern string, vars RouteVars) (string, error) {
+	var (
+		path string
+		last int
+	)
+	for _, v := range patternVars(pattern) {
+		value, exists := vars[v.name]
+		if exists && value == "" && v.optional {
+			exists = false
+		}
+		if !exists && v.optional {
+			// Optional variables are always the last segment of a pattern.
+			path += strings.TrimSuffix(pattern[last:v.start], "/")
+			if path == "" {
+				path = "/"
+			}
+			return path, nil
+		}
+		if !exists {
+			return "", fmt.Errorf("rst: variable %s of pattern %s is missing", v.name, pattern)
+		}
+		if v.expr == "" && value == "" {
+			return "", fmt.Errorf("rst: variable %s of pattern %s is empty", v.name, pattern)
+		}
+		if v.expr != "" && !varExpr(v.expr).MatchString(value) {
+			return "", fmt.Errorf("rst: value %q of variable %s doesn't match pattern %s", value, v.name, pattern)
+		}
+
+		escaped := url.PathEscape(value)
+		if spansSegments([]patternVar{v}) {
+			segments := strings.Split(value, "/")
+			for i, segment := range segments {
+				segments[i] = url.PathEscape(segment)
+			}
+			escaped = strings.Join(segments, "/")
+		}
+		path += pattern[last:v.start] + escaped
+		last = v.end
+	}
+	return path + pattern[last:], nil
+}
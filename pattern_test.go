@@ -0,0 +1,119 @@
+package rst
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestExpandPattern(t *testing.T) {
+	var test = func(pattern string, expected ...string) {
+		if got := expandPattern(pattern); !reflect.DeepEqual(got, expected) {
+			t.Fatal("Got:", got, "Wanted:", expected)
+		}
+	}
+	test("/reports/{year}", "/reports/{year}")
+	test("/reports/{year?}", "/reports", "/reports/{year}")
+	test("/{page?}", "/", "/{page}")
+	test("/reports/{year?:[0-9]{4}}", "/reports", "/reports/{year:[0-9]{4}}")
+	test("/codes/{code:[a-z]?[0-9]+}", "/codes/{code:[a-z]?[0-9]+}")
+
+	var panics = func(pattern string) {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("No panic for", pattern)
+			}
+		}()
+		expandPattern(pattern)
+	}
+	panics("/reports/{year?}/summary")
+	panics("/reports-{year?}")
+}
+
+type reportsEndpoint struct{}
+
+func (ep *reportsEndpoint) Get(vars RouteVars, r *http.Request) (Resource, error) {
+	year := vars.Get("year")
+	if !vars.Has("year") {
+		year = "current"
+	}
+	return Text(year), nil
+}
+
+func TestOptionalSegment(t *testing.T) {
+	mux := NewMux()
+	mux.HandleEndpoint("/reports/{year?}", &reportsEndpoint{})
+
+	var test = func(url, expected string) {
+		r, _ := newRequest("GET " + url + " HTTP/1.1\nHost: www.example.com\n\n")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatal(url, "Status code. Got:", w.Code, "Wanted:", http.StatusOK)
+		}
+		if got := w.Body.String(); got != expected {
+			t.Fatal(url, "Got:", got, "Wanted:", expected)
+		}
+	}
+	test("/reports", "current")
+	test("/reports/2024", "2024")
+}
+
+func TestConstrainedVariable(t *testing.T) {
+	mux := NewMux()
+	mux.HandleEndpoint("/users/{id:[0-9]+}", &textEndpoint{})
+
+	var test = func(url string, expected int) {
+		r, _ := newRequest("GET " + url + " HTTP/1.1\nHost: www.example.com\n\n")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal(url, "Status code. Got:", w.Code, "Wanted:", expected)
+		}
+	}
+	test("/users/42", http.StatusOK)
+	test("/users/abc", http.StatusNotFound)
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("No panic for an invalid regular expression")
+		}
+	}()
+	mux.HandleEndpoint("/users/{id:[0-9+}", &textEndpoint{})
+}
+
+func TestMuxURL(t *testing.T) {
+	mux := NewMux()
+	for _, pattern := range []string{"/users/{id}", "/codes/{code:[a-z]+-[0-9]+}", "/files/{path:.*}", "/reports/{year?}"} {
+		mux.Handle(pattern, http.NotFoundHandler())
+	}
+
+	var test = func(name string, vars RouteVars, expected string) {
+		got, err := mux.URL(name, vars)
+		if expected == "" {
+			if err == nil {
+				t.Fatal(name, vars, "Got:", got, "Wanted: an error")
+			}
+			return
+		}
+		if err != nil || got != expected {
+			t.Fatal(name, vars, "Got:", got, err, "Wanted:", expected)
+		}
+	}
+
+	test("/users/{id}", RouteVars{"id": "42"}, "/users/42")
+	test("/users/{id}", RouteVars{"id": "a b/c"}, "/users/a%20b%2Fc")
+	test("/users/{id}", RouteVars{}, "")
+	test("/users/{id}", RouteVars{"id": ""}, "")
+	test("/codes/{code:[a-z]+-[0-9]+}", RouteVars{"code": "abc-12"}, "/codes/abc-12")
+	test("/codes/{code:[a-z]+-[0-9]+}", RouteVars{"code": "12"}, "")
+	test("/files/{path:.*}", RouteVars{"path": "a/b c.txt"}, "/files/a/b%20c.txt")
+	test("/reports/{year?}", RouteVars{"year": "2016"}, "/reports/2016")
+	test("/reports/{year?}", RouteVars{}, "/reports")
+	test("/reports/{year?}", RouteVars{"year": ""}, "/reports")
+	test("/unregistered/{id}", RouteVars{"id": "42"}, "")
+
+	mux.ExternalURL = "https://api.example.com/"
+	test("/users/{id}", RouteVars{"id": "42"}, "https://api.example.com/users/42")
+}
@@ -0,0 +1,303 @@
+package rst
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+/*
+FieldSelection enables the filtering of JSON responses with the fields query
+parameter. By default, field selection is disabled.
+
+When enabled, a request to /people/1?fields=id,profile.email will only receive
+the id key of the encoded resource, and the email key nested in its profile
+object. Arrays are filtered element by element.
+
+Filtering is applied to the marshaled payload, and therefore works with any
+resource encoded in JSON.
+*/
+var FieldSelection = false
+
+// StrictFieldSelection makes rst respond with 400 Bad Request when a field
+// requested in the fields query parameter can't be found in the resource.
+// Unknown fields are silently ignored otherwise.
+var StrictFieldSelection = false
+
+const (
+	fieldsParam = "fields"
+	expandParam = "expand"
+)
+
+// parseListParam returns the values of the query parameter key in r, whether
+// they are separated by commas or passed in repeated parameters.
+func parseListParam(r *http.Request, key string) (values []string) {
+	for _, raw := range r.URL.Query()[key] {
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+/*
+ExpandParams returns the names of the related resources a client asked to be
+inlined in the response with the expand query parameter.
+
+Names can be separated by commas, or passed in repeated parameters:
+
+	GET /posts/1?expand=author,comments
+	GET /posts/1?expand=author&expand=comments
+
+rst doesn't load relations itself. It's up to the endpoint to decide what to
+embed in the resource it returns:
+
+	func (ep *PostEP) Get(vars rst.RouteVars, r *http.Request) (rst.Resource, error) {
+		expand, err := rst.ValidateExpandParams(r, "author", "comments")
+		if err != nil {
+			return nil, err
+		}
+		return database.FindPost(vars.Get("id"), expand...), nil
+	}
+
+Duplicates are removed, and the order of first appearance is preserved.
+*/
+func ExpandParams(r *http.Request) []string {
+	var (
+		values []string
+		seen   = make(map[string]bool)
+	)
+	for _, value := range parseListParam(r, expandParam) {
+		if !seen[value] {
+			seen[value] = true
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// ValidateExpandParams returns the values of ExpandParams, or a 400 Bad Request
+// error if one of them is not in the list of allowed relations.
+func ValidateExpandParams(r *http.Request, allowed ...string) ([]string, error) {
+	values := ExpandParams(r)
+	for _, value := range values {
+		if !contains(allowed, value) {
+			return nil, BadRequest("", fmt.Sprintf("%s can't be expanded. Supported values for the %s parameter: %s.", value, expandParam, strings.Join(allowed, ", ")))
+		}
+	}
+	return values, nil
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+BindQuery populates the struct pointed to by v with the query parameters of r.
+
+Fields are matched with the name set in their query tag, and a default value
+can be set in a default tag. Fields without a query tag, or unexported, are
+ignored.
+
+	type Filter struct {
+		Status []string `query:"status"`
+		Limit  int      `query:"limit" default:"20"`
+		Closed bool     `query:"closed"`
+	}
+
+	var filter Filter
+	if err := rst.BindQuery(r, &filter); err != nil {
+		return nil, err
+	}
+
+Strings, booleans, integers, floats, and slices of those types are supported.
+Slices are filled with repeated parameters (?status=open&status=pending).
+
+A 400 Bad Request error is returned when a value can't be converted to the
+type of its field.
+*/
+func BindQuery(r *http.Request, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("rst: BindQuery requires a non-nil pointer to a struct")
+	}
+	rv = rv.Elem()
+
+	query := r.URL.Query()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name := field.Tag.Get("query")
+		if name == "" || name == "-" || field.PkgPath != "" {
+			continue
+		}
+
+		values, exists := query[name]
+		if !exists || len(values) == 0 {
+			def := field.Tag.Get("default")
+			if def == "" {
+				continue
+			}
+			values = []string{def}
+			if field.Type.Kind() == reflect.Slice {
+				values = strings.Split(def, ",")
+			}
+		}
+
+		if err := bindValues(rv.Field(i), values); err != nil {
+			return BadRequest("", fmt.Sprintf("Query parameter %s is not a valid %s.", name, field.Type))
+		}
+	}
+	return nil
+}
+
+// bindValues converts values into the type of dst, and sets the result.
+func bindValues(dst reflect.Value, values []string) error {
+	if dst.Kind() != reflect.Slice {
+		return bindValue(dst, values[0])
+	}
+
+	slice := reflect.MakeSlice(dst.Type(), len(values), len(values))
+	for i, value := range values {
+		if err := bindValue(slice.Index(i), value); err != nil {
+			return err
+		}
+	}
+	dst.Set(slice)
+	return nil
+}
+
+func bindValue(dst reflect.Value, value string) error {
+	switch dst.Kind() {
+	case reflect.String:
+		dst.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		dst.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", dst.Type())
+	}
+	return nil
+}
+
+// selectFields returns a new JSON document with only the fields of b listed in
+// fields. Nested fields are designated with a dot-separated path. Empty and
+// null documents are returned as they are.
+func selectFields(b []byte, fields []string, strict bool) ([]byte, error) {
+	if trimmed := bytes.TrimSpace(b); len(trimmed) == 0 || bytes.Equal(trimmed, jsonNull) {
+		return b, nil
+	}
+	var paths [][]string
+	for _, f := range fields {
+		paths = append(paths, strings.Split(f, "."))
+	}
+
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	filtered, err := filterFields(v, "", paths, strict)
+	if err != nil {
+		return nil, err
+	}
+	return JSONMarshal(filtered)
+}
+
+func filterFields(v interface{}, prefix string, paths [][]string, strict bool) (interface{}, error) {
+	switch t := v.(type) {
+	case []interface{}:
+		items := make([]interface{}, len(t))
+		for i, item := range t {
+			filtered, err := filterFields(item, prefix, paths, strict)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = filtered
+		}
+		return items, nil
+	case map[string]interface{}:
+		var (
+			keys     []string
+			whole    = make(map[string]bool)
+			children = make(map[string][][]string)
+		)
+		for _, path := range paths {
+			key := path[0]
+			if _, exists := children[key]; !exists && !whole[key] {
+				keys = append(keys, key)
+			}
+			if len(path) == 1 {
+				whole[key] = true
+			} else {
+				children[key] = append(children[key], path[1:])
+			}
+		}
+
+		object := make(map[string]interface{})
+		for _, key := range keys {
+			value, exists := t[key]
+			if !exists {
+				if strict {
+					return nil, unknownField(prefix + key)
+				}
+				continue
+			}
+			if whole[key] {
+				object[key] = value
+				continue
+			}
+			filtered, err := filterFields(value, prefix+key+".", children[key], strict)
+			if err != nil {
+				return nil, err
+			}
+			object[key] = filtered
+		}
+		return object, nil
+	}
+
+	// Nested fields were requested on a value that is not an object.
+	if strict && len(paths) > 0 {
+		return nil, unknownField(prefix + strings.Join(paths[0], "."))
+	}
+	return v, nil
+}
+
+func unknownField(name string) *Error {
+	return BadRequest("", fmt.Sprintf("Field %s requested in the %s parameter does not exist.", name, fieldsParam))
+}
@@ -0,0 +1,166 @@
+package rst
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+)
+
+func TestSelectFields(t *testing.T) {
+	doc := []byte(`{"id":1,"name":"Francis","profile":{"email":"francis@example.com","phone":"555"}}`)
+
+	b, err := selectFields(doc, []string{"id", "profile.email"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"id":1,"profile":{"email":"francis@example.com"}}`; string(b) != expected {
+		t.Fatal("Got:", string(b), "Wanted:", expected)
+	}
+
+	list := []byte(`[{"id":1,"name":"a"},{"id":2,"name":"b"}]`)
+	b, err = selectFields(list, []string{"name"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `[{"name":"a"},{"name":"b"}]`; string(b) != expected {
+		t.Fatal("Got:", string(b), "Wanted:", expected)
+	}
+
+	if _, err := selectFields(doc, []string{"profile.address"}, false); err != nil {
+		t.Fatal("unknown field with strict=false:", err)
+	}
+	for _, empty := range []string{"", "null"} {
+		if b, err := selectFields([]byte(empty), []string{"id"}, true); err != nil || string(b) != empty {
+			t.Fatal("Empty document", empty, "Got:", string(b), err, "Wanted:", empty)
+		}
+	}
+
+	_, err = selectFields(doc, []string{"profile.address"}, true)
+	if e, valid := err.(*Error); !valid || e.Code != http.StatusBadRequest {
+		t.Fatalf("Expecting error with code %d. Got: %s", http.StatusBadRequest, err)
+	}
+}
+
+func TestSelectFieldsJSONMarshal(t *testing.T) {
+	defer func(original func(interface{}) ([]byte, error)) { JSONMarshal = original }(JSONMarshal)
+	JSONMarshal = func(v interface{}) ([]byte, error) {
+		return []byte(`"custom"`), nil
+	}
+
+	b, err := selectFields([]byte(`{"id":1,"name":"Francis"}`), []string{"id"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `"custom"` {
+		t.Fatal("JSONMarshal was not used. Got:", string(b), "Wanted:", `"custom"`)
+	}
+}
+
+func TestFieldSelection(t *testing.T) {
+	defer func() {
+		FieldSelection = false
+		StrictFieldSelection = false
+	}()
+	FieldSelection = true
+
+	p := testPeople[len(testPeople)-1]
+	var test = func(fields string, expected int) *requestResponse {
+		header := make(http.Header)
+		header.Set("Accept", "application/json")
+		rr := newRequestResponse(Get, testServerAddr+"/people/"+p.ID+"?fields="+fields, header, nil)
+		if err := rr.TestStatusCode(expected); err != nil {
+			t.Fatal(err)
+		}
+		return rr
+	}
+
+	rr := test("_id,employer.company", http.StatusOK)
+	body := `{"_id":"` + p.ID + `","employer":{"company":"` + p.Employer.Company + `"}}`
+	if err := rr.TestBody(bytes.NewBufferString(body)); err != nil {
+		t.Fatal(err)
+	}
+
+	test("_id,employer.address", http.StatusOK)
+	StrictFieldSelection = true
+	test("_id,employer.address", http.StatusBadRequest)
+}
+
+func TestExpandParams(t *testing.T) {
+	var test = func(query string, expected []string) {
+		r, err := newRequest("GET /posts/1?" + query + " HTTP/1.1\nHost: www.example.com\n\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := ExpandParams(r)
+		if len(got) != len(expected) {
+			t.Fatalf("%s: Got: %v Wanted: %v", query, got, expected)
+		}
+		for i := range got {
+			if got[i] != expected[i] {
+				t.Fatalf("%s: Got: %v Wanted: %v", query, got, expected)
+			}
+		}
+	}
+
+	test("", nil)
+	test("expand=author", []string{"author"})
+	test("expand=author,comments", []string{"author", "comments"})
+	test("expand=author&expand=comments", []string{"author", "comments"})
+	test("expand=author,%20comments&expand=tags,author", []string{"author", "comments", "tags"})
+	test("expand=,", nil)
+}
+
+func TestValidateExpandParams(t *testing.T) {
+	r, _ := newRequest("GET /posts/1?expand=author,comments HTTP/1.1\nHost: www.example.com\n\n")
+	if _, err := ValidateExpandParams(r, "author", "comments", "tags"); err != nil {
+		t.Fatal(err)
+	}
+	_, err := ValidateExpandParams(r, "author")
+	if e, valid := err.(*Error); !valid || e.Code != http.StatusBadRequest {
+		t.Fatalf("Expecting error with code %d. Got: %s", http.StatusBadRequest, err)
+	}
+}
+
+func TestBindQuery(t *testing.T) {
+	type filter struct {
+		Limit  int      `query:"limit" default:"20"`
+		Closed bool     `query:"closed"`
+		Status []string `query:"status"`
+		Label  string
+	}
+
+	r, _ := newRequest("GET /issues?limit=5&closed=true&status=open&status=pending HTTP/1.1\nHost: www.example.com\n\n")
+	var f filter
+	if err := BindQuery(r, &f); err != nil {
+		t.Fatal(err)
+	}
+	if f.Limit != 5 {
+		t.Error("Limit. Got:", f.Limit, "Wanted:", 5)
+	}
+	if !f.Closed {
+		t.Error("Closed. Got:", f.Closed, "Wanted:", true)
+	}
+	if len(f.Status) != 2 || f.Status[0] != "open" || f.Status[1] != "pending" {
+		t.Error("Status. Got:", f.Status, "Wanted:", []string{"open", "pending"})
+	}
+
+	// Defaults
+	r, _ = newRequest("GET /issues HTTP/1.1\nHost: www.example.com\n\n")
+	f = filter{}
+	if err := BindQuery(r, &f); err != nil {
+		t.Fatal(err)
+	}
+	if f.Limit != 20 {
+		t.Error("default Limit. Got:", f.Limit, "Wanted:", 20)
+	}
+
+	// Errors
+	r, _ = newRequest("GET /issues?limit=ten HTTP/1.1\nHost: www.example.com\n\n")
+	err := BindQuery(r, &f)
+	if e, valid := err.(*Error); !valid || e.Code != http.StatusBadRequest {
+		t.Errorf("Expecting error with code %d. Got: %s", http.StatusBadRequest, err)
+	}
+	if err := BindQuery(r, f); err == nil {
+		t.Error("expected an error when binding to a non-pointer")
+	}
+}
@@ -0,0 +1,104 @@
+package rst
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+/*
+Redirection is returned by endpoints in place of an error to redirect clients
+to another URL.
+
+	func (ep *PeopleEP) Put(vars rst.RouteVars, r *http.Request) (rst.Resource, error) {
+		return nil, rst.Redirect(http.StatusPermanentRedirect, "/v2/people/"+vars.Get("id"))
+	}
+
+Clients may change the method of the request to GET when following a 301
+Moved Permanently or a 302 Found redirection, and always do so with 303 See
+Other. 307 Temporary Redirect and 308 Permanent Redirect preserve the method
+and the body of the request, which makes them the only safe redirections of
+POST, PUT, PATCH, and DELETE requests. rst doesn't read the body of redirected
+requests, so that clients can send it again to Location.
+*/
+type Redirection struct {
+	Code     int
+	Location string
+}
+
+// Redirect returns a redirection to location with code, which must be one of
+// 301, 302, 303, 307, or 308. Redirect panics otherwise.
+func Redirect(code int, location string) *Redirection {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+	default:
+		panic(fmt.Sprintf("rst: %d is not a valid redirection status code", code))
+	}
+	return &Redirection{code, location}
+}
+
+func (rd *Redirection) Error() string {
+	return fmt.Sprintf("%d %s: %s", rd.Code, http.StatusText(rd.Code), rd.Location)
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (rd *Redirection) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Headers set for a successful response don't apply to the redirection.
+	w.Header().Del("Last-Modified")
+	w.Header().Del("ETag")
+	w.Header().Del("Expires")
+
+	w.Header().Set("Location", rd.Location)
+	w.WriteHeader(rd.Code)
+}
+
+// Alternative is a representation of a resource listed in a MultipleChoices,
+// which can be fetched from URL.
+type Alternative struct {
+	Type string `json:"type" xml:"Type"`
+	URL  string `json:"url" xml:"URL"`
+}
+
+// MultipleChoices is returned by MarshalResource in place of an error when the
+// representations of a resource are equally acceptable to the client, and
+// NegotiateMultipleChoices is enabled. It's written in a 300 Multiple Choices
+// response listing the alternatives, with a Link header for each one of them.
+type MultipleChoices struct {
+	XMLName      xml.Name       `json:"-" xml:"MultipleChoices"`
+	Alternatives []*Alternative `json:"alternatives" xml:"Alternative"`
+}
+
+func (mc *MultipleChoices) Error() string {
+	types := make([]string, len(mc.Alternatives))
+	for i, alternative := range mc.Alternatives {
+		types[i] = alternative.Type
+	}
+	return fmt.Sprintf("%d %s: %s", http.StatusMultipleChoices, http.StatusText(http.StatusMultipleChoices), strings.Join(types, ", "))
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (mc *MultipleChoices) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Headers set for a successful response don't apply to the choices.
+	w.Header().Del("Last-Modified")
+	w.Header().Del("ETag")
+	w.Header().Del("Expires")
+
+	for _, alternative := range mc.Alternatives {
+		w.Header().Add("Link", fmt.Sprintf(`<%s>; rel="alternate"; type="%s"`, alternative.URL, alternative.Type))
+	}
+	// The list is only written if the client accepts one of its encodings.
+	contentType, b, err := MarshalResource(mc, r)
+	if err != nil {
+		w.WriteHeader(http.StatusMultipleChoices)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+	w.WriteHeader(http.StatusMultipleChoices)
+	if strings.ToUpper(r.Method) != Head {
+		w.Write(b)
+	}
+}
@@ -0,0 +1,51 @@
+package rst
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedirect(t *testing.T) {
+	var called bool
+	mux := NewMux()
+	mux.Handle("/people/{id}", putFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return nil, Redirect(http.StatusPermanentRedirect, "/v2/people/"+vars.Get("id"))
+	}))
+	mux.Handle("/v2/people/{id}", putFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		called = r.Method == Put
+		return nil, nil
+	}))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	r, _ := http.NewRequest(Put, server.URL+"/people/42", strings.NewReader(testCannedContent))
+	client := &http.Client{CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if req.Method != Put {
+			t.Fatal("redirected method. Got:", req.Method, "Wanted:", Put)
+		}
+		if loc := req.Response.Header.Get("Location"); loc != "/v2/people/42" {
+			t.Fatal("Location. Got:", loc, "Wanted: /v2/people/42")
+		}
+		if code := req.Response.StatusCode; code != http.StatusPermanentRedirect {
+			t.Fatal("status code. Got:", code, "Wanted:", http.StatusPermanentRedirect)
+		}
+		return nil
+	}}
+	resp, err := client.Do(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !called {
+		t.Fatal("redirected PUT. Got:", resp.StatusCode, called, "Wanted:", http.StatusOK, true)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Redirect should panic with a status code which isn't a redirection")
+		}
+	}()
+	Redirect(http.StatusOK, "/")
+}
@@ -0,0 +1,70 @@
+package rst
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/gorilla/context"
+)
+
+// DefaultRequestIDHeader is the header in which request IDs are read and
+// written when Mux.RequestIDHeader is empty.
+const DefaultRequestIDHeader = "X-Request-ID"
+
+const requestIDKey = "__rst__requestID"
+
+var (
+	uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	ulidPattern = regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Za-hjkmnp-tv-z]{25}$`)
+)
+
+// RequestID returns the ID of r, or an empty string if r is not served by a
+// Mux with PropagateRequestID enabled.
+func RequestID(r *http.Request) string {
+	id, _ := context.Get(r, requestIDKey).(string)
+	return id
+}
+
+func setRequestID(r *http.Request, id string) {
+	context.Set(r, requestIDKey, id)
+}
+
+// requestID returns the ID sent by the client in the request ID header of s,
+// or a new one if it's missing or is neither a UUID nor a ULID. The ID is
+// stored for r, and echoed in the response.
+func (s *Mux) requestID(w http.ResponseWriter, r *http.Request) string {
+	header := s.RequestIDHeader
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+	id := r.Header.Get(header)
+	if !uuidPattern.MatchString(id) && !ulidPattern.MatchString(id) {
+		id = newUUID()
+	}
+	setRequestID(r, id)
+	w.Header().Set(header, id)
+	return id
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// Unique enough to correlate the logs of a single server.
+		return newErrorID() + "-" + newErrorID()
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// errorID returns the correlation ID of an error returned for r, which is the
+// ID of r when it has one.
+func errorID(r *http.Request) string {
+	if id := RequestID(r); id != "" {
+		return id
+	}
+	return newErrorID()
+}
@@ -0,0 +1,71 @@
+package rst
+
+import (
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	var got string
+	mux := NewMux()
+	mux.Logger = log.New(ioutil.Discard, "", log.Ltime)
+	mux.PropagateRequestID = true
+	mux.Handle("/people/1", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		got = RequestID(r)
+		return testPeople[len(testPeople)-1], nil
+	}))
+	mux.Handle("/failure", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return nil, errors.New("failure")
+	}))
+
+	var test = func(path, header, sent string) *httptest.ResponseRecorder {
+		got = ""
+		r, _ := http.NewRequest(Get, path, nil)
+		if sent != "" {
+			r.Header.Set(header, sent)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		id := w.Header().Get(header)
+		if sent != "" && id != sent {
+			t.Fatal(path, "request ID. Got:", id, "Wanted:", sent)
+		}
+		if !uuidPattern.MatchString(id) && !ulidPattern.MatchString(id) {
+			t.Fatal(path, "request ID. Got:", id, "Wanted: a UUID or a ULID")
+		}
+		if w.Code == http.StatusOK && got != id {
+			t.Fatal(path, "RequestID. Got:", got, "Wanted:", id)
+		}
+		return w
+	}
+
+	// Generated when absent.
+	generated := test("/people/1", DefaultRequestIDHeader, "").Header().Get(DefaultRequestIDHeader)
+	if other := test("/people/1", DefaultRequestIDHeader, "").Header().Get(DefaultRequestIDHeader); other == generated {
+		t.Fatal("Request IDs must be unique. Got:", other, "twice")
+	}
+	// Echoed when present.
+	test("/people/1", DefaultRequestIDHeader, "3b241101-e2bb-4255-8caf-4136c566a962")
+	test("/people/1", DefaultRequestIDHeader, "01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	// Replaced when invalid.
+	r, _ := http.NewRequest(Get, "/people/1", nil)
+	r.Header.Set(DefaultRequestIDHeader, "<script>")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	if id := w.Header().Get(DefaultRequestIDHeader); !uuidPattern.MatchString(id) {
+		t.Fatal("Invalid request ID. Got:", id, "Wanted: a new UUID")
+	}
+
+	// Internal server errors are correlated with the request.
+	w = test("/failure", DefaultRequestIDHeader, "01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	if id := w.Header().Get("X-Error-ID"); id != "01ARZ3NDEKTSV4RRFFQ69G5FAV" {
+		t.Fatal("X-Error-ID. Got:", id, "Wanted: 01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	}
+
+	mux.RequestIDHeader = "X-Correlation-ID"
+	test("/people/1", "X-Correlation-ID", "3b241101-e2bb-4255-8caf-4136c566a962")
+}
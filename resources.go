@@ -0,0 +1,570 @@
+package rst
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+// statusResource is a resource written with a custom status code.
+type statusResource struct {
+	Resource
+	code int
+}
+
+/*
+WithStatus returns a resource that will be written with the given status code
+instead of the one rst would have picked. A nil resource results in a response
+with an empty body.
+
+	func (ep *JobsEP) Post(vars rst.RouteVars, r *http.Request) (rst.Resource, string, error) {
+		job := queue.Start(r)
+		return rst.WithStatus(http.StatusAccepted, nil), "/jobs/" + job.ID, nil
+	}
+*/
+func WithStatus(code int, resource Resource) Resource {
+	return &statusResource{resource, code}
+}
+
+// StatusCode implements the rst.StatusCoder interface.
+func (s *statusResource) StatusCode() int {
+	return s.code
+}
+
+// partialResource is a part of a resource written with its Content-Range.
+type partialResource struct {
+	Resource
+	contentRange *ContentRange
+}
+
+/*
+Partial returns a resource that will be written in a 206 Partial Content
+response, with cr as the value of the Content-Range header. It lets endpoints
+answer with the part of a resource they changed, rather than with the whole
+resource.
+
+	func (ep *DocumentEP) Patch(vars rst.RouteVars, r *http.Request) (rst.Resource, error) {
+		doc := database.FindDocument(vars.Get("id"))
+		from, to := doc.Splice(r.Body)
+		cr := &rst.ContentRange{&rst.Range{Unit: "bytes", From: from, To: to}, doc.Size()}
+		return rst.Partial(cr, rst.Blob("text/plain", doc.Bytes()[from:to+1])), nil
+	}
+
+part must be the representation of the range described by cr. The metadata of
+part, like its ETag, should be the ones of the whole resource, since they're
+written in the response as they are.
+
+Partial responses are never compressed, and their status code can be changed
+with WithStatus, for clients which don't expect a 206 outside of range
+requests.
+*/
+func Partial(cr *ContentRange, part Resource) Resource {
+	return &partialResource{part, cr}
+}
+
+// delegatedResource is a resource whose response is entirely written by a
+// handler.
+type delegatedResource struct {
+	handler http.Handler
+}
+
+/*
+Delegate returns a resource whose response is written by h, as if h had been
+registered for the route of the request. rst doesn't negotiate the
+representation of the resource, doesn't evaluate the preconditions of the
+request, and doesn't write any of the headers it derives from resources,
+leaving h in total control of the response.
+
+	func (ep *AssetsEP) Get(vars rst.RouteVars, r *http.Request) (rst.Resource, error) {
+		if !ep.Published(vars.Get("path")) {
+			return nil, rst.NotFound()
+		}
+		return rst.Delegate(http.StripPrefix("/assets", ep.fileServer)), nil
+	}
+
+The headers set by the mux before the endpoint is called, like the ones of
+Mux.Header, are still written with the response.
+*/
+func Delegate(h http.Handler) Resource {
+	return &delegatedResource{h}
+}
+
+// ETag implements the rst.Resource interface.
+func (d *delegatedResource) ETag() string {
+	return ""
+}
+
+// LastModified implements the rst.Resource interface.
+func (d *delegatedResource) LastModified() time.Time {
+	return time.Time{}
+}
+
+// TTL implements the rst.Resource interface.
+func (d *delegatedResource) TTL() time.Duration {
+	return 0
+}
+
+/*
+WithCache returns a resource that encodes v with the given caching metadata.
+It saves the declaration of a type implementing Resource when the metadata of
+a plain value are already known.
+
+	func (ep *ProfileEP) Get(vars rst.RouteVars, r *http.Request) (rst.Resource, error) {
+		p := database.FindProfile(vars.Get("id"))
+		return rst.WithCache(p, p.Version, p.UpdatedAt, time.Minute), nil
+	}
+
+WithCache is a shorthand for NewEnvelope.
+*/
+func WithCache(v interface{}, etag string, lastModified time.Time, ttl time.Duration) Resource {
+	return NewEnvelope(v, lastModified, etag, ttl)
+}
+
+// WithContentCache is like WithCache, but derives the ETag of the resource
+// from the canonical JSON encoding of v with ContentETag. An error is returned
+// if v can't be encoded in JSON.
+func WithContentCache(v interface{}, lastModified time.Time, ttl time.Duration) (Resource, error) {
+	etag, err := ContentETag(v)
+	if err != nil {
+		return nil, err
+	}
+	return WithCache(v, etag, lastModified, ttl), nil
+}
+
+// ModTimeETag returns a weak ETag derived from the modification date t, like
+// W/"1397469600000000000", or an empty string if t is zero. Two resources with
+// the same modification date have the same ETag.
+func ModTimeETag(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf(`W/"%d"`, t.UnixNano())
+}
+
+/*
+Timestamped implements the ETag and LastModified methods of Resource for types
+which only track their modification date, and is meant to be embedded:
+
+	type Note struct {
+		rst.Timestamped
+		Text string `json:"text"`
+	}
+
+	func (n *Note) TTL() time.Duration {
+		return 0
+	}
+
+	note := &Note{rst.Timestamped{ModTime: time.Now()}, "Hello"}
+
+The ETag is weak, and derived from ModTime with ModTimeETag, which gives
+conditional requests a validator without hashing the encoded resource.
+*/
+type Timestamped struct {
+	ModTime time.Time `json:"-" xml:"-"`
+}
+
+// ETag implements the rst.Resource interface.
+func (ts Timestamped) ETag() string {
+	return ModTimeETag(ts.ModTime)
+}
+
+// LastModified implements the rst.Resource interface.
+func (ts Timestamped) LastModified() time.Time {
+	return ts.ModTime
+}
+
+// blob is a resource made of raw bytes served with a fixed content type.
+type blob struct {
+	contentType  string
+	data         []byte
+	etag         string
+	lastModified time.Time
+}
+
+/*
+Blob returns a resource that writes data as is in the payload of the response,
+with contentType as the value of the Content-Type header.
+
+	func (ep *InvoiceEP) Get(vars rst.RouteVars, r *http.Request) (rst.Resource, error) {
+		b, err := ioutil.ReadFile("invoices/" + vars.Get("id") + ".pdf")
+		if err != nil {
+			return nil, rst.NotFound()
+		}
+		return rst.Blob("application/pdf", b), nil
+	}
+
+The ETag of the resource is derived from data, its TTL is zero, and its last
+modification date is the time at which Blob was called.
+
+Blob implements Ranger, and supports partial requests in bytes.
+*/
+func Blob(contentType string, data []byte) Resource {
+	return &blob{
+		contentType:  contentType,
+		data:         data,
+		etag:         fmt.Sprintf("\"%x\"", sha1.Sum(data)),
+		lastModified: time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+// Text returns a resource encoded as s, with the text/plain content type.
+// Its ETag is derived from s, which makes it suitable for conditional
+// requests.
+//
+//	return rst.Text("OK"), nil
+func Text(s string) Resource {
+	return Blob("text/plain; charset=utf-8", []byte(s))
+}
+
+// HTML returns a resource encoded as s, with the text/html content type.
+// Its ETag is derived from s, which makes it suitable for conditional
+// requests.
+func HTML(s string) Resource {
+	return Blob("text/html; charset=utf-8", []byte(s))
+}
+
+/*
+RawJSON returns a resource whose JSON encoding is data, written as is without
+being decoded and encoded again. Clients which don't accept JSON receive a 406
+Not Acceptable error.
+
+	func (ep *ReportEP) Get(vars rst.RouteVars, r *http.Request) (rst.Resource, error) {
+		b, err := cache.Get("reports/" + vars.Get("id"))
+		if err != nil {
+			return nil, rst.NotFound()
+		}
+		return rst.RawJSON(b), nil
+	}
+
+As with Blob, the ETag of the resource is derived from data, its TTL is zero,
+and its last modification date is the time at which RawJSON was called.
+*/
+func RawJSON(data []byte) Resource {
+	return NewEnvelope(
+		json.RawMessage(data),
+		time.Now().UTC().Truncate(time.Second),
+		fmt.Sprintf("\"%x\"", sha1.Sum(data)),
+		0,
+	)
+}
+
+// ETag implements the rst.Resource interface.
+func (b *blob) ETag() string {
+	return b.etag
+}
+
+// LastModified implements the rst.Resource interface.
+func (b *blob) LastModified() time.Time {
+	return b.lastModified
+}
+
+// TTL implements the rst.Resource interface.
+func (b *blob) TTL() time.Duration {
+	return 0
+}
+
+// MarshalRST implements the rst.Marshaler interface.
+func (b *blob) MarshalRST(r *http.Request) (string, []byte, error) {
+	return b.contentType, b.data, nil
+}
+
+// Units implements the rst.Ranger interface.
+func (b *blob) Units() []string {
+	return []string{"bytes"}
+}
+
+// Count implements the rst.Ranger interface.
+func (b *blob) Count() uint64 {
+	return uint64(len(b.data))
+}
+
+// Range implements the rst.Ranger interface.
+func (b *blob) Range(rg *Range) (*ContentRange, Resource, error) {
+	part := &blob{
+		contentType:  b.contentType,
+		data:         b.data[rg.From : rg.To+1],
+		etag:         b.etag,
+		lastModified: b.lastModified,
+	}
+	return &ContentRange{rg, b.Count()}, part, nil
+}
+
+// readerResource is a resource streaming the data of a reader.
+type readerResource struct {
+	contentType string
+	reader      io.Reader
+}
+
+/*
+Stream returns a resource that copies the data of reader in the response as it
+is read, without buffering it, with contentType as the value of the
+Content-Type header.
+
+	func (ep *ArchiveEP) Get(vars rst.RouteVars, r *http.Request) (rst.Resource, error) {
+		f, err := os.Open("archives/" + vars.Get("id") + ".tar")
+		if err != nil {
+			return nil, rst.NotFound()
+		}
+		return rst.Stream("application/x-tar", f), nil
+	}
+
+reader is closed once the response has been written if it implements
+io.Closer. See the Streamer interface for details.
+*/
+func Stream(contentType string, reader io.Reader) Resource {
+	return &readerResource{contentType, reader}
+}
+
+// ETag implements the rst.Resource interface.
+func (rr *readerResource) ETag() string {
+	return ""
+}
+
+// LastModified implements the rst.Resource interface.
+func (rr *readerResource) LastModified() time.Time {
+	return time.Time{}
+}
+
+// TTL implements the rst.Resource interface.
+func (rr *readerResource) TTL() time.Duration {
+	return 0
+}
+
+// ContentType implements the rst.Streamer interface.
+func (rr *readerResource) ContentType() string {
+	return rr.contentType
+}
+
+// Stream implements the rst.Streamer interface.
+func (rr *readerResource) Stream(w io.Writer) error {
+	_, err := io.Copy(w, rr.reader)
+	return err
+}
+
+// Close closes the reader of rr if it implements io.Closer.
+func (rr *readerResource) Close() error {
+	if closer, implemented := rr.reader.(io.Closer); implemented {
+		return closer.Close()
+	}
+	return nil
+}
+
+// sectionResource is a resource reading its data from a section of a reader.
+type sectionResource struct {
+	contentType  string
+	reader       io.ReaderAt
+	offset, size int64
+	etag         string
+	lastModified time.Time
+}
+
+/*
+ReaderAt returns a resource reading size bytes from reader, served with
+contentType as the value of the Content-Type header. Responses are streamed
+from reader without being buffered, with a Content-Length header unless they
+are compressed, and range requests in bytes only read the requested span,
+which makes it suitable to serve large files.
+
+	var video, _ = os.Open("videos/intro.mp4")
+
+	func (ep *VideoEP) Get(vars rst.RouteVars, r *http.Request) (rst.Resource, error) {
+		info, err := video.Stat()
+		if err != nil {
+			return nil, err
+		}
+		return rst.ReaderAt("video/mp4", video, info.Size(), info.ModTime()), nil
+	}
+
+The ETag of the resource is derived from size and modTime, and its last
+modification date is modTime. Its TTL is zero.
+
+reader isn't closed by rst, so that it can be shared by concurrent requests.
+*/
+func ReaderAt(contentType string, reader io.ReaderAt, size int64, modTime time.Time) Resource {
+	sr := &sectionResource{
+		contentType:  contentType,
+		reader:       reader,
+		size:         size,
+		lastModified: modTime.UTC().Truncate(time.Second),
+	}
+	if !modTime.IsZero() {
+		sr.etag = fmt.Sprintf("\"%x-%x\"", modTime.UnixNano(), size)
+	}
+	return sr
+}
+
+// ETag implements the rst.Resource interface.
+func (sr *sectionResource) ETag() string {
+	return sr.etag
+}
+
+// LastModified implements the rst.Resource interface.
+func (sr *sectionResource) LastModified() time.Time {
+	return sr.lastModified
+}
+
+// TTL implements the rst.Resource interface.
+func (sr *sectionResource) TTL() time.Duration {
+	return 0
+}
+
+// ContentType implements the rst.Streamer interface.
+func (sr *sectionResource) ContentType() string {
+	return sr.contentType
+}
+
+// Stream implements the rst.Streamer interface.
+func (sr *sectionResource) Stream(w io.Writer) error {
+	_, err := io.Copy(w, io.NewSectionReader(sr.reader, sr.offset, sr.size))
+	return err
+}
+
+// Size implements the rst.SizedStreamer interface.
+func (sr *sectionResource) Size() int64 {
+	return sr.size
+}
+
+// Units implements the rst.Ranger interface.
+func (sr *sectionResource) Units() []string {
+	return []string{"bytes"}
+}
+
+// Count implements the rst.Ranger interface.
+func (sr *sectionResource) Count() uint64 {
+	return uint64(sr.size)
+}
+
+// Range implements the rst.Ranger interface.
+func (sr *sectionResource) Range(rg *Range) (*ContentRange, Resource, error) {
+	part := &sectionResource{
+		contentType:  sr.contentType,
+		reader:       sr.reader,
+		offset:       sr.offset + int64(rg.From),
+		size:         int64(rg.To-rg.From) + 1,
+		etag:         sr.etag,
+		lastModified: sr.lastModified,
+	}
+	return &ContentRange{rg, sr.Count()}, part, nil
+}
+
+/*
+Collection is a resource made of a list of items, which can be requested in
+parts with the items range unit.
+
+	func (ep *PeopleEP) Get(vars rst.RouteVars, r *http.Request) (rst.Resource, error) {
+		people, total := database.ListPeople()
+		return rst.NewCollection(people, 0, total, time.Time{}, "", 0), nil
+	}
+
+A request with the header Range: items=0-9 receives the first ten items of the
+collection in a 206 Partial Content response, with a Content-Range header like
+items 0-9/25. The total number of items is always sent in the X-Total-Count
+header, including in full responses.
+
+A collection can hold a single page of a larger list, for endpoints which
+paginate their queries. It then only serves the ranges that fall within the
+items it holds, and answers other range requests with the full page. The range
+requested by the client can be read with ParseRange before querying the items:
+
+	rg, err := rst.ParseRange(r.Header.Get("Range"))
+	if err != nil || rg.Unit != "items" {
+		rg = &rst.Range{Unit: "items", From: 0, To: 99}
+	}
+	people, total := database.ListPeople(rg.From, rg.To)
+	return rst.NewCollection(people, rg.From, total, time.Time{}, "", 0), nil
+*/
+type Collection struct {
+	items        reflect.Value
+	offset       uint64
+	total        uint64
+	lastModified time.Time
+	etag         string
+	ttl          time.Duration
+}
+
+// NewCollection returns a collection holding the items of the slice items,
+// which start at position offset of a list of total items. lastModified,
+// etag, and ttl are used like in NewEnvelope.
+//
+// NewCollection panics if items is not a slice.
+func NewCollection(items interface{}, offset, total uint64, lastModified time.Time, etag string, ttl time.Duration) *Collection {
+	v := reflect.ValueOf(items)
+	if v.Kind() != reflect.Slice {
+		panic(fmt.Sprintf("rst: items of a collection must be a slice, not %T", items))
+	}
+	// An empty collection is encoded as an empty list, not as null.
+	if v.IsNil() {
+		v = reflect.MakeSlice(v.Type(), 0, 0)
+	}
+	return &Collection{
+		items:        v,
+		offset:       offset,
+		total:        total,
+		lastModified: lastModified,
+		etag:         etag,
+		ttl:          ttl,
+	}
+}
+
+// Items returns the slice of items held by c.
+func (c *Collection) Items() interface{} {
+	return c.items.Interface()
+}
+
+// Total returns the number of items in the whole collection.
+func (c *Collection) Total() uint64 {
+	return c.total
+}
+
+// ETag implements the rst.Resource interface.
+func (c *Collection) ETag() string {
+	return c.etag
+}
+
+// LastModified implements the rst.Resource interface.
+func (c *Collection) LastModified() time.Time {
+	return c.lastModified
+}
+
+// TTL implements the rst.Resource interface.
+func (c *Collection) TTL() time.Duration {
+	return c.ttl
+}
+
+// MarshalRST implements the rst.Marshaler interface, and marshals the items of
+// c as a list.
+func (c *Collection) MarshalRST(r *http.Request) (string, []byte, error) {
+	return Marshal(c.items.Interface(), r)
+}
+
+// Units implements the rst.Ranger interface.
+func (c *Collection) Units() []string {
+	return []string{"items"}
+}
+
+// Count implements the rst.Ranger interface.
+func (c *Collection) Count() uint64 {
+	return c.total
+}
+
+// Range implements the rst.Ranger interface. ErrRangeUnavailable is returned
+// for ranges which are not held by c.
+func (c *Collection) Range(rg *Range) (*ContentRange, Resource, error) {
+	end := c.offset + uint64(c.items.Len())
+	if rg.From < c.offset || rg.To >= end {
+		return nil, nil, ErrRangeUnavailable
+	}
+	part := &Collection{
+		items:        c.items.Slice(int(rg.From-c.offset), int(rg.To-c.offset+1)),
+		offset:       rg.From,
+		total:        c.total,
+		lastModified: c.lastModified,
+		etag:         c.etag,
+		ttl:          c.ttl,
+	}
+	return &ContentRange{rg, c.total}, part, nil
+}
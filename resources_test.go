@@ -0,0 +1,505 @@
+package rst
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBlob(t *testing.T) {
+	header := make(http.Header)
+	header.Set("Accept", "application/json")
+	rr := newRequestResponse(Get, testServerAddr+"/blob", header, nil)
+	if err := rr.TestStatusCode(http.StatusOK); err != nil {
+		t.Fatal(err)
+	}
+	if err := rr.TestHeader("Content-Type", testBlobContentType); err != nil {
+		t.Fatal(err)
+	}
+	if err := rr.TestHeader("ETag", Blob(testBlobContentType, testBlobContent).ETag()); err != nil {
+		t.Fatal(err)
+	}
+	if err := rr.TestBody(bytes.NewReader(testBlobContent)); err != nil {
+		t.Fatal(err)
+	}
+
+	header.Set("Range", "bytes=0-3")
+	rr = newRequestResponse(Get, testServerAddr+"/blob", header, nil)
+	if err := rr.TestStatusCode(http.StatusPartialContent); err != nil {
+		t.Fatal(err)
+	}
+	if err := rr.TestBody(bytes.NewReader(testBlobContent[:4])); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestText(t *testing.T) {
+	rr := newRequestResponse(Get, testServerAddr+"/text", nil, nil)
+	if err := rr.TestStatusCode(http.StatusOK); err != nil {
+		t.Fatal(err)
+	}
+	if err := rr.TestHeader("Content-Type", "text/plain; charset=utf-8"); err != nil {
+		t.Fatal(err)
+	}
+	if err := rr.TestBody(bytes.NewBufferString(testCannedContent)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Conditional request with an ETag
+	header := make(http.Header)
+	header.Set("If-None-Match", Text(testCannedContent).ETag())
+	rr = newRequestResponse(Get, testServerAddr+"/text", header, nil)
+	if err := rr.TestStatusCode(http.StatusNotModified); err != nil {
+		t.Fatal(err)
+	}
+
+	// ETags are quoted, and match the values sent by clients.
+	if etag, expected := Text(testCannedContent).ETag(), fmt.Sprintf("\"%x\"", sha1.Sum(testCannedBytes)); etag != expected {
+		t.Fatal("ETag. Got:", etag, "Wanted:", expected)
+	}
+
+	if ct, _, _ := Marshal(HTML("<p>hello</p>"), nil); ct != "text/html; charset=utf-8" {
+		t.Fatal("Got:", ct, "Wanted: text/html; charset=utf-8")
+	}
+}
+
+func TestWithStatus(t *testing.T) {
+	var test = func(resource Resource, expected int, body []byte) {
+		mux := NewMux()
+		mux.Handle("/jobs", postFunc(func(vars RouteVars, r *http.Request) (Resource, string, error) {
+			return resource, "/jobs/42", nil
+		}))
+		r, _ := http.NewRequest(Post, "/jobs", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal("status code. Got:", w.Code, "Wanted:", expected)
+		}
+		if location := w.Header().Get("Location"); location != "/jobs/42" {
+			t.Fatal("Location header. Got:", location, "Wanted: /jobs/42")
+		}
+		if !bytes.Equal(w.Body.Bytes(), body) {
+			t.Fatal("body. Got:", w.Body.String(), "Wanted:", string(body))
+		}
+	}
+
+	test(WithStatus(http.StatusAccepted, nil), http.StatusAccepted, nil)
+	test(WithStatus(http.StatusAccepted, Text(testCannedContent)), http.StatusAccepted, testCannedBytes)
+	test(Text(testCannedContent), http.StatusCreated, testCannedBytes)
+}
+
+func TestGetStatusCode(t *testing.T) {
+	var test = func(resource Resource, expected int) {
+		mux := NewMux()
+		mux.Handle("/weather", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+			return resource, nil
+		}))
+		r, _ := http.NewRequest(Get, "/weather", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal("status code. Got:", w.Code, "Wanted:", expected)
+		}
+	}
+
+	test(WithStatus(http.StatusNonAuthoritativeInfo, Text(testCannedContent)), http.StatusNonAuthoritativeInfo)
+	test(WithStatus(http.StatusFound, Text(testCannedContent)), http.StatusInternalServerError)
+	test(WithStatus(http.StatusFound, nil), http.StatusInternalServerError)
+}
+
+func TestPartial(t *testing.T) {
+	var test = func(resource Resource, expected int) {
+		mux := NewMux()
+		mux.Handle("/documents/1", patchFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+			return resource, nil
+		}))
+		r, _ := http.NewRequest(Patch, "/documents/1", strings.NewReader("s/fox/dog/"))
+		r.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal("status code. Got:", w.Code, "Wanted:", expected)
+		}
+		if got, wanted := w.Header().Get("Content-Range"), "bytes 16-18/44"; got != wanted {
+			t.Fatal("Content-Range. Got:", got, "Wanted:", wanted)
+		}
+		if got := w.Header().Get("Content-Encoding"); got != "" {
+			t.Fatal("Content-Encoding. Got:", got, "Wanted: none")
+		}
+		if got := w.Body.String(); got != "dog" {
+			t.Fatal("body. Got:", got, "Wanted: dog")
+		}
+	}
+
+	cr := &ContentRange{&Range{Unit: "bytes", From: 16, To: 18}, 44}
+	test(Partial(cr, Text("dog")), http.StatusPartialContent)
+	test(WithStatus(http.StatusOK, Partial(cr, Text("dog"))), http.StatusOK)
+}
+
+// note is a resource only tracking its modification date.
+type note struct {
+	Timestamped
+	Text string `json:"text"`
+}
+
+func (n *note) TTL() time.Duration {
+	return 0
+}
+
+func TestTimestamped(t *testing.T) {
+	a := &note{Timestamped{testTimeReference}, "a"}
+	b := &note{Timestamped{testTimeReference}, "b"}
+	c := &note{Timestamped{testTimeReference.Add(time.Nanosecond)}, "a"}
+
+	if a.ETag() != b.ETag() {
+		t.Fatal("Same modification dates. Got:", a.ETag(), b.ETag())
+	}
+	if a.ETag() == c.ETag() {
+		t.Fatal("Different modification dates. Got:", a.ETag(), "twice")
+	}
+	if etag := a.ETag(); !strings.HasPrefix(etag, `W/"`) {
+		t.Fatal("ETag. Got:", etag, "Wanted: a weak ETag")
+	}
+	if etag := (Timestamped{}).ETag(); etag != "" {
+		t.Fatal("ETag of a zero time. Got:", etag, "Wanted: none")
+	}
+
+	mux := NewMux()
+	mux.Handle("/notes/1", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return a, nil
+	}))
+	r, _ := http.NewRequest(Get, "/notes/1", nil)
+	r.Header.Set("If-None-Match", a.ETag())
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	if w.Code != http.StatusNotModified {
+		t.Fatal("status code. Got:", w.Code, "Wanted:", http.StatusNotModified)
+	}
+}
+
+func TestRawJSON(t *testing.T) {
+	// Spacing and order of keys would both be lost in a round trip.
+	data := []byte(`{"z": 1,  "a": [true, false], "text": "` + strings.Repeat("rst ", 300) + `"}`)
+	mux := NewMux()
+	mux.Handle("/report", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return RawJSON(data), nil
+	}))
+
+	var test = func(accept, encoding string, expected int) *httptest.ResponseRecorder {
+		r, _ := http.NewRequest(Get, "/report", nil)
+		r.Header.Set("Accept", accept)
+		r.Header.Set("Accept-Encoding", encoding)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal(accept, "status code. Got:", w.Code, "Wanted:", expected)
+		}
+		return w
+	}
+
+	w := test("application/json", "identity", http.StatusOK)
+	if !bytes.Equal(w.Body.Bytes(), data) {
+		t.Fatal("body. Got:", w.Body.String(), "Wanted:", string(data))
+	}
+	if got, expected := w.Header().Get("ETag"), fmt.Sprintf("\"%x\"", sha1.Sum(data)); got != expected {
+		t.Fatal("ETag. Got:", got, "Wanted:", expected)
+	}
+
+	w = test("application/json", "gzip", http.StatusOK)
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatal("Content-Encoding. Got:", got, "Wanted: gzip")
+	}
+	if b, err := decompress(ioutil.NopCloser(w.Body), "gzip"); err != nil || !bytes.Equal(b, data) {
+		t.Fatal("decompressed body. Got:", string(b), err, "Wanted:", string(data))
+	}
+
+	test("application/xml", "identity", http.StatusNotAcceptable)
+	test("*/*", "identity", http.StatusOK)
+}
+
+func TestDelegate(t *testing.T) {
+	mux := NewMux()
+	mux.Handle("/assets/{path:.*}", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return Delegate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/css")
+			w.WriteHeader(http.StatusTeapot)
+			io.WriteString(w, "body{}")
+		})), nil
+	}))
+
+	r, _ := http.NewRequest(Get, "/assets/main.css", nil)
+	r.Header.Set("Accept", "application/xml")
+	r.Header.Set("If-None-Match", "*")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	if w.Code != http.StatusTeapot {
+		t.Fatal("Status code. Got:", w.Code, "Wanted:", http.StatusTeapot)
+	}
+	if got := w.Body.String(); got != "body{}" {
+		t.Fatal("Body. Got:", got, "Wanted: body{}")
+	}
+	if got := w.Header().Get("Content-Type"); got != "text/css" {
+		t.Fatal("Content-Type. Got:", got, "Wanted: text/css")
+	}
+	for _, key := range []string{"Vary", "Cache-Control", "ETag", "Last-Modified"} {
+		if got := w.Header().Get(key); got != "" {
+			t.Fatal(key, "should not be set. Got:", got)
+		}
+	}
+}
+
+func TestDelegateEncoded(t *testing.T) {
+	mux := NewMux()
+	mux.Handle("/assets/{path:.*}", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return Delegate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/css")
+			w.Header().Set("Content-Encoding", vars.Get("path"))
+			io.WriteString(w, "encoded")
+		})), nil
+	}))
+
+	for _, encoding := range []string{"gzip", "br"} {
+		r, _ := http.NewRequest(Get, "/assets/"+encoding, nil)
+		r.Header.Set("Accept-Encoding", encoding)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatal(encoding, "status code. Got:", w.Code, "Wanted:", http.StatusOK)
+		}
+		if got := w.Body.String(); got != "encoded" {
+			t.Fatal(encoding, "body. Got:", got, "Wanted: encoded")
+		}
+	}
+}
+
+func TestWithCache(t *testing.T) {
+	modified := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	value := map[string]int{"visits": 42}
+	derived, err := WithContentCache(value, modified, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	etag, _ := ContentETag(value)
+
+	var test = func(resource Resource, etag string) {
+		mux := NewMux()
+		mux.Handle("/stats", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+			return resource, nil
+		}))
+
+		r, _ := newRequest("GET /stats HTTP/1.1\nHost: www.example.com\nAccept: application/json\n\n")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatal("Status code. Got:", w.Code, "Wanted:", http.StatusOK)
+		}
+		if got := w.Header().Get("ETag"); got != etag {
+			t.Fatal("ETag. Got:", got, "Wanted:", etag)
+		}
+		if got := w.Header().Get("Last-Modified"); got != modified.Format(rfc1123) {
+			t.Fatal("Last-Modified. Got:", got, "Wanted:", modified.Format(rfc1123))
+		}
+		if got := w.Header().Get("Expires"); got == "" {
+			t.Fatal("Expires header is missing")
+		}
+		if got := w.Body.String(); got != `{"visits":42}` {
+			t.Fatal("Body. Got:", got, `Wanted: {"visits":42}`)
+		}
+
+		// Conditional request with the ETag.
+		r, _ = newRequest("GET /stats HTTP/1.1\nHost: www.example.com\nIf-None-Match: " + etag + "\n\n")
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != http.StatusNotModified {
+			t.Fatal("Status code. Got:", w.Code, "Wanted:", http.StatusNotModified)
+		}
+	}
+
+	test(WithCache(value, "v42", modified, time.Minute), "v42")
+	test(derived, etag)
+
+	if _, err := WithContentCache(func() {}, modified, 0); err == nil {
+		t.Fatal("No error for a value that can't be encoded in JSON")
+	}
+}
+
+// closingPipe records whether it was closed.
+type closingPipe struct {
+	*io.PipeReader
+	closed chan bool
+}
+
+func (p *closingPipe) Close() error {
+	p.closed <- true
+	return p.PipeReader.Close()
+}
+
+func TestStream(t *testing.T) {
+	pr, pw := io.Pipe()
+	reader := &closingPipe{pr, make(chan bool, 1)}
+	received := make(chan bool)
+	chunk := bytes.Repeat([]byte("rst"), 32<<10)
+
+	// The second half of the data is only produced once the client has
+	// received the first one.
+	go func() {
+		pw.Write(chunk)
+		<-received
+		pw.Write(chunk)
+		pw.Close()
+	}()
+
+	mux := NewMux()
+	mux.Handle("/stream", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return Stream("application/octet-stream", reader), nil
+	}))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if got := resp.Header.Get("Content-Type"); got != "application/octet-stream" {
+		t.Fatal("Content-Type. Got:", got, "Wanted: application/octet-stream")
+	}
+
+	first := make([]byte, len(chunk))
+	if _, err := io.ReadFull(resp.Body, first); err != nil || !bytes.Equal(first, chunk) {
+		t.Fatal("First half was not received before the end of the stream:", err)
+	}
+	received <- true
+	rest, err := ioutil.ReadAll(resp.Body)
+	if err != nil || !bytes.Equal(rest, chunk) {
+		t.Fatal("Second half. Got:", len(rest), "bytes", err)
+	}
+
+	select {
+	case <-reader.closed:
+	case <-time.After(time.Second):
+		t.Fatal("The reader was not closed")
+	}
+}
+
+// recordingReaderAt records the spans read with ReadAt.
+type recordingReaderAt struct {
+	*bytes.Reader
+	reads []string
+}
+
+func (ra *recordingReaderAt) ReadAt(b []byte, off int64) (int, error) {
+	ra.reads = append(ra.reads, fmt.Sprintf("%d-%d", off, off+int64(len(b))-1))
+	return ra.Reader.ReadAt(b, off)
+}
+
+func TestReaderAt(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 10<<10)
+	reader := &recordingReaderAt{Reader: bytes.NewReader(data)}
+	modTime := time.Date(2016, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	mux := NewMux()
+	mux.Handle("/video", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return ReaderAt("video/mp4", reader, int64(len(data)), modTime), nil
+	}))
+
+	var test = func(rg string, status int, body []byte) *httptest.ResponseRecorder {
+		reader.reads = nil
+		r, _ := http.NewRequest(Get, "/video", nil)
+		if rg != "" {
+			r.Header.Set("Range", rg)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != status {
+			t.Fatal(rg, "status code. Got:", w.Code, "Wanted:", status)
+		}
+		if !bytes.Equal(w.Body.Bytes(), body) {
+			t.Fatal(rg, "body. Got:", w.Body.Len(), "bytes. Wanted:", len(body))
+		}
+		if got := w.Header().Get("Content-Type"); got != "video/mp4" {
+			t.Fatal(rg, "Content-Type. Got:", got, "Wanted: video/mp4")
+		}
+		if got, want := w.Header().Get("Last-Modified"), modTime.Format(rfc1123); got != want {
+			t.Fatal(rg, "Last-Modified. Got:", got, "Wanted:", want)
+		}
+		if w.Header().Get("ETag") == "" {
+			t.Fatal(rg, "ETag is missing")
+		}
+		return w
+	}
+
+	w := test("bytes=50000-50099", http.StatusPartialContent, data[50000:50100])
+	if got, want := w.Header().Get("Content-Range"), fmt.Sprintf("bytes 50000-50099/%d", len(data)); got != want {
+		t.Fatal("Content-Range. Got:", got, "Wanted:", want)
+	}
+	if len(reader.reads) != 1 || reader.reads[0] != "50000-50099" {
+		t.Fatal("ReadAt calls. Got:", reader.reads, "Wanted: [50000-50099]")
+	}
+	if got := w.Header().Get("Content-Length"); got != "100" {
+		t.Fatal("Content-Length. Got:", got, "Wanted: 100")
+	}
+
+	// Long ranges are streamed in chunks, rather than read at once.
+	test("bytes=1-", http.StatusPartialContent, data[1:])
+	if len(reader.reads) < 2 || !strings.HasPrefix(reader.reads[0], "1-") {
+		t.Fatal("ReadAt calls. Got:", reader.reads, "Wanted: chunks of the range")
+	}
+
+	test("", http.StatusOK, data)
+}
+
+func TestCollection(t *testing.T) {
+	items := make([]int, 25)
+	for i := range items {
+		items[i] = i
+	}
+	var collection *Collection
+	mux := NewMux()
+	mux.Handle("/numbers", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return collection, nil
+	}))
+
+	var test = func(rg string, expected int, contentRange string, body string) {
+		r, _ := http.NewRequest(Get, "/numbers", nil)
+		r.Header.Set("Accept", "application/json")
+		if rg != "" {
+			r.Header.Set("Range", rg)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal(rg, "status code. Got:", w.Code, "Wanted:", expected)
+		}
+		if got := w.Header().Get("Content-Range"); got != contentRange {
+			t.Fatal(rg, "Content-Range. Got:", got, "Wanted:", contentRange)
+		}
+		if got := w.Header().Get("X-Total-Count"); got != "25" {
+			t.Fatal(rg, "X-Total-Count. Got:", got, "Wanted: 25")
+		}
+		if got := w.Body.String(); got != body {
+			t.Fatal(rg, "body. Got:", got, "Wanted:", body)
+		}
+	}
+
+	collection = NewCollection(items, 0, 25, time.Time{}, "", 0)
+	test("items=0-9", http.StatusPartialContent, "items 0-9/25", "[0,1,2,3,4,5,6,7,8,9]")
+	test("items=20-", http.StatusPartialContent, "items 20-24/25", "[20,21,22,23,24]")
+	test("", http.StatusOK, "", "[0,1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24]")
+
+	// A page only serves the ranges it holds.
+	collection = NewCollection(items[10:20], 10, 25, time.Time{}, "", 0)
+	test("items=10-14", http.StatusPartialContent, "items 10-14/25", "[10,11,12,13,14]")
+	test("items=0-9", http.StatusOK, "", "[10,11,12,13,14,15,16,17,18,19]")
+
+	var empty []int
+	collection = NewCollection(empty, 0, 25, time.Time{}, "", 0)
+	test("", http.StatusOK, "", "[]")
+}
@@ -0,0 +1,312 @@
+package rst
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"sync"
+)
+
+/*
+routeTree matches the path of a request with the routes of a Mux in a time
+proportional to the length of the path, rather than to the number of routes.
+
+Patterns are split in segments. At each level of the tree, static segments are
+tried first, then segments containing variables, and finally catch-all
+variables, whose regular expression can match slashes and consume the rest of
+the path. Routes of the same kind are tried in the order in which they were
+registered, and the first route registered with a pattern wins.
+*/
+type routeTree struct {
+	root *routeNode
+}
+
+type routeNode struct {
+	static    map[string]*routeNode
+	params    []*paramEdge
+	catchAlls []*catchAll
+	route     *route
+}
+
+// paramEdge leads to the routes whose next segment contains variables.
+type paramEdge struct {
+	segment string         // Segment as written in the pattern.
+	re      *regexp.Regexp // Nil for a single unconstrained variable.
+	names   []string
+	child   *routeNode
+}
+
+// catchAll is a route whose remaining segments are matched as a whole with a
+// regular expression.
+type catchAll struct {
+	re    *regexp.Regexp
+	names []string
+	route *route
+}
+
+func newRouteTree() *routeTree {
+	return &routeTree{root: &routeNode{}}
+}
+
+// handle registers rt for pattern, which must have been validated with
+// validatePattern and expanded with expandPattern from the pattern of rt.
+func (t *routeTree) handle(pattern string, rt *route) {
+	if !strings.HasPrefix(pattern, "/") {
+		return // Such patterns can't match any path.
+	}
+
+	n := t.root
+	segments := splitPattern(pattern[1:])
+	for i, segment := range segments {
+		vars := patternVars(segment)
+		switch {
+		case len(vars) == 0:
+			child, exists := n.static[segment]
+			if !exists {
+				if n.static == nil {
+					n.static = make(map[string]*routeNode)
+				}
+				child = &routeNode{}
+				n.static[segment] = child
+			}
+			n = child
+		case spansSegments(vars):
+			re, names := compileSegment(strings.Join(segments[i:], "/"))
+			n.catchAlls = append(n.catchAlls, &catchAll{re, names, rt})
+			return
+		default:
+			n = n.paramChild(segment, vars)
+		}
+	}
+	if n.route == nil {
+		n.route = rt
+	}
+}
+
+// paramChild returns the node following the edge of n for segment, which is
+// created if needed.
+func (n *routeNode) paramChild(segment string, vars []patternVar) *routeNode {
+	for _, edge := range n.params {
+		if edge.segment == segment {
+			return edge.child
+		}
+	}
+
+	edge := &paramEdge{segment: segment, child: &routeNode{}}
+	if len(vars) == 1 && vars[0].expr == "" && vars[0].start == 0 && vars[0].end == len(segment) {
+		edge.names = []string{vars[0].name}
+	} else {
+		edge.re, edge.names = compileSegment(segment)
+	}
+	n.params = append(n.params, edge)
+	return edge.child
+}
+
+// routeMatch holds the variables extracted from the path of a request. Matches
+// are reused across requests to save allocations.
+type routeMatch struct {
+	values []string // Names and values of the variables, in pairs.
+	vars   RouteVars
+}
+
+var routeMatchPool = sync.Pool{
+	New: func() interface{} {
+		return &routeMatch{vars: make(RouteVars)}
+	},
+}
+
+// getRouteMatch returns an empty routeMatch from the pool.
+func getRouteMatch() *routeMatch {
+	return routeMatchPool.Get().(*routeMatch)
+}
+
+// putRouteMatch resets m and puts it back in the pool. m, and its vars, must
+// not be used anymore.
+func putRouteMatch(m *routeMatch) {
+	for key := range m.vars {
+		delete(m.vars, key)
+	}
+	m.values = m.values[:0]
+	routeMatchPool.Put(m)
+}
+
+// match returns the route registered for path, and sets the values of its
+// variables in m, or returns nil if no route matches.
+func (t *routeTree) match(path string, m *routeMatch) *route {
+	if !strings.HasPrefix(path, "/") {
+		return nil
+	}
+	rt, values := t.root.lookup(strings.Split(path[1:], "/"), m.values[:0])
+	m.values = values
+	if rt == nil {
+		return nil
+	}
+	for i := 0; i < len(values); i += 2 {
+		m.vars[values[i]] = values[i+1]
+	}
+	return rt
+}
+
+// lookup returns the route matching segments in the subtree of n. values
+// holds the names and values of the variables matched so far, in pairs.
+func (n *routeNode) lookup(segments []string, values []string) (*route, []string) {
+	if len(segments) == 0 {
+		return n.route, values
+	}
+
+	segment := segments[0]
+	if child, exists := n.static[segment]; exists {
+		if rt, v := child.lookup(segments[1:], values); rt != nil {
+			return rt, v
+		}
+	}
+
+	for _, edge := range n.params {
+		if edge.re == nil {
+			if segment == "" {
+				continue
+			}
+			if rt, v := edge.child.lookup(segments[1:], append(values, edge.names[0], segment)); rt != nil {
+				return rt, v
+			}
+			continue
+		}
+		if m := edge.re.FindStringSubmatch(segment); m != nil {
+			if rt, v := edge.child.lookup(segments[1:], appendMatches(values, edge.names, m)); rt != nil {
+				return rt, v
+			}
+		}
+	}
+
+	if len(n.catchAlls) > 0 {
+		rest := strings.Join(segments, "/")
+		for _, c := range n.catchAlls {
+			if m := c.re.FindStringSubmatch(rest); m != nil {
+				return c.route, appendMatches(values, c.names, m)
+			}
+		}
+	}
+	return nil, values
+}
+
+// appendMatches appends the names of variables and the values matched for
+// them by a regular expression compiled with compileSegment.
+func appendMatches(values []string, names []string, m []string) []string {
+	for i, name := range names {
+		if name != "" {
+			values = append(values, name, m[i+1])
+		}
+	}
+	return values
+}
+
+// splitPattern splits pattern on the slashes which are not part of the
+// regular expression of a variable.
+func splitPattern(pattern string) (segments []string) {
+	depth, start := 0, 0
+	for i, c := range pattern {
+		switch c {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case '/':
+			if depth == 0 {
+				segments = append(segments, pattern[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(segments, pattern[start:])
+}
+
+// compileSegment returns a regular expression matching the whole of segment,
+// with a group for each one of its variables, and the names of the variables
+// indexed by group. Groups which don't belong to a variable have no name.
+func compileSegment(segment string) (*regexp.Regexp, []string) {
+	var (
+		expr  = "^"
+		names []string
+		last  int
+	)
+	for _, v := range patternVars(segment) {
+		e := v.expr
+		if e == "" {
+			e = "[^/]+"
+		}
+		expr += regexp.QuoteMeta(segment[last:v.start]) + fmt.Sprintf("(?P<v%d>%s)", len(names), e)
+		names = append(names, v.name)
+		last = v.end
+	}
+	expr += regexp.QuoteMeta(segment[last:]) + "$"
+
+	re := regexp.MustCompile(expr)
+	// Groups declared in the expressions of the variables shift the index of
+	// the groups added for the variables themselves.
+	indexes := make(map[string]int)
+	for i, name := range re.SubexpNames() {
+		indexes[name] = i
+	}
+	ordered := make([]string, re.NumSubexp())
+	for i, name := range names {
+		ordered[indexes[fmt.Sprintf("v%d", i)]-1] = name
+	}
+	return re, ordered
+}
+
+// spansSegments returns true if one of vars has a regular expression able to
+// match a slash.
+func spansSegments(vars []patternVar) bool {
+	for _, v := range vars {
+		if v.expr == "" {
+			continue
+		}
+		if re, err := syntax.Parse(v.expr, syntax.Perl); err == nil && matchesSlash(re) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesSlash(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return true
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if r == '/' {
+				return true
+			}
+		}
+	case syntax.OpCharClass:
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			if re.Rune[i] <= '/' && '/' <= re.Rune[i+1] {
+				return true
+			}
+		}
+	}
+	for _, sub := range re.Sub {
+		if matchesSlash(sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanPath returns the canonical form of p, like path.Clean, with its
+// trailing slash kept.
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	if p[0] != '/' {
+		p = "/" + p
+	}
+	cleaned := path.Clean(p)
+	if p[len(p)-1] == '/' && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
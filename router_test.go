@@ -0,0 +1,235 @@
+package rst
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	gorillaMux "github.com/gorilla/mux"
+)
+
+// namedHandler is a handler identified by its route in the tests.
+type namedHandler string
+
+func (h namedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
+
+func TestRouteTree(t *testing.T) {
+	tree := newRouteTree()
+	for _, pattern := range []string{
+		"/",
+		"/files/{path:.*}",
+		"/people/{id:[0-9]+}.{format}",
+		"/people/{id}",
+		"/people/me",
+		"/people/{id}/friends",
+		"/codes/{code:([a-z]+)-([0-9]+)}",
+		"/people/{id}", // Ignored, the first registration wins.
+	} {
+		tree.handle(pattern, &route{pattern: pattern, handler: namedHandler(pattern)})
+	}
+
+	var test = func(path, pattern string, vars RouteVars) {
+		m := &routeMatch{vars: make(RouteVars)}
+		rt, got := tree.match(path, m), m.vars
+		if pattern == "" {
+			if rt != nil {
+				t.Fatal(path, "Got:", rt.pattern, "Wanted: no match")
+			}
+			return
+		}
+		if rt == nil || rt.handler != namedHandler(pattern) {
+			t.Fatal(path, "Got:", rt, "Wanted:", pattern)
+		}
+		if !reflect.DeepEqual(got, vars) {
+			t.Fatal(path, "Vars. Got:", got, "Wanted:", vars)
+		}
+	}
+
+	test("/", "/", RouteVars{})
+	test("/people/me", "/people/me", RouteVars{})
+	test("/people/42", "/people/{id}", RouteVars{"id": "42"})
+	test("/people/42/friends", "/people/{id}/friends", RouteVars{"id": "42"})
+	test("/people/me/friends", "/people/{id}/friends", RouteVars{"id": "me"})
+	test("/people/42.json", "/people/{id:[0-9]+}.{format}", RouteVars{"id": "42", "format": "json"})
+	test("/people/me.json", "/people/{id}", RouteVars{"id": "me.json"})
+	test("/codes/abc-12", "/codes/{code:([a-z]+)-([0-9]+)}", RouteVars{"code": "abc-12"})
+	test("/files/a/b/c.txt", "/files/{path:.*}", RouteVars{"path": "a/b/c.txt"})
+	test("/files/", "/files/{path:.*}", RouteVars{"path": ""})
+	test("/people", "", nil)
+	test("/people/", "", nil)
+	test("/people/42/enemies", "", nil)
+	test("/codes/abc", "", nil)
+}
+
+// benchmarkRoutes returns n patterns, and a path matching the last one.
+func benchmarkRoutes(n int) ([]string, string) {
+	patterns := make([]string, n)
+	for i := range patterns {
+		patterns[i] = fmt.Sprintf("/resources%d/{id}/items/{item}", i)
+	}
+	return patterns, fmt.Sprintf("/resources%d/42/items/7", n-1)
+}
+
+func benchmarkRouteTree(b *testing.B, n int) {
+	patterns, path := benchmarkRoutes(n)
+	tree := newRouteTree()
+	for _, pattern := range patterns {
+		tree.handle(pattern, &route{pattern: pattern, handler: namedHandler(pattern)})
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m := getRouteMatch()
+		if tree.match(path, m) == nil {
+			b.Fatal("no match for", path)
+		}
+		putRouteMatch(m)
+	}
+}
+
+// benchmarkGorillaRouter measures the matcher used before routeTree.
+func benchmarkGorillaRouter(b *testing.B, n int) {
+	patterns, path := benchmarkRoutes(n)
+	router := gorillaMux.NewRouter()
+	for _, pattern := range patterns {
+		router.Handle(pattern, namedHandler(pattern))
+	}
+	r, _ := http.NewRequest(Get, path, nil)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var match gorillaMux.RouteMatch
+		if !router.Match(r, &match) {
+			b.Fatal("no match for", path)
+		}
+	}
+}
+
+func BenchmarkRouteTree10(b *testing.B)       { benchmarkRouteTree(b, 10) }
+func BenchmarkRouteTree100(b *testing.B)      { benchmarkRouteTree(b, 100) }
+func BenchmarkRouteTree1000(b *testing.B)     { benchmarkRouteTree(b, 1000) }
+func BenchmarkGorillaRouter10(b *testing.B)   { benchmarkGorillaRouter(b, 10) }
+func BenchmarkGorillaRouter100(b *testing.B)  { benchmarkGorillaRouter(b, 100) }
+func BenchmarkGorillaRouter1000(b *testing.B) { benchmarkGorillaRouter(b, 1000) }
+
+// Benchmarking the allocations saved by reusing matches.
+func benchmarkRouteMatch(b *testing.B, pooled bool) {
+	tree := newRouteTree()
+	tree.handle("/people/{id}/friends/{friend}", &route{pattern: "/people/{id}/friends/{friend}", handler: namedHandler("")})
+	path := "/people/42/friends/7"
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m := &routeMatch{vars: make(RouteVars)}
+		if pooled {
+			m = getRouteMatch()
+		}
+		if tree.match(path, m) == nil {
+			b.Fatal("no match for", path)
+		}
+		if pooled {
+			putRouteMatch(m)
+		}
+	}
+}
+
+func BenchmarkRouteMatchPooled(b *testing.B)   { benchmarkRouteMatch(b, true) }
+func BenchmarkRouteMatchUnpooled(b *testing.B) { benchmarkRouteMatch(b, false) }
+
+// Variables of a request must never be visible in the following ones.
+func TestRouteVarsReuse(t *testing.T) {
+	mux := NewMux()
+	var got RouteVars
+	mux.Handle("/people/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = getVars(r).All()
+	}))
+	mux.Handle("/employers/{name}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = getVars(r).All()
+	}))
+
+	for i := 0; i < 100; i++ {
+		var test = func(url string, expected RouteVars) {
+			r, _ := http.NewRequest(Get, url, nil)
+			mux.ServeHTTP(httptest.NewRecorder(), r)
+			if !reflect.DeepEqual(got, expected) {
+				t.Fatal(url, "Got:", got, "Wanted:", expected)
+			}
+		}
+		test(fmt.Sprintf("/people/%d", i), RouteVars{"id": fmt.Sprint(i)})
+		test("/employers/acme", RouteVars{"name": "acme"})
+	}
+}
+
+func TestCleanPath(t *testing.T) {
+	mux := NewMux()
+	mux.Handle("/people/{id}", http.NotFoundHandler())
+
+	var test = func(url string, expected int, location string) {
+		r, err := newRequest("GET " + url + " HTTP/1.1\nHost: www.example.com\n\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal(url, "status code. Got:", w.Code, "Wanted:", expected)
+		}
+		if got := w.Header().Get("Location"); got != location {
+			t.Fatal(url, "Location. Got:", got, "Wanted:", location)
+		}
+	}
+
+	test("/people/1", http.StatusNotFound, "")
+	test("//people/../people/1", http.StatusMovedPermanently, "/people/1")
+	test("/people/./1?lang=fr", http.StatusMovedPermanently, "/people/1?lang=fr")
+	test("/people//", http.StatusMovedPermanently, "/people/")
+}
+
+func TestPathExtensions(t *testing.T) {
+	mux := NewMux()
+	mux.PathExtensions = true
+	var got RouteVars
+	mux.Handle("/users/{id}", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		got = vars.All()
+		return testPeople[len(testPeople)-1], nil
+	}))
+	mux.Handle("/files/{path:.*}", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		got = vars.All()
+		return Text(testCannedContent), nil
+	}))
+	mux.Handle("/versions/{v:[0-9.]+}.{ext}", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		got = vars.All()
+		return Text(testCannedContent), nil
+	}))
+
+	var test = func(path string, vars RouteVars, contentType string) {
+		got = nil
+		r, _ := http.NewRequest(Get, path, nil)
+		r.Header.Set("Accept", "application/json")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatal(path, "status code. Got:", w.Code, "Wanted:", http.StatusOK)
+		}
+		if !reflect.DeepEqual(got, vars) {
+			t.Fatal(path, "vars. Got:", got, "Wanted:", vars)
+		}
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, contentType) {
+			t.Fatal(path, "Content-Type. Got:", ct, "Wanted:", contentType)
+		}
+	}
+
+	test("/users/42.xml", RouteVars{"id": "42"}, "application/xml")
+	test("/users/42.json", RouteVars{"id": "42"}, "application/json")
+	test("/users/42", RouteVars{"id": "42"}, "application/json")
+	test("/users/j.doe", RouteVars{"id": "j.doe"}, "application/json")
+	test("/versions/1.2.zip", RouteVars{"v": "1.2", "ext": "zip"}, "text/plain")
+
+	mux.PathExtensions = false
+	test("/users/42.xml", RouteVars{"id": "42.xml"}, "application/json")
+	test("/files/a/b.txt", RouteVars{"path": "a/b.txt"}, "text/plain")
+}
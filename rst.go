@@ -99,9 +99,22 @@ database, or return a 404 Not Found error.
 
 Routing
 
-Routing of requests in rst is powered by Gorilla mux
-(https://github.com/gorilla/mux). Only URL patterns are available for now.
-Optional regular expressions are supported.
+Routes are registered with URL patterns, in which variables are set between
+braces, like /people/{id}. Their value is found in the RouteVars given to
+endpoints. A variable can be constrained by a regular expression following its
+name, like {id:[0-9]+}, and a variable whose expression can match slashes,
+like {path:.*}, spans the rest of the path. A variable marked with a question
+mark, like /reports/{year?}, is optional and must be the last segment of the
+pattern.
+
+Requests to paths which aren't clean, like //people/../people/1, are
+redirected with a 301 Moved Permanently to their clean form, as returned by
+path.Clean with the trailing slash kept.
+
+Routes are stored in a tree, and requests are matched in a time proportional
+to the length of their path. Static segments take precedence over variables,
+which take precedence over variables able to match slashes, such as
+{path:.*}.
 
 	mux := rst.NewMux()
 	mux.Debug = true // make sure this is switched back to false before production
@@ -132,6 +145,7 @@ rst compresses the payload of responses using the supported algorithm detected
 in the request's Accept-Encoding header.
 
 Payloads under the size defined in the CompressionThreshold const are not compressed.
+Resources can opt out of compression by implementing Compressible.
 
 Both Gzip and Flate are supported.
 
@@ -147,7 +161,8 @@ rst responds with 304 NOT MODIFIED when an appropriate If-Modified-Since or
 If-None-Match header is found in the request.
 
 The Expires header is also automatically inserted with the duration returned by
-Resource.TTL().
+Resource.TTL(). A TTL of zero sets Cache-Control to no-cache instead, and a
+negative TTL sets it to no-store.
 
 Partial Gets
 
@@ -164,6 +179,8 @@ The supported range units and the range extent will be validated for you.
 
 Note that the If-Range conditional header is supported as well.
 
+Requests listing more than MaxRanges ranges receive the full resource.
+
 CORS
 
 rst can add the headers required to serve cross-origin (CORS) requests for you.
@@ -178,21 +195,38 @@ Support can be disabled by passing nil.
 Preflighted requests are also supported. However, you can customize the
 responses returned by preflight OPTIONS requests if you implement the
 Preflighter interface in your endpoint.
+
+Timeout
+
+A mux can answer requests taking too long to be handled with a 503 Service
+Unavailable error.
+
+	mux.Timeout = 10 * time.Second
+	mux.TimeoutMessage = "The service is busy. Please try again later."
+
+The response written by the handler is buffered until it returns, and the
+context of the request is canceled when the timeout expires. Writes are
+therefore not flushed to the client, which makes the timeout unsuitable to
+streams.
 */
 package rst
 
 import (
+	"bufio"
 	"compress/flate"
 	"compress/gzip"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gorilla/context"
-	gorillaMux "github.com/gorilla/mux"
 )
 
 // rfc1123 with GMT
@@ -214,31 +248,103 @@ const (
 	flateCompression        = "deflate"
 )
 
+// compressor is implemented by the gzip and flate writers.
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(io.Writer)
+}
+
+// compressorPools holds the compressors of each format, which allocate large
+// buffers and are reused across responses.
+var compressorPools = map[string]*sync.Pool{
+	gzipCompression: {New: func() interface{} {
+		return gzip.NewWriter(nil)
+	}},
+	flateCompression: {New: func() interface{} {
+		w, _ := flate.NewWriter(nil, flate.DefaultCompression)
+		return w
+	}},
+}
+
+// getCompressor returns a compressor of format writing in w. It must be
+// released with putCompressor.
+func getCompressor(w io.Writer, format string) compressor {
+	c := compressorPools[format].Get().(compressor)
+	c.Reset(w)
+	return c
+}
+
+// putCompressor closes c, and puts it back in the pool of format. c must not be
+// used anymore.
+func putCompressor(c compressor, format string) error {
+	err := c.Close()
+	c.Reset(nil)
+	compressorPools[format].Put(c)
+	return err
+}
+
 // CompressionThreshold is the minimal length that the body of a response must
 // reach before compression is enabled.
 // The current default value is the one used by Akamai, and falls within the
 // range recommended by Google.
 var CompressionThreshold = 860 // bytes
 
+/*
+Compressible is implemented by resources which can opt out of the compression
+of their payload, like images or archives whose data is already compressed,
+and wouldn't get any smaller.
+
+	func (p *Photo) Compressible() bool {
+		return false
+	}
+
+Resources returning false are written without a Content-Encoding, whatever the
+Accept-Encoding header of the request, and whether they're marshaled, written
+by their ServeHTTP method, or streamed.
+*/
+type Compressible interface {
+	Compressible() bool
+}
+
+// compressionAllowed returns false if resource opted out of compression.
+func compressionAllowed(resource interface{}) bool {
+	if c, implemented := resource.(Compressible); implemented {
+		return c.Compressible()
+	}
+	return true
+}
+
 // getCompressionFormat returns the compression for that will be used for b as
 // a payload in the response to r. The returned string is either empty, gzip, or
 // deflate.
 func getCompressionFormat(b []byte, r *http.Request) string {
-	if b == nil || len(b) < CompressionThreshold {
+	if !compressible(b) {
 		return ""
 	}
+	return acceptedCompression(r)
+}
+
+// compressible returns true if b is long enough to be compressed.
+func compressible(b []byte) bool {
+	return b != nil && len(b) >= CompressionThreshold
+}
 
+// acceptedCompression returns the compression format accepted by the client
+// in the Accept-Encoding header of r. The returned string is either empty,
+// gzip, or deflate.
+func acceptedCompression(r *http.Request) string {
 	encoding := r.Header.Get("Accept-Encoding")
-	if strings.Contains(encoding, gzipCompression) {
-		return gzipCompression
-	}
-	if strings.Contains(encoding, flateCompression) {
-		return flateCompression
+	if encoding == "" {
+		return ""
 	}
-	return ""
+	return ParseAcceptEncoding(encoding).Negotiate(gzipCompression, flateCompression)
 }
 
 // RouteVars represents the variables extracted by the router from a URL.
+//
+// The RouteVars passed to an endpoint are reused once the request has been
+// served, and must not be retained. Use All to keep a copy.
 type RouteVars map[string]string
 
 // Get returns the value with key, or an empty string if not found.
@@ -247,24 +353,76 @@ func (rv RouteVars) Get(key string) string {
 	return value
 }
 
+// Has returns true if key was extracted from the URL, even if its value is
+// empty.
+func (rv RouteVars) Has(key string) bool {
+	_, exists := rv[key]
+	return exists
+}
+
+// All returns a copy of all the variables in rv.
+func (rv RouteVars) All() map[string]string {
+	all := make(map[string]string, len(rv))
+	for key, value := range rv {
+		all[key] = value
+	}
+	return all
+}
+
 // ResponseWriter implements http.ResponseWriter, and adds data compression
 // support.
+//
+// It also records the status code and the number of bytes written in the
+// response, and passes Flush and Hijack calls through to the embedded
+// http.ResponseWriter.
 type responseWriter struct {
 	http.ResponseWriter
+	status  int  // 0 until the header is written.
+	size    int  // Bytes written by the handler, before compression.
+	encoded bool // Set when the data written is already encoded.
+
+	// beforeHeader, when set, is called with the status code of the response
+	// right before its header is written.
+	beforeHeader func(code int)
+}
+
+// WriteHeader records code, and writes it in the embedded http.ResponseWriter.
+func (w *responseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+		if w.beforeHeader != nil {
+			w.beforeHeader(code)
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
 }
 
 // Write will compress data in the format specified in the Content-Encoding
 // header of the embedded http.ResponseWriter.
-func (w *responseWriter) Write(b []byte) (int, error) {
+func (w *responseWriter) Write(b []byte) (n int, err error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+		if w.beforeHeader != nil {
+			w.beforeHeader(http.StatusOK)
+		}
+	}
+	defer func() {
+		w.size += n
+	}()
+
+	if w.encoded {
+		return w.ResponseWriter.Write(b)
+	}
+
 	switch format := w.Header().Get("Content-Encoding"); format {
-	case gzipCompression:
-		compressor := gzip.NewWriter(w.ResponseWriter)
-		defer compressor.Close()
-		return compressor.Write(b)
-	case flateCompression:
-		compressor, _ := flate.NewWriter(w.ResponseWriter, 0)
-		defer compressor.Close()
-		return compressor.Write(b)
+	case gzipCompression, flateCompression:
+		c := getCompressor(w.ResponseWriter, format)
+		n, err = c.Write(b)
+		// The compressor is released even if the data couldn't be written.
+		if cerr := putCompressor(c, format); err == nil {
+			err = cerr
+		}
+		return n, err
 	case "":
 		return w.ResponseWriter.Write(b)
 	default:
@@ -272,11 +430,139 @@ func (w *responseWriter) Write(b []byte) (int, error) {
 	}
 }
 
+// Status returns the status code written in the response, or 0 if nothing has
+// been written yet.
+func (w *responseWriter) Status() int {
+	return w.status
+}
+
+// Size returns the number of bytes written in the body of the response.
+func (w *responseWriter) Size() int {
+	return w.size
+}
+
+// Flush implements the http.Flusher interface.
+func (w *responseWriter) Flush() {
+	if w.status == 0 && w.beforeHeader != nil {
+		w.WriteHeader(http.StatusOK)
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements the http.Hijacker interface.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		conn, rw, err := h.Hijack()
+		if err == nil && w.status == 0 {
+			// The connection is no longer an HTTP response.
+			w.status = http.StatusSwitchingProtocols
+		}
+		return conn, rw, err
+	}
+	return nil, nil, errors.New("rst: the underlying ResponseWriter does not support hijacking")
+}
+
+// ResponseHook is a function called before the header of a response is written,
+// with the status code of the response, its header, and the request. See
+// Mux.ResponseHooks.
+type ResponseHook func(status int, header http.Header, r *http.Request)
+
 func newResponseWriter(w http.ResponseWriter) *responseWriter {
-	return &responseWriter{w}
+	return &responseWriter{ResponseWriter: w}
+}
+
+/*
+HandlerCompression enables the compression of the payloads written by resources
+implementing http.Handler. By default, these payloads are never compressed.
+
+When enabled, the data written by the handler is compressed on the fly with the
+format negotiated from the Accept-Encoding header of the request, regardless of
+CompressionThreshold since the length of the payload can't be known in advance.
+
+Handlers that set the Content-Encoding header themselves are left untouched.
+*/
+var HandlerCompression = false
+
+// compressWriter is an http.ResponseWriter that compresses the data written by
+// a handler in a single stream.
+type compressWriter struct {
+	http.ResponseWriter
+	format      string
+	compressor  compressor
+	wroteHeader bool
 }
 
-const varsKey = "__rst__vars"
+func newCompressWriter(w http.ResponseWriter, format string) *compressWriter {
+	// The rst responseWriter compresses data based on the Content-Encoding
+	// header, which would cause this data to be compressed twice.
+	if rw, ok := w.(*responseWriter); ok {
+		rw.encoded = true
+	}
+	return &compressWriter{ResponseWriter: w, format: format}
+}
+
+// WriteHeader sets the compression headers, unless the handler has already set
+// its own Content-Encoding or the status code doesn't allow a body.
+func (w *compressWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	if w.Header().Get("Content-Encoding") == "" && code != http.StatusNoContent && code != http.StatusNotModified {
+		w.Header().Set("Content-Encoding", w.format)
+		w.Header().Add("Vary", "Accept-Encoding")
+		w.Header().Del("Content-Length")
+		if _, supported := compressorPools[w.format]; supported {
+			w.compressor = getCompressor(w.ResponseWriter, w.format)
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		// Content-Type can't be sniffed by net/http from compressed data.
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.compressor == nil {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.compressor.Write(b)
+}
+
+// Flush implements the http.Flusher interface, and sends the data compressed
+// so far to the client.
+func (w *compressWriter) Flush() {
+	if w.compressor != nil {
+		w.compressor.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// close terminates the compressed stream, and releases the compressor.
+func (w *compressWriter) close() error {
+	if w.compressor == nil {
+		return nil
+	}
+	err := putCompressor(w.compressor, w.format)
+	w.compressor = nil
+	return err
+}
+
+const (
+	varsKey    = "__rst__vars"
+	muxKey     = "__rst__mux"
+	patternKey = "__rst__pattern"
+	copiesKey  = "__rst__copies"
+)
 
 func getVars(r *http.Request) (vars RouteVars) {
 	if v := context.Get(r, varsKey); v != nil {
@@ -287,32 +573,230 @@ func getVars(r *http.Request) (vars RouteVars) {
 func setVars(r *http.Request, vars RouteVars) {
 	context.Set(r, varsKey, vars)
 }
-func delVars(r *http.Request) {
+
+// getMux returns the mux serving r, or nil if r is not served by a mux.
+func getMux(r *http.Request) *Mux {
+	if m, ok := context.Get(r, muxKey).(*Mux); ok {
+		return m
+	}
+	return nil
+}
+func setMux(r *http.Request, m *Mux) {
+	context.Set(r, muxKey, m)
+}
+
+// RoutePattern returns the pattern of the route matched by r in the Mux
+// serving it, like /people/{id}, or an empty string if r didn't match any.
+func RoutePattern(r *http.Request) string {
+	pattern, _ := context.Get(r, patternKey).(string)
+	return pattern
+}
+
+func setPattern(r *http.Request, pattern string) {
+	context.Set(r, patternKey, pattern)
+}
+
+// copyContext stores the values of r for its copy c, and returns c. The values
+// of c are cleared along with those of r.
+func copyContext(r, c *http.Request) *http.Request {
+	values := context.GetAll(r)
+	if len(values) == 0 {
+		return c
+	}
+	for key, value := range values {
+		if key != copiesKey {
+			context.Set(c, key, value)
+		}
+	}
+	copies, _ := values[copiesKey].([]*http.Request)
+	context.Set(r, copiesKey, append(copies, c))
+	return c
+}
+
+// clearContext removes all the values stored for r and its copies.
+func clearContext(r *http.Request) {
+	copies, _ := context.Get(r, copiesKey).([]*http.Request)
+	for _, c := range copies {
+		clearContext(c)
+	}
 	context.Clear(r)
 }
 
+// Default limits enforced by a Mux on incoming requests. DefaultMaxBodyBytes
+// is only enforced when set in Mux.MaxBodyBytes.
+const (
+	DefaultMaxURLLength   = 8192     // bytes
+	DefaultMaxHeaderBytes = 1 << 20  // 1 MB
+	DefaultMaxBodyBytes   = 32 << 20 // 32 MB
+)
+
 // Mux is an HTTP request multiplexer. It matches the URL of each incoming
 // requests against a list of registered REST endpoints.
 type Mux struct {
 	Debug  bool // Set to true to display stack traces and debug info in errors.
 	Logger *log.Logger
+
+	// ErrorRenderer, when set, writes the responses generated from the errors
+	// returned by endpoints and by the mux itself, instead of Error.ServeHTTP.
+	// The error passed to ErrorRenderer is always an *Error.
+	ErrorRenderer func(error, http.ResponseWriter, *http.Request)
+
+	// CloseConnection, when set, is called with the errors written by the
+	// mux. When it returns true, the response is sent with a Connection:
+	// close header, and the connection isn't reused for other requests. This
+	// is the safest way to recover from errors which leave the connection in
+	// an unknown state, like a body only partially read:
+	//
+	//	mux.CloseConnection = func(err *rst.Error, r *http.Request) bool {
+	//		return err.Code == http.StatusBadRequest && r.ContentLength != 0
+	//	}
+	CloseConnection func(err *Error, r *http.Request) bool
+
+	// Requests with a URI longer than MaxURLLength bytes are rejected with
+	// 414 URI Too Long, and requests with header fields totaling more than
+	// MaxHeaderBytes are rejected with 431 Request Header Fields Too Large.
+	// A value of 0 disables the limit.
+	MaxURLLength   int
+	MaxHeaderBytes int
+
+	// Reading more than MaxBodyBytes from the body of a request fails with a
+	// 413 Request Entity Too Large error. When DecompressRequests is true, the
+	// limit applies to the decompressed body. The default value of 0 disables
+	// the limit:
+	//
+	//	mux.MaxBodyBytes = rst.DefaultMaxBodyBytes
+	MaxBodyBytes int64
+
+	// FormatParam is the name of a query parameter with which clients can
+	// choose the representation of the response, regardless of their Accept
+	// header, like in /people/1?format=xml. The values of the parameter are
+	// the keys of Formats, and other values are rejected with a 406 Not
+	// Acceptable error. An empty string disables the parameter.
+	FormatParam string
+
+	// ContentType forces the media type of every response, errors included,
+	// like application/json, regardless of the Accept header of the request.
+	// FormatParam and PathExtensions are ignored when it's set, and resources
+	// which can't be encoded in ContentType are rejected with a 406 Not
+	// Acceptable error. An empty string enables content negotiation.
+	ContentType string
+
+	// PathExtensions lets clients choose the representation of the response
+	// with an extension named after one of the keys of Formats, like in
+	// /people/1.xml. The extension is removed from the path before it's
+	// matched with the routes, and paths which don't match any route once
+	// stripped are matched as is. FormatParam takes precedence over the
+	// extension.
+	PathExtensions bool
+
+	// RequirePreconditions makes the PATCH, PUT, and DELETE requests served
+	// by endpoints fail with a 428 Precondition Required error, unless they
+	// carry an If-Match or an If-Unmodified-Since header. This prevents lost
+	// updates by clients overwriting a resource they haven't seen. The error
+	// is returned before the endpoint is called.
+	RequirePreconditions bool
+
+	// OptionsMaxAge, when positive, lets clients and caches store the
+	// responses to OPTIONS requests for that long, with a Cache-Control:
+	// max-age header. It's also used as the Access-Control-Max-Age of CORS
+	// preflight requests when the MaxAge of the CORS policy is zero.
+	OptionsMaxAge time.Duration
+
+	// DecompressRequests enables the transparent decompression of request
+	// bodies encoded with gzip or deflate. Handlers read the decompressed data
+	// from the body of the request, and the Content-Encoding header is removed.
+	DecompressRequests bool
+
+	// ResponseCache, when set, keeps the encoded representations of the
+	// resources served by the mux to reuse them as long as their ETag doesn't
+	// change. See the ResponseCache type for details.
+	ResponseCache *ResponseCache
+
+	// Requests taking longer than Timeout to be handled are answered with a
+	// 503 Service Unavailable error, whose description is TimeoutMessage if
+	// set. A value of 0 disables the timeout.
+	Timeout        time.Duration
+	TimeoutMessage string
+
+	// LogRequests writes the method, the matched pattern, the duration, and
+	// the status code of the requests served by the mux in Logger, like in
+	// "GET /people/{id} 200 12.5ms", followed by the ID of the request when
+	// PropagateRequestID is enabled. Only the requests taking longer than
+	// SlowRequestThreshold are logged, and a value of 0 logs all of them.
+	LogRequests          bool
+	SlowRequestThreshold time.Duration
+
+	// ExternalURL is the scheme and the host under which the mux is reached
+	// by clients, like https://api.example.com, used by URL to return absolute
+	// URLs. URL returns paths when it's empty. It also replaces the scheme and
+	// the host of requests in the URLs of the Content-Location and Link
+	// headers, which are wrong behind a proxy terminating TLS.
+	ExternalURL string
+
+	// PropagateRequestID gives an ID to every request served by the mux, which
+	// is the one sent by the client in the RequestIDHeader header if it's a
+	// UUID or a ULID, or a new random UUID otherwise. The ID is echoed in the
+	// same header of the response, can be read by handlers with RequestID,
+	// and is used as the correlation ID of internal server errors to match
+	// them with the logs of the client. RequestIDHeader defaults to
+	// DefaultRequestIDHeader.
+	PropagateRequestID bool
+	RequestIDHeader    string
+
+	// ResponseHooks are called in order before the header of every response
+	// served by the mux is written, including errors, once the handler has set
+	// the header and chosen the status code. They can add, change, or remove
+	// headers, like the Location of redirections or headers leaking details
+	// of the implementation:
+	//
+	//	mux.ResponseHooks = append(mux.ResponseHooks, func(status int, header http.Header, r *http.Request) {
+	//		header.Del("X-Powered-By")
+	//	})
+	ResponseHooks []ResponseHook
+
+	// Tracer, when set, is notified of the beginning and of the end of every
+	// request served by the mux. See the Tracer interface for details.
+	Tracer Tracer
+
 	header http.Header
 	ac     *AccessControlResponse
-	m      *gorillaMux.Router
+
+	notFound         http.Handler
+	methodNotAllowed http.Handler
+
+	life lifecycle
+
+	mu       sync.RWMutex // guards routes, m and modified
+	routes   []*route
+	m        *routeTree
+	modified time.Time // Last change of the routing table.
+}
+
+// route is a pattern registered in a Mux with its handler.
+type route struct {
+	pattern string
+	handler http.Handler
+	name    string // Empty for the routes registered without a name.
 }
 
 // NewMux initializes a new REST multiplexer.
 func NewMux() *Mux {
 	s := &Mux{
-		Logger: log.New(os.Stdout, "rst: ", log.LstdFlags),
-		header: make(http.Header),
-		m:      gorillaMux.NewRouter(),
+		Logger:         log.New(os.Stdout, "rst: ", log.LstdFlags),
+		MaxURLLength:   DefaultMaxURLLength,
+		MaxHeaderBytes: DefaultMaxHeaderBytes,
+		header:         make(http.Header),
+		m:              newRouteTree(),
 	}
 	return s
 }
 
 // Header contains the headers that will automatically be set in all responses
-// served from this mux.
+// served from this mux, including errors. Values are written as they are, and
+// headers with multiple values are written in as many fields, in order. This
+// makes it suitable for security and reporting headers, like
+// Content-Security-Policy and Report-To, whose JSON values can be encoded with
+// JSONHeader.
 func (s *Mux) Header() http.Header {
 	return s.header
 }
@@ -331,19 +815,127 @@ func (s *Mux) SetCORSPolicy(ac *AccessControlResponse) {
 	s.ac = ac
 }
 
+// NotFoundHandler sets the handler that will respond to requests for which no
+// route or endpoint could be found. A nil value restores the default handler,
+// which serves NotFound().
+func (s *Mux) NotFoundHandler(h http.Handler) {
+	s.notFound = h
+}
+
+// MethodNotAllowedHandler sets the handler that will respond to requests with
+// a method not allowed by the matched endpoint. The Allow header is set before
+// h is called. A nil value restores the default handler, which serves
+// MethodNotAllowed().
+func (s *Mux) MethodNotAllowedHandler(h http.Handler) {
+	s.methodNotAllowed = h
+}
+
 func (s *Mux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Requests to paths which aren't clean, like //a/../b, are redirected to
+	// their clean form.
+	if r.Method != "CONNECT" {
+		if p := cleanPath(r.URL.Path); p != r.URL.Path {
+			u := *r.URL
+			u.Path, u.RawPath = p, ""
+			w.Header().Set("Location", u.String())
+			w.WriteHeader(http.StatusMovedPermanently)
+			return
+		}
+	}
+
+	m := getRouteMatch()
+	defer putRouteMatch(m)
+	rt, extension := s.match(r.URL.Path, m)
+
+	if s.Tracer != nil {
+		var pattern string
+		if rt != nil {
+			pattern = rt.pattern
+		}
+		if tr := s.Tracer.StartRequest(r, pattern); tr != nil {
+			r = tr
+		}
+		// The response is written as is, and only observed.
+		tw := &responseWriter{ResponseWriter: w, encoded: true}
+		w = tw
+		defer func() {
+			status := tw.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			s.Tracer.FinishRequest(r, pattern, status)
+		}()
+	}
+
+	// Set below when PropagateRequestID is enabled.
+	var requestID string
+
+	if s.LogRequests {
+		lw := &responseWriter{ResponseWriter: w, encoded: true}
+		w = lw
+		start := time.Now()
+		defer func() { s.logRequest(r, rt, lw, requestID, start) }()
+	}
+
+	if len(s.ResponseHooks) > 0 {
+		hw := &responseWriter{ResponseWriter: w, encoded: true}
+		hw.beforeHeader = func(code int) {
+			for _, hook := range s.ResponseHooks {
+				hook(code, hw.Header(), r)
+			}
+		}
+		w = hw
+		// Responses without a body are given a header too.
+		defer func() {
+			if hw.status == 0 {
+				hw.WriteHeader(http.StatusOK)
+			}
+		}()
+	}
+
+	if s.ContentType != "" {
+		r.Header.Set("Accept", s.ContentType)
+	}
+
+	tr, done := s.track(r)
+	if done == nil {
+		setMux(r, s)
+		defer clearContext(r)
+		s.writeError(shuttingDown(), w, r)
+		return
+	}
+	defer done()
+	r = tr
+
+	if s.PropagateRequestID {
+		requestID = s.requestID(w, r)
+	}
+
 	defer func() {
 		if err := recover(); err != nil {
 			reason := fmt.Sprintf("%s", err) // Stringer interface
+			id := requestID
+			if id == "" {
+				id = newErrorID()
+			}
 			if !s.Debug {
 				t := InternalServerError(reason, "", true)
+				t.ID = id
 				s.Logger.Println(t.String())
 				reason = "internal server error"
 			}
-			InternalServerError(reason, "", s.Debug).ServeHTTP(w, r)
+			e := InternalServerError(reason, "", s.Debug)
+			e.ID = id
+			if s.Debug {
+				e.Causes = errorCauses(err)
+			}
+			s.writeError(e, w, r)
 		}
 	}()
 
+	setMux(r, s)
+	defer clearContext(r)
+
 	// Custom headers are written no matter what.
 	for key, values := range s.header {
 		for i, value := range values {
@@ -355,23 +947,179 @@ func (s *Mux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	match := s.match(r)
-	if match == nil || match.Handler == nil {
-		NotFound().ServeHTTP(w, r)
+	if err := s.checkLimits(r); err != nil {
+		s.writeError(err, w, r)
 		return
 	}
 
-	setVars(r, RouteVars(match.Vars))
-	defer delVars(r)
+	if err := s.prepareBody(r); err != nil {
+		s.writeError(err, w, r)
+		return
+	}
 
-	if s.ac != nil {
-		if handler, valid := match.Handler.(*endpointHandler); valid {
-			newAccessControlHandler(handler.endpoint, s.ac).ServeHTTP(w, r)
+	if rt == nil {
+		if s.notFound != nil {
+			s.notFound.ServeHTTP(w, r)
 		} else {
-			newAccessControlHandler(nil, s.ac).ServeHTTP(w, r)
+			s.writeError(NotFound(), w, r)
+		}
+		return
+	}
+
+	setVars(r, m.vars)
+	setPattern(r, rt.pattern)
+
+	handler := rt.handler
+	endpoint := endpointOf(handler)
+	writeDeprecationHeaders(endpoint, w, r)
+	if s.ac != nil {
+		newAccessControlHandler(endpoint, s.ac).ServeHTTP(w, r)
+	}
+
+	if extension != "" && s.ContentType == "" {
+		forceFormat(r, extension)
+	}
+	if s.FormatParam != "" && s.ContentType == "" {
+		if format := r.URL.Query().Get(s.FormatParam); format != "" {
+			if err := forceFormat(r, format); err != nil {
+				s.writeError(err, w, r)
+				return
+			}
+		}
+	}
+
+	if endpoint != nil {
+		if fallback := s.fallbackHandler(endpoint, w, r); fallback != nil {
+			fallback.ServeHTTP(w, r)
+			return
+		}
+	}
+	if s.Timeout > 0 {
+		s.serveWithTimeout(rt, m.vars.All(), w, r)
+		return
+	}
+	handler.ServeHTTP(newResponseWriter(w), r)
+}
+
+// logRequest logs r, served by rt with w since start, if it took longer than
+// s.SlowRequestThreshold. The ID of the request is appended when it's set.
+func (s *Mux) logRequest(r *http.Request, rt *route, w *responseWriter, id string, start time.Time) {
+	elapsed := time.Since(start)
+	if elapsed < s.SlowRequestThreshold {
+		return
+	}
+	pattern := r.URL.Path // Requests which don't match any route.
+	if rt != nil {
+		pattern = rt.pattern
+	}
+	status := w.Status()
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if id != "" {
+		s.Logger.Printf("%s %s %d %s %s", r.Method, pattern, status, elapsed, id)
+		return
+	}
+	s.Logger.Printf("%s %s %d %s", r.Method, pattern, status, elapsed)
+}
+
+// writeError writes err in the response with s.ErrorRenderer if set, or with
+// err.ServeHTTP otherwise.
+//
+// Internal server errors are given a correlation ID, which is written in the
+// X-Error-ID header and in the body of the response, and is the ID of the
+// request when PropagateRequestID is enabled. Operators can find the
+// matching entry in the logs of s, where every error with status code 500
+// returned by an endpoint is printed.
+func (s *Mux) writeError(err *Error, w http.ResponseWriter, r *http.Request) {
+	// Errors can be shared by requests, like the ones declared in variables,
+	// and are annotated on a copy.
+	e := *err
+	e.Header = err.Header.Clone()
+	if e.Header == nil {
+		e.Header = make(http.Header)
+	}
+	err = &e
+
+	if err.Code == http.StatusInternalServerError {
+		if err.ID == "" {
+			err.ID = errorID(r)
+			if err.Err != nil {
+				s.Logger.Println(err.String() + "\nCause: " + err.Err.Error())
+			} else {
+				s.Logger.Println(err.String())
+			}
+		}
+		if s.Debug && err.Err != nil && err.Causes == nil {
+			err.Causes = errorCauses(err)
+		}
+		err.Header.Set("X-Error-ID", err.ID)
+	}
+	if s.CloseConnection != nil && s.CloseConnection(err, r) {
+		err.Header.Set("Connection", "close")
+	}
+
+	if s.ErrorRenderer != nil {
+		s.ErrorRenderer(err, w, r)
+		return
+	}
+	err.ServeHTTP(w, r)
+}
+
+// endpointOf returns the endpoint served by h, or nil if h is not a handler
+// returned by EndpointHandler.
+func endpointOf(h http.Handler) Endpoint {
+	switch t := h.(type) {
+	case *endpointHandler:
+		return t.endpoint
+	case *idempotencyHandler:
+		return endpointOf(t.handler)
+	}
+	return nil
+}
+
+// fallbackHandler returns the custom handler set in s to respond to r if
+// endpoint doesn't support its method, or nil if the request should be handled
+// by the endpoint itself.
+func (s *Mux) fallbackHandler(endpoint Endpoint, w http.ResponseWriter, r *http.Request) http.Handler {
+	if getMethodHandler(endpoint, r.Method, r.Header) != nil {
+		return nil
+	}
+	if allowed := AllowedMethods(endpoint); len(allowed) > 0 {
+		if s.methodNotAllowed != nil {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+		}
+		return s.methodNotAllowed
+	}
+	return s.notFound
+}
+
+// checkLimits returns an error if the URI or the header fields of r exceed
+// the limits set in s.
+func (s *Mux) checkLimits(r *http.Request) *Error {
+	if s.MaxURLLength > 0 {
+		uri := r.RequestURI
+		if uri == "" {
+			uri = r.URL.RequestURI()
+		}
+		if len(uri) > s.MaxURLLength {
+			return URITooLong()
+		}
+	}
+
+	if s.MaxHeaderBytes > 0 {
+		size := 0
+		for key, values := range r.Header {
+			for _, value := range values {
+				// Accounting for the ": " separator and the trailing CRLF.
+				size += len(key) + len(value) + 4
+			}
+		}
+		if size > s.MaxHeaderBytes {
+			return RequestHeaderFieldsTooLarge()
 		}
 	}
-	match.Handler.ServeHTTP(newResponseWriter(w), r)
+	return nil
 }
 
 // HandleEndpoint registers the endpoint for the given pattern.
@@ -381,18 +1129,212 @@ func (s *Mux) HandleEndpoint(pattern string, endpoint Endpoint) {
 	s.Handle(pattern, EndpointHandler(endpoint))
 }
 
-// Handle registers the handler function for the given pattern.
+/*
+Handle registers the handler function for the given pattern.
+
+Variables can be constrained with a regular expression, like in
+/users/{id:[0-9]+}. Handle panics if the expression is invalid.
+
+The last segment of pattern can be made optional with a question mark after
+the name of its variable, like in /reports/{year?}, which matches both
+/reports and /reports/2024.
+
+Routes can be registered while s is serving requests. The routing table is
+copied and replaced on every registration, which means that a request is
+always matched against either the table before the change, or the one after
+it, and never against a partially updated one. Requests being served when a
+route is registered are not affected by the change.
+*/
 func (s *Mux) Handle(pattern string, handler http.Handler) {
-	s.m.Handle(pattern, handler)
+	s.handle(&route{pattern: pattern, handler: handler})
 }
 
-// match returns the route
-func (s *Mux) match(r *http.Request) *gorillaMux.RouteMatch {
-	var match gorillaMux.RouteMatch
-	if !s.m.Match(r, &match) {
-		return nil
+/*
+HandleNamed registers the endpoint for the given pattern, like HandleEndpoint,
+and gives the route a name by which it can be referenced with URL and Route,
+whatever its pattern:
+
+	mux.HandleNamed("user", "/users/{id}", &UserEP{})
+
+	location, err := mux.URL("user", rst.RouteVars{"id": user.ID})
+
+Names are unique in a mux, and HandleNamed panics if name is empty or is
+already the name of another route.
+*/
+func (s *Mux) HandleNamed(name, pattern string, endpoint Endpoint) {
+	if name == "" {
+		panic(fmt.Errorf("rst: route %s can't be registered with an empty name", pattern))
+	}
+	s.handle(&route{pattern: pattern, handler: EndpointHandler(endpoint), name: name})
+}
+
+// handle adds rt to the routing table of s.
+func (s *Mux) handle(rt *route) {
+	// Invalid patterns panic before the routing table is changed.
+	validatePattern(rt.pattern)
+	expandPattern(rt.pattern)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rt.name != "" {
+		for _, existing := range s.routes {
+			if existing.name == rt.name {
+				panic(fmt.Errorf("rst: route %s can't be named %s, which is the name of route %s", rt.pattern, rt.name, existing.pattern))
+			}
+		}
+	}
+
+	routes := make([]*route, len(s.routes), len(s.routes)+1)
+	copy(routes, s.routes)
+	s.setRoutes(append(routes, rt))
+}
+
+// Unhandle removes the routes registered with pattern, and returns true if at
+// least one route was removed.
+//
+// Like Handle, Unhandle can be called while s is serving requests.
+func (s *Mux) Unhandle(pattern string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var routes []*route
+	for _, rt := range s.routes {
+		if rt.pattern != pattern {
+			routes = append(routes, rt)
+		}
+	}
+	if len(routes) == len(s.routes) {
+		return false
+	}
+	s.setRoutes(routes)
+	return true
+}
+
+// setRoutes replaces the routing table of s with a new router built from
+// routes. s.mu must be held by the caller.
+func (s *Mux) setRoutes(routes []*route) {
+	m := newRouteTree()
+	for _, rt := range routes {
+		for _, pattern := range expandPattern(rt.pattern) {
+			m.handle(pattern, rt)
+		}
+	}
+	s.routes = routes
+	s.m = m
+	s.modified = time.Now().UTC().Truncate(time.Second)
+}
+
+// RouteInfo describes a route registered in a Mux.
+type RouteInfo struct {
+	Name    string   `json:"name,omitempty" xml:"name,attr,omitempty"` // Set for named routes only.
+	Pattern string   `json:"pattern" xml:"pattern,attr"`
+	Methods []string `json:"methods,omitempty" xml:"method"` // Set for endpoints only.
+}
+
+// Routes returns the routes registered in s, in the order in which they were
+// registered. The methods allowed by endpoints are listed in their route.
+func (s *Mux) Routes() []RouteInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	routes := make([]RouteInfo, 0, len(s.routes))
+	for _, rt := range s.routes {
+		routes = append(routes, rt.info())
+	}
+	return routes
+}
+
+// Route returns the description of the route registered in s with name by
+// HandleNamed, and false if there is none.
+func (s *Mux) Route(name string) (RouteInfo, bool) {
+	if rt := s.namedRoute(name); rt != nil && rt.name == name {
+		return rt.info(), true
+	}
+	return RouteInfo{}, false
+}
+
+// namedRoute returns the route of s named name, or the first route registered
+// with name as its pattern, or nil.
+func (s *Mux) namedRoute(name string) *route {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var found *route
+	for _, rt := range s.routes {
+		if rt.name == name {
+			return rt
+		}
+		if rt.pattern == name && found == nil {
+			found = rt
+		}
+	}
+	return found
+}
+
+func (rt *route) info() RouteInfo {
+	info := RouteInfo{Name: rt.name, Pattern: rt.pattern}
+	if endpoint := endpointOf(rt.handler); endpoint != nil {
+		info.Methods = AllowedMethods(endpoint)
+	}
+	return info
+}
+
+/*
+URL returns the path of the route named name with HandleNamed, filled with the
+values of vars, which saves endpoints from building the URLs of the resources
+they link to by hand:
+
+	mux.HandleNamed("user", "/users/{id}", &UserEP{})
+
+	location, err := mux.URL("user", rst.RouteVars{"id": user.ID})
+	// location is /users/42
+
+Routes registered without a name are referenced by their pattern, like in
+mux.URL("/users/{id}", vars).
+
+An error is returned if no route was registered with name, if a variable of
+the pattern is missing from vars, or if its value doesn't match the regular
+expression of the variable. Optional variables missing from vars are omitted.
+
+The path is prefixed with s.ExternalURL when it's set.
+*/
+func (s *Mux) URL(name string, vars RouteVars) (string, error) {
+	rt := s.namedRoute(name)
+	if rt == nil {
+		return "", fmt.Errorf("rst: no route registered with %s", name)
 	}
-	return &match
+
+	path, err := fillPattern(rt.pattern, vars)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(s.ExternalURL, "/") + path, nil
+}
+
+// match returns the route matching path, and sets its variables in m. If
+// s.PathExtensions is true and path ends with a known extension, the route
+// matching path without it is preferred, and the extension is returned.
+func (s *Mux) match(path string, m *routeMatch) (*route, string) {
+	router := s.router()
+	if s.PathExtensions {
+		if i := strings.LastIndex(path, "."); i > strings.LastIndex(path, "/") {
+			extension := path[i+1:]
+			if _, known := Formats[strings.ToLower(extension)]; known {
+				if rt := router.match(path[:i], m); rt != nil {
+					return rt, extension
+				}
+			}
+		}
+	}
+	return router.match(path, m), ""
+}
+
+// router returns the current routing table of s.
+func (s *Mux) router() *routeTree {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m
 }
 
 // Envelope is a wrapper to allow any interface{} to be used as an rst.Resource
@@ -9,7 +9,10 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"log"
 	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -152,6 +155,56 @@ func TestMuxHeaders(t *testing.T) {
 	test(testServerAddr + "/manu") // 404 NOT FOUND
 }
 
+func TestReportingHeaders(t *testing.T) {
+	group := map[string]interface{}{
+		"group":     "csp-endpoint",
+		"max_age":   10886400,
+		"endpoints": []interface{}{map[string]interface{}{"url": "https://example.com/reports?app=web&env=prod"}},
+	}
+	reportTo, err := JSONHeader(group)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(reportTo, "app=web&env=prod") {
+		t.Fatal("JSONHeader escaped the URL. Got:", reportTo)
+	}
+	values := []string{reportTo, `{"group":"network-errors","max_age":2592000,"endpoints":[{"url":"https://example.com/nel"}]}`}
+	csp := "default-src 'self'; report-uri https://example.com/reports?app=web&env=prod; report-to csp-endpoint"
+
+	for _, value := range values {
+		testMux.Header().Add("Report-To", value)
+	}
+	testMux.Header().Set("Content-Security-Policy", csp)
+	defer testMux.Header().Del("Report-To")
+	defer testMux.Header().Del("Content-Security-Policy")
+
+	var test = func(addr string) {
+		resp, err := http.Get(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		got := resp.Header["Report-To"]
+		if strings.Join(got, "\n") != strings.Join(values, "\n") {
+			t.Fatal("Report-To. Got:", got, "Wanted:", values)
+		}
+		var decoded map[string]interface{}
+		if err := json.Unmarshal([]byte(got[0]), &decoded); err != nil {
+			t.Fatal("Report-To is not valid JSON:", err)
+		}
+		if fmt.Sprint(decoded["endpoints"]) != fmt.Sprint(group["endpoints"]) {
+			t.Fatal("Report-To endpoints. Got:", decoded["endpoints"], "Wanted:", group["endpoints"])
+		}
+		if v := resp.Header.Get("Content-Security-Policy"); v != csp {
+			t.Fatal("Content-Security-Policy. Got:", v, "Wanted:", csp)
+		}
+	}
+
+	test(testSafeURL)              // 200 OK
+	test(testServerAddr + "/manu") // 404 NOT FOUND
+}
+
 func TestBypass(t *testing.T) {
 	rr := newRequestResponse(Post, testServerAddr+"/bypass", nil, nil)
 	if err := rr.TestBody(bytes.NewBuffer(testCannedBytes)); err != nil {
@@ -216,6 +269,10 @@ func TestResponseCompression(t *testing.T) {
 		t.Fatal("gzip Accept-Encoding with small sized data:", err)
 	}
 
+	if vary := strings.Join(rrGzipNoThreshold.resp.Header["Vary"], ", "); strings.Contains(vary, "Accept-Encoding") {
+		t.Fatal("gzip Accept-Encoding with small sized data: Vary. Got:", vary)
+	}
+
 	// Accept-Encoding: deflate
 	header.Set("Accept-Encoding", "deflate")
 	rrFlate := newRequestResponse(Post, testEchoURL, header, bytes.NewReader(testMBText))
@@ -232,6 +289,76 @@ func TestResponseCompression(t *testing.T) {
 	}
 }
 
+// Testing that compression is never used when the client excludes it, even on
+// payloads over CompressionThreshold.
+func TestCompressionIdentityForced(t *testing.T) {
+	header := make(http.Header)
+	header.Set("Accept-Encoding", "identity;q=1, gzip;q=0")
+
+	rr := newRequestResponse(Post, testEchoURL, header, bytes.NewReader(testMBText))
+	if err := rr.TestStatusCode(http.StatusCreated); err != nil {
+		t.Fatal(err)
+	}
+	if err := rr.TestHeader("Content-Encoding", ""); err != nil {
+		t.Fatal(err)
+	}
+	if vary := strings.Join(rr.resp.Header["Vary"], ", "); !strings.Contains(vary, "Accept-Encoding") {
+		t.Fatal("Vary. Got:", vary, "Wanted: Accept-Encoding")
+	}
+	if err := rr.TestBody(bytes.NewReader(testMBText)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// precompressed is a blob whose data is already compressed.
+type precompressed struct {
+	*blob
+}
+
+func (p *precompressed) Compressible() bool {
+	return false
+}
+
+// precompressedStream is a stream whose data is already compressed.
+type precompressedStream struct {
+	*sectionResource
+}
+
+func (p *precompressedStream) Compressible() bool {
+	return false
+}
+
+func TestCompressibleOptOut(t *testing.T) {
+	mux := NewMux()
+	mux.Handle("/archive.zip", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return &precompressed{Blob("application/zip", testMBText).(*blob)}, nil
+	}))
+	mux.Handle("/archive.tar.gz", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		reader := bytes.NewReader(testMBText)
+		return &precompressedStream{ReaderAt("application/gzip", reader, reader.Size(), time.Now()).(*sectionResource)}, nil
+	}))
+	mux.Handle("/text", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return Text(string(testMBText)), nil
+	}))
+
+	var test = func(path, encoding string) {
+		r, _ := http.NewRequest(Get, path, nil)
+		r.Header.Set("Accept-Encoding", "gzip, deflate")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if got := w.Header().Get("Content-Encoding"); got != encoding {
+			t.Fatal(path, "Content-Encoding. Got:", got, "Wanted:", encoding)
+		}
+		if encoding == "" && !bytes.Equal(w.Body.Bytes(), testMBText) {
+			t.Fatal(path, "body. Got:", w.Body.Len(), "bytes. Wanted:", len(testMBText))
+		}
+	}
+
+	test("/archive.zip", "")
+	test("/archive.tar.gz", "")
+	test("/text", "gzip")
+}
+
 func TestEnvelope(t *testing.T) {
 
 	var test = func(accept string, body io.Reader) {
@@ -269,3 +396,461 @@ func TestEnvelope(t *testing.T) {
 	test("application/json", bytes.NewReader(b))
 	test("text/plain", bytes.NewReader([]byte(envelopeTextProjection)))
 }
+
+func TestMuxLimits(t *testing.T) {
+	maxURLLength, maxHeaderBytes := testMux.MaxURLLength, testMux.MaxHeaderBytes
+	defer func() {
+		testMux.MaxURLLength = maxURLLength
+		testMux.MaxHeaderBytes = maxHeaderBytes
+	}()
+	testMux.MaxURLLength = 64
+	testMux.MaxHeaderBytes = 256
+
+	var test = func(url string, header http.Header, expected int) {
+		rr := newRequestResponse(Get, url, header, nil)
+		if err := rr.TestStatusCode(expected); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// URI length
+	test(testSafeURL+"?q="+strings.Repeat("a", 32), nil, http.StatusOK)
+	test(testSafeURL+"?q="+strings.Repeat("a", 64), nil, http.StatusRequestURITooLong)
+
+	// Header size
+	header := make(http.Header)
+	header.Set("X-Padding", strings.Repeat("a", 64))
+	test(testSafeURL, header, http.StatusOK)
+	header.Set("X-Padding", strings.Repeat("a", 512))
+	test(testSafeURL, header, http.StatusRequestHeaderFieldsTooLarge)
+
+	// Disabled limits
+	testMux.MaxURLLength, testMux.MaxHeaderBytes = 0, 0
+	test(testSafeURL+"?q="+strings.Repeat("a", 64), header, http.StatusOK)
+}
+
+// TestMuxConcurrentHandle registers routes while requests are being served.
+// Run with -race to detect unsynchronized accesses to the routing table.
+func TestMuxConcurrentHandle(t *testing.T) {
+	mux := NewMux()
+	mux.Handle("/bypass", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(testCannedBytes)
+	}))
+
+	done := make(chan bool)
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			mux.HandleEndpoint(fmt.Sprintf("/dynamic/%d", i), &envelopeEndpoint{})
+		}
+	}()
+
+	for i := 0; i < 500; i++ {
+		r, _ := http.NewRequest(Get, fmt.Sprintf("/dynamic/%d", i%100), nil)
+		mux.ServeHTTP(httptest.NewRecorder(), r)
+
+		r, _ = http.NewRequest(Get, "/bypass", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatal("status code. Got:", w.Code, "Wanted:", http.StatusOK)
+		}
+	}
+	<-done
+
+	r, _ := http.NewRequest(Get, "/dynamic/99", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatal("status code. Got:", w.Code, "Wanted:", http.StatusOK)
+	}
+}
+
+func TestMuxUnhandle(t *testing.T) {
+	var test = func(mux *Mux, path string, expected int) {
+		r, _ := http.NewRequest(Get, path, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal(path, "status code. Got:", w.Code, "Wanted:", expected)
+		}
+	}
+
+	mux := NewMux()
+	mux.HandleEndpoint("/envelope", &envelopeEndpoint{})
+	mux.HandleEndpoint("/text", &textEndpoint{})
+	test(mux, "/envelope", http.StatusOK)
+
+	if !mux.Unhandle("/envelope") {
+		t.Fatal("Unhandle returned false for a registered pattern")
+	}
+	test(mux, "/envelope", http.StatusNotFound)
+	test(mux, "/text", http.StatusOK)
+
+	if mux.Unhandle("/envelope") {
+		t.Fatal("Unhandle returned true for an unregistered pattern")
+	}
+}
+
+func TestMuxHandleNamed(t *testing.T) {
+	mux := NewMux()
+	mux.HandleNamed("user", "/users/{id}", &getterOnly{})
+	mux.HandleEndpoint("/text", &textEndpoint{})
+
+	info, found := mux.Route("user")
+	if want := (RouteInfo{"user", "/users/{id}", AllowedMethods(&getterOnly{})}); !found || !reflect.DeepEqual(info, want) {
+		t.Fatal("Route. Got:", info, found, "Wanted:", want)
+	}
+	if info, found := mux.Route("/text"); found {
+		t.Fatal("Route of an unnamed route. Got:", info)
+	}
+	if got, err := mux.URL("user", RouteVars{"id": "42"}); err != nil || got != "/users/42" {
+		t.Fatal("URL. Got:", got, err, "Wanted: /users/42")
+	}
+
+	var panics = func(name, pattern string) {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("No panic for", name, pattern)
+			}
+		}()
+		mux.HandleNamed(name, pattern, &getterOnly{})
+	}
+	panics("user", "/people/{id}")
+	panics("", "/people/{id}")
+
+	r, _ := http.NewRequest(Get, "/people/1", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	if w.Code != http.StatusNotFound {
+		t.Fatal("A route rejected at registration is served. Got:", w.Code)
+	}
+
+	// The name is released once its route is removed.
+	mux.Unhandle("/users/{id}")
+	mux.HandleNamed("user", "/people/{id}", &getterOnly{})
+	if got, err := mux.URL("user", RouteVars{"id": "42"}); err != nil || got != "/people/42" {
+		t.Fatal("URL. Got:", got, err, "Wanted: /people/42")
+	}
+}
+
+func TestMuxCustomErrorHandlers(t *testing.T) {
+	var test = func(mux *Mux, method, path string, expected int, body string) *httptest.ResponseRecorder {
+		r, _ := http.NewRequest(method, path, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal(path, "status code. Got:", w.Code, "Wanted:", expected)
+		}
+		if body != "" && w.Body.String() != body {
+			t.Fatal(path, "body. Got:", w.Body.String(), "Wanted:", body)
+		}
+		return w
+	}
+
+	var requested []string
+	mux := NewMux()
+	mux.HandleEndpoint("/envelope", &envelopeEndpoint{})
+	mux.HandleEndpoint("/empty", struct{}{})
+	mux.NotFoundHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		w.Header().Set("Link", "<https://example.com/docs>; rel=\"help\"")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("custom not found"))
+	}))
+	mux.MethodNotAllowedHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		w.Write([]byte("custom method not allowed"))
+	}))
+
+	w := test(mux, Get, "/unknown", http.StatusNotFound, "custom not found")
+	if w.Header().Get("Link") == "" {
+		t.Fatal("expected custom not found handler to set the Link header")
+	}
+	test(mux, Get, "/empty", http.StatusNotFound, "custom not found")
+	if len(requested) != 2 || requested[0] != "/unknown" || requested[1] != "/empty" {
+		t.Fatal("custom not found handler requests. Got:", requested)
+	}
+
+	w = test(mux, Delete, "/envelope", http.StatusMethodNotAllowed, "custom method not allowed")
+	if allow := w.Header().Get("Allow"); allow != "HEAD, GET" {
+		t.Fatal("Allow header. Got:", allow, "Wanted: HEAD, GET")
+	}
+	test(mux, Get, "/envelope", http.StatusOK, "")
+
+	// Defaults
+	mux.NotFoundHandler(nil)
+	mux.MethodNotAllowedHandler(nil)
+	test(mux, Get, "/unknown", http.StatusNotFound, "")
+	test(mux, Delete, "/envelope", http.StatusMethodNotAllowed, "")
+}
+
+func TestMuxErrorRenderer(t *testing.T) {
+	var codes []int
+	mux := NewMux()
+	mux.HandleEndpoint("/people/{id}", &personResource{})
+	mux.ErrorRenderer = func(err error, w http.ResponseWriter, r *http.Request) {
+		e, ok := err.(*Error)
+		if !ok {
+			t.Fatal("ErrorRenderer received an error that is not an *Error:", err)
+		}
+		codes = append(codes, e.Code)
+		w.Header().Set("X-Trace-Id", "trace")
+		w.WriteHeader(e.Code)
+	}
+
+	var test = func(method, path string, expected int) {
+		r, _ := http.NewRequest(method, path, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal(path, "status code. Got:", w.Code, "Wanted:", expected)
+		}
+		if w.Header().Get("X-Trace-Id") != "trace" {
+			t.Fatal(path, "expected response to be written by ErrorRenderer")
+		}
+	}
+
+	test(Get, "/unknown", http.StatusNotFound)        // no route
+	test(Get, "/people/unknown", http.StatusNotFound) // returned by the endpoint
+	test(Post, "/people/unknown", http.StatusMethodNotAllowed)
+
+	expected := []int{http.StatusNotFound, http.StatusNotFound, http.StatusMethodNotAllowed}
+	for i, code := range expected {
+		if codes[i] != code {
+			t.Fatal("ErrorRenderer codes. Got:", codes, "Wanted:", expected)
+		}
+	}
+}
+
+func TestCloseConnection(t *testing.T) {
+	shared := BadRequest("", "")
+	mux := NewMux()
+	mux.Handle("/shared", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return nil, shared
+	}))
+	mux.Handle("/people", postFunc(func(vars RouteVars, r *http.Request) (Resource, string, error) {
+		var v map[string]string
+		if err := DecodeJSON(r, &v); err != nil {
+			return nil, "", err
+		}
+		return nil, "/people/1", nil
+	}))
+
+	var test = func(body string, expected int, connection string) {
+		r, _ := http.NewRequest(Post, "/people", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal(body, "status code. Got:", w.Code, "Wanted:", expected)
+		}
+		if got := w.Header().Get("Connection"); got != connection {
+			t.Fatal(body, "Connection. Got:", got, "Wanted:", connection)
+		}
+	}
+
+	test(`{"name":`, http.StatusBadRequest, "")
+
+	mux.CloseConnection = func(err *Error, r *http.Request) bool {
+		return err.Code == http.StatusBadRequest && r.ContentLength != 0
+	}
+	test(`{"name":`, http.StatusBadRequest, "close")
+	test(`{"name":"rst"}`, http.StatusCreated, "")
+
+	// The header is not left on errors shared by requests.
+	for _, body := range []string{"rst", ""} {
+		r, _ := http.NewRequest(Get, "/shared", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		connection := "close"
+		if body == "" {
+			connection = ""
+		}
+		if got := w.Header().Get("Connection"); got != connection {
+			t.Fatal("Shared error with body", body, "Connection. Got:", got, "Wanted:", connection)
+		}
+	}
+	if got := shared.Header.Get("Connection"); got != "" {
+		t.Fatal("The shared error was modified. Got:", got)
+	}
+}
+
+func TestResponseWriter(t *testing.T) {
+	// Explicit status code
+	w := newResponseWriter(httptest.NewRecorder())
+	if w.Status() != 0 {
+		t.Fatal("status before writing. Got:", w.Status(), "Wanted:", 0)
+	}
+	w.WriteHeader(http.StatusCreated)
+	w.Write(testCannedBytes)
+	if w.Status() != http.StatusCreated {
+		t.Fatal("status. Got:", w.Status(), "Wanted:", http.StatusCreated)
+	}
+	if w.Size() != len(testCannedBytes) {
+		t.Fatal("size. Got:", w.Size(), "Wanted:", len(testCannedBytes))
+	}
+
+	// Implicit 200 OK
+	w = newResponseWriter(httptest.NewRecorder())
+	w.Write(testCannedBytes)
+	w.Write(testCannedBytes)
+	if w.Status() != http.StatusOK {
+		t.Fatal("status. Got:", w.Status(), "Wanted:", http.StatusOK)
+	}
+	if w.Size() != 2*len(testCannedBytes) {
+		t.Fatal("size. Got:", w.Size(), "Wanted:", 2*len(testCannedBytes))
+	}
+
+	var _ http.Flusher = w
+	var _ http.Hijacker = w
+	if _, _, err := w.Hijack(); err == nil {
+		t.Fatal("expected Hijack to fail with a ResponseWriter that doesn't support it")
+	}
+}
+
+func TestRouteVars(t *testing.T) {
+	vars := RouteVars{"id": "42", "format": ""}
+
+	if !vars.Has("id") || !vars.Has("format") {
+		t.Fatal("Has returned false for an existing variable")
+	}
+	if vars.Has("name") {
+		t.Fatal("Has returned true for a missing variable")
+	}
+
+	all := vars.All()
+	if len(all) != 2 || all["id"] != "42" {
+		t.Fatal("All. Got:", all, "Wanted:", vars)
+	}
+	all["id"] = "0"
+	if vars.Get("id") != "42" {
+		t.Fatal("All did not return a copy. Got:", vars.Get("id"), "Wanted: 42")
+	}
+}
+
+// Compressors taken from the pools must produce valid streams, no matter how
+// many times they've been used before.
+func TestCompressorPool(t *testing.T) {
+	for _, format := range []string{gzipCompression, flateCompression} {
+		for i := 0; i < 20; i++ {
+			data := bytes.Repeat([]byte(fmt.Sprintf("payload %d ", i)), 100*(i+1))
+
+			w := httptest.NewRecorder()
+			rw := newResponseWriter(w)
+			rw.Header().Set("Content-Encoding", format)
+			rw.Write(data)
+
+			decompressed, err := decompress(ioutil.NopCloser(w.Body), format)
+			if err != nil {
+				t.Fatal(format, i, err)
+			}
+			if !bytes.Equal(decompressed, data) {
+				t.Fatal(format, i, "data was decompressed but did not match the expected value")
+			}
+		}
+	}
+}
+
+func BenchmarkCompressorPooled(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c := getCompressor(ioutil.Discard, gzipCompression)
+		c.Write(testCannedBytes)
+		putCompressor(c, gzipCompression)
+	}
+}
+
+func BenchmarkCompressorUnpooled(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c := gzip.NewWriter(ioutil.Discard)
+		c.Write(testCannedBytes)
+		c.Close()
+	}
+}
+
+func TestResponseHooks(t *testing.T) {
+	var statuses []int
+	mux := NewMux()
+	mux.ResponseHooks = []ResponseHook{
+		func(status int, header http.Header, r *http.Request) {
+			statuses = append(statuses, status)
+			header.Del("X-Powered-By")
+		},
+		func(status int, header http.Header, r *http.Request) {
+			if location := header.Get("Location"); strings.HasPrefix(location, "/") {
+				header.Set("Location", "https://api.example.com"+location)
+			}
+		},
+	}
+	mux.Handle("/people", postFunc(func(vars RouteVars, r *http.Request) (Resource, string, error) {
+		return Text(testCannedContent), "/people/42", nil
+	}))
+	mux.Handle("/empty", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Powered-By", "rst")
+	}))
+	mux.Header().Set("X-Powered-By", "rst")
+
+	var test = func(method, path string, status int, location string) {
+		statuses = nil
+		r, _ := http.NewRequest(method, path, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != status {
+			t.Fatal(path, "status code. Got:", w.Code, "Wanted:", status)
+		}
+		if len(statuses) != 1 || statuses[0] != status {
+			t.Fatal(path, "status codes seen by the hook. Got:", statuses, "Wanted:", status)
+		}
+		if got := w.Header().Get("X-Powered-By"); got != "" {
+			t.Fatal(path, "X-Powered-By. Got:", got, "Wanted: none")
+		}
+		if got := w.Header().Get("Location"); got != location {
+			t.Fatal(path, "Location. Got:", got, "Wanted:", location)
+		}
+	}
+
+	test(Post, "/people", http.StatusCreated, "https://api.example.com/people/42")
+	test(Get, "/empty", http.StatusOK, "")
+	test(Get, "/unknown", http.StatusNotFound, "")
+}
+
+func TestSlowRequestLogging(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	mux := NewMux()
+	mux.Logger = log.New(buffer, "", 0)
+	mux.LogRequests = true
+	mux.SlowRequestThreshold = 50 * time.Millisecond
+	mux.Handle("/fast/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	mux.Handle("/slow/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(60 * time.Millisecond)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	var test = func(path, logged string) {
+		buffer.Reset()
+		r, _ := http.NewRequest(Get, path, nil)
+		mux.ServeHTTP(httptest.NewRecorder(), r)
+		if got := buffer.String(); !strings.HasPrefix(got, logged) || (logged == "") != (got == "") {
+			t.Fatal(path, "Got:", got, "Wanted:", logged)
+		}
+	}
+
+	test("/fast/1", "")
+	test("/slow/1", "GET /slow/{id} 202 ")
+
+	mux.SlowRequestThreshold = 0
+	test("/fast/1", "GET /fast/{id} 200 ")
+	test("/unknown", "GET /unknown 404 ")
+
+	mux.PropagateRequestID = true
+	id := "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+	buffer.Reset()
+	r, _ := http.NewRequest(Get, "/fast/1", nil)
+	r.Header.Set(DefaultRequestIDHeader, id)
+	mux.ServeHTTP(httptest.NewRecorder(), r)
+	if got := strings.TrimSpace(buffer.String()); !strings.HasSuffix(got, " "+id) {
+		t.Fatal("Request ID. Got:", got, "Wanted:", id)
+	}
+}
@@ -0,0 +1,109 @@
+package rst
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+/*
+Schemer is implemented by endpoints declaring the JSON Schema of the body of
+their POST, PUT, and PATCH requests.
+
+	var personSchema = []byte(`{
+		"type": "object",
+		"properties": {"name": {"type": "string"}},
+		"required": ["name"]
+	}`)
+
+	func (ep *PeopleEP) Schema() []byte {
+		return personSchema
+	}
+
+The body is validated with SchemaValidator before the endpoint is called, and
+requests failing validation are rejected with a 422 Unprocessable Entity error
+listing the violations. The body can still be read by the endpoint.
+*/
+type Schemer interface {
+	Schema() []byte
+}
+
+/*
+SchemaValidator validates document against schema, and returns the violations
+it found. rst doesn't include a JSON Schema implementation, and bodies are not
+validated until SchemaValidator is set, usually with an adapter around a
+dedicated library:
+
+	rst.SchemaValidator = func(schema, document []byte) ([]*rst.FieldError, error) {
+		result, err := gojsonschema.Validate(
+			gojsonschema.NewBytesLoader(schema),
+			gojsonschema.NewBytesLoader(document),
+		)
+		if err != nil {
+			return nil, err
+		}
+		var violations []*rst.FieldError
+		for _, e := range result.Errors() {
+			violations = append(violations, &rst.FieldError{
+				Pointer: "/" + strings.Replace(e.Field(), ".", "/", -1),
+				Message: e.Description(),
+			})
+		}
+		return violations, nil
+	}
+
+Only the bodies sent without a Content-Type, or with a JSON media type like
+application/json or application/merge-patch+json, are validated. Bodies which
+aren't valid JSON are rejected with a 400 Bad Request error, and an error
+returned by SchemaValidator, like for a broken schema, results in a 500
+Internal Server Error.
+*/
+var SchemaValidator func(schema, document []byte) ([]*FieldError, error)
+
+// validateBody validates the body of r against the schema of endpoint, and
+// replaces it with a copy the endpoint can read.
+func validateBody(endpoint Endpoint, r *http.Request) *Error {
+	schemer, implemented := endpoint.(Schemer)
+	if !implemented || SchemaValidator == nil || r.Body == nil {
+		return nil
+	}
+	switch strings.ToUpper(r.Method) {
+	case Post, Put, Patch:
+	default:
+		return nil
+	}
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, err := mime.ParseMediaType(ct)
+		if err != nil || (mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json")) {
+			return nil
+		}
+	}
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		if e, ok := err.(*Error); ok {
+			return e
+		}
+		return BadRequest("", "The body of the request could not be read.")
+	}
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{bytes.NewReader(b), r.Body}
+
+	if !json.Valid(b) {
+		return BadRequest("", "The body of the request is not valid JSON.")
+	}
+	violations, err := SchemaValidator(schemer.Schema(), b)
+	if err != nil {
+		return InternalServerError("", "The body of the request could not be validated.", false).Wrap(err)
+	}
+	if len(violations) > 0 {
+		return UnprocessableEntity(violations...)
+	}
+	return nil
+}
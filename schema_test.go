@@ -0,0 +1,95 @@
+package rst
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// schemaWriter is an endpoint declaring a schema for the body of its POST
+// requests.
+type schemaWriter struct {
+	body string
+}
+
+func (ep *schemaWriter) Schema() []byte {
+	return []byte(`{"type":"object","properties":{"name":{"type":"string"}}}`)
+}
+
+func (ep *schemaWriter) Post(vars RouteVars, r *http.Request) (Resource, string, error) {
+	b, _ := ioutil.ReadAll(r.Body)
+	ep.body = string(b)
+	return nil, "", nil
+}
+
+// stringFieldsValidator rejects the documents whose name field is not a
+// string, in place of a real JSON Schema implementation.
+func stringFieldsValidator(schema, document []byte) ([]*FieldError, error) {
+	var v map[string]interface{}
+	if err := json.Unmarshal(document, &v); err != nil {
+		return nil, errors.New("invalid JSON")
+	}
+	if name, exists := v["name"]; exists {
+		if _, ok := name.(string); !ok {
+			return []*FieldError{{Pointer: "/name", Message: "Invalid type. Expected: string"}}, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestSchemaValidation(t *testing.T) {
+	defer func(original func([]byte, []byte) ([]*FieldError, error)) { SchemaValidator = original }(SchemaValidator)
+	endpoint := &schemaWriter{}
+	mux := NewMux()
+	mux.HandleEndpoint("/people", endpoint)
+
+	contentType := "application/json"
+	var test = func(body string, expected int) *httptest.ResponseRecorder {
+		endpoint.body = ""
+		r, _ := http.NewRequest(Post, "/people", strings.NewReader(body))
+		r.Header.Set("Content-Type", contentType)
+		r.Header.Set("Accept", "application/json")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Fatal(body, "status code. Got:", w.Code, "Wanted:", expected)
+		}
+		return w
+	}
+
+	// Bodies are not validated until a validator is set.
+	test(`{"name":42}`, http.StatusCreated)
+
+	SchemaValidator = stringFieldsValidator
+	test(`{"name":"rst"}`, http.StatusCreated)
+	if endpoint.body != `{"name":"rst"}` {
+		t.Fatal("Body read by the endpoint. Got:", endpoint.body, "Wanted:", `{"name":"rst"}`)
+	}
+	test(`{"name":`, http.StatusBadRequest)
+
+	w := test(`{"name":42}`, http.StatusUnprocessableEntity)
+	if endpoint.body != "" {
+		t.Fatal("The endpoint was called with an invalid body")
+	}
+	var e Error
+	if err := json.Unmarshal(w.Body.Bytes(), &e); err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Errors) != 1 || e.Errors[0].Pointer != "/name" {
+		t.Fatal("Invalid fields. Got:", w.Body.String(), "Wanted: /name")
+	}
+
+	// Validator errors are the fault of the server.
+	SchemaValidator = func(schema, document []byte) ([]*FieldError, error) {
+		return nil, errors.New("invalid schema")
+	}
+	test(`{"name":"rst"}`, http.StatusInternalServerError)
+
+	// Bodies which aren't JSON are not validated.
+	contentType = "application/xml"
+	test(`<person><name>rst</name></person>`, http.StatusCreated)
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -172,6 +173,130 @@ func (e *chunckedEchoResource) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	w.Write(e.content)
 }
 
+// rangedHandlerResource implements both Ranger and http.Handler.
+type rangedHandlerResource struct {
+	content []byte
+}
+
+func (e *rangedHandlerResource) LastModified() time.Time {
+	return testTimeReference
+}
+
+func (e *rangedHandlerResource) ETag() string {
+	return "ranged-handler"
+}
+
+func (e *rangedHandlerResource) TTL() time.Duration {
+	return 0
+}
+
+func (e *rangedHandlerResource) Units() []string {
+	return []string{"bytes"}
+}
+
+func (e *rangedHandlerResource) Count() uint64 {
+	return uint64(len(e.content))
+}
+
+func (e *rangedHandlerResource) Range(rg *Range) (*ContentRange, Resource, error) {
+	return &ContentRange{rg, e.Count()}, &rangedHandlerResource{e.content[rg.From : rg.To+1]}, nil
+}
+
+func (e *rangedHandlerResource) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	w.Write(e.content)
+}
+
+type rangedHandlerEndpoint struct{}
+
+func (e *rangedHandlerEndpoint) Get(vars RouteVars, r *http.Request) (Resource, error) {
+	return &rangedHandlerResource{testCannedBytes}, nil
+}
+
+// trailerResource streams its content, and sends its length in a trailer.
+type trailerResource struct {
+	content []byte
+}
+
+func (e *trailerResource) LastModified() time.Time {
+	return testTimeReference
+}
+
+func (e *trailerResource) ETag() string {
+	return "trailer"
+}
+
+func (e *trailerResource) TTL() time.Duration {
+	return 0
+}
+
+func (e *trailerResource) Trailer() []string {
+	return []string{"x-content-count"}
+}
+
+func (e *trailerResource) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	half := len(e.content) / 2
+	w.Write(e.content[:half])
+	w.(http.Flusher).Flush()
+	w.Write(e.content[half:])
+	w.Header().Set("X-Content-Count", strconv.Itoa(len(e.content)))
+}
+
+type trailerEndpoint struct{}
+
+func (e *trailerEndpoint) Get(vars RouteVars, r *http.Request) (Resource, error) {
+	return &trailerResource{testCannedBytes}, nil
+}
+
+// unseekableBlob is a Ranger unable to serve range requests.
+type unseekableBlob struct {
+	*blob
+}
+
+func (b *unseekableBlob) Range(rg *Range) (*ContentRange, Resource, error) {
+	return nil, nil, ErrRangeUnavailable
+}
+
+type unseekableEndpoint struct{}
+
+func (e *unseekableEndpoint) Get(vars RouteVars, r *http.Request) (Resource, error) {
+	return &unseekableBlob{Blob(testBlobContentType, testBlobContent).(*blob)}, nil
+}
+
+// weakBlob is a Ranger with a weak ETag.
+type weakBlob struct {
+	*blob
+}
+
+func (b *weakBlob) ETag() string {
+	return "W/" + b.blob.ETag()
+}
+
+type weakBlobEndpoint struct{}
+
+func (e *weakBlobEndpoint) Get(vars RouteVars, r *http.Request) (Resource, error) {
+	return &weakBlob{Blob(testBlobContentType, testBlobContent).(*blob)}, nil
+}
+
+// unvalidatedBlob is a resource without validators.
+type unvalidatedBlob struct {
+	*blob
+}
+
+func (b *unvalidatedBlob) ETag() string {
+	return ""
+}
+
+func (b *unvalidatedBlob) LastModified() time.Time {
+	return time.Time{}
+}
+
+type unvalidatedEndpoint struct{}
+
+func (e *unvalidatedEndpoint) Get(vars RouteVars, r *http.Request) (Resource, error) {
+	return &unvalidatedBlob{Blob(testBlobContentType, testBlobContent).(*blob)}, nil
+}
+
 type echoEndpoint struct{}
 
 // Post will simply return any data found in the body of the request.
@@ -314,6 +439,45 @@ func (e *envelopeEndpoint) Get(vars RouteVars, r *http.Request) (Resource, error
 	), nil
 }
 
+var (
+	testBlobContentType = "application/pdf"
+	testBlobContent     = []byte("%PDF-1.4 hello, world!")
+)
+
+type blobEndpoint struct{}
+
+func (e *blobEndpoint) Get(vars RouteVars, r *http.Request) (Resource, error) {
+	return Blob(testBlobContentType, testBlobContent), nil
+}
+
+type largeBlobEndpoint struct{}
+
+func (e *largeBlobEndpoint) Get(vars RouteVars, r *http.Request) (Resource, error) {
+	return Blob("text/plain; charset=utf-8", testMBText), nil
+}
+
+type textEndpoint struct{}
+
+func (e *textEndpoint) Get(vars RouteVars, r *http.Request) (Resource, error) {
+	return Text(testCannedContent), nil
+}
+
+type ttlEndpoint struct{}
+
+// Get returns an envelope with a TTL in seconds taken from the URL.
+func (e *ttlEndpoint) Get(vars RouteVars, r *http.Request) (Resource, error) {
+	seconds, err := strconv.Atoi(vars.Get("seconds"))
+	if err != nil {
+		return nil, NotFound()
+	}
+	return NewEnvelope(
+		envelopeProjection,
+		envelopeLastModified,
+		envelopeETag,
+		time.Duration(seconds)*time.Second,
+	), nil
+}
+
 func TestMain(m *testing.M) {
 	var err error
 
@@ -344,7 +508,16 @@ func TestMain(m *testing.M) {
 
 	testMux.Handle("/echo", EndpointHandler(&echoEndpoint{}))
 	testMux.Handle("/envelope", EndpointHandler(&envelopeEndpoint{}))
+	testMux.Handle("/blob", EndpointHandler(&blobEndpoint{}))
+	testMux.Handle("/text", EndpointHandler(&textEndpoint{}))
+	testMux.Handle("/large-blob", EndpointHandler(&largeBlobEndpoint{}))
+	testMux.Handle("/unseekable", EndpointHandler(&unseekableEndpoint{}))
+	testMux.Handle("/weak", EndpointHandler(&weakBlobEndpoint{}))
+	testMux.Handle("/unvalidated", EndpointHandler(&unvalidatedEndpoint{}))
+	testMux.Handle("/ttl/{seconds}", EndpointHandler(&ttlEndpoint{}))
 	testMux.Handle("/chunked", EndpointHandler(&chunkedEchoEndpoint{}))
+	testMux.Handle("/ranged-handler", EndpointHandler(&rangedHandlerEndpoint{}))
+	testMux.Handle("/trailer", EndpointHandler(&trailerEndpoint{}))
 	testMux.Handle("/panic", EndpointHandler(&panicEndpoint{}))
 	testMux.Handle("/people", EndpointHandler(&peopleCollection{}))
 	testMux.Handle("/people/{id}", EndpointHandler(&personResource{}))
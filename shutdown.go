@@ -0,0 +1,109 @@
+package rst
+
+import (
+	gocontext "context"
+	"net/http"
+	"sync"
+)
+
+// lifecycle tracks the requests served by a Mux, to shut it down gracefully.
+type lifecycle struct {
+	mu       sync.Mutex
+	closing  bool
+	inflight map[*inflightRequest]struct{}
+	idle     chan struct{} // Closed once closing and without requests.
+}
+
+type inflightRequest struct {
+	cancel gocontext.CancelFunc
+}
+
+/*
+Shutdown gracefully shuts s down. Once called, new requests are rejected with
+a 503 Service Unavailable error, and Shutdown waits for the requests being
+served to complete.
+
+If ctx expires first, the contexts of the remaining requests are canceled, and
+Shutdown returns the error of ctx without waiting any further. Long-lived
+responses, like streams, should therefore watch the context of their request:
+
+	for {
+		select {
+		case event := <-events:
+			w.Write(event)
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+Shutdown is meant to be called along with the Shutdown method of the
+http.Server serving s, which stops accepting connections but doesn't cancel
+the requests in flight.
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	go server.Shutdown(ctx)
+	mux.Shutdown(ctx)
+*/
+func (s *Mux) Shutdown(ctx gocontext.Context) error {
+	s.life.mu.Lock()
+	if !s.life.closing {
+		s.life.closing = true
+		s.life.idle = make(chan struct{})
+		if len(s.life.inflight) == 0 {
+			close(s.life.idle)
+		}
+	}
+	idle := s.life.idle
+	s.life.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		s.life.mu.Lock()
+		for req := range s.life.inflight {
+			req.cancel()
+		}
+		s.life.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// track registers r as being served by s, and returns a copy of r whose
+// context is canceled if s is shut down before r is served, along with the
+// function to call once r has been served. A nil function is returned if s is
+// shutting down.
+func (s *Mux) track(r *http.Request) (*http.Request, func()) {
+	s.life.mu.Lock()
+	defer s.life.mu.Unlock()
+	if s.life.closing {
+		return r, nil
+	}
+
+	ctx, cancel := gocontext.WithCancel(r.Context())
+	req := &inflightRequest{cancel}
+	if s.life.inflight == nil {
+		s.life.inflight = make(map[*inflightRequest]struct{})
+	}
+	s.life.inflight[req] = struct{}{}
+
+	return r.WithContext(ctx), func() {
+		cancel()
+		s.life.mu.Lock()
+		defer s.life.mu.Unlock()
+		delete(s.life.inflight, req)
+		if s.life.closing && len(s.life.inflight) == 0 {
+			close(s.life.idle)
+		}
+	}
+}
+
+// shuttingDown returns the error written in the responses to requests
+// received once s is shutting down.
+func shuttingDown() *Error {
+	err := ServiceUnavailable(0)
+	err.Description = "The server is shutting down."
+	err.Header.Set("Connection", "close")
+	return err
+}
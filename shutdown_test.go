@@ -0,0 +1,86 @@
+package rst
+
+import (
+	gocontext "context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMuxShutdown(t *testing.T) {
+	mux := NewMux()
+	started, canceled := make(chan struct{}), make(chan struct{})
+	mux.Handle("/stream", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("event\n"))
+		w.(http.Flusher).Flush()
+		close(started)
+		select {
+		case <-r.Context().Done():
+			close(canceled)
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	mux.Handle("/fast", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	served := make(chan struct{})
+	go func() {
+		defer close(served)
+		r, _ := http.NewRequest(Get, "/stream", nil)
+		mux.ServeHTTP(httptest.NewRecorder(), r)
+	}()
+	<-started
+
+	ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := mux.Shutdown(ctx); err != gocontext.DeadlineExceeded {
+		t.Fatal("Shutdown. Got:", err, "Wanted:", gocontext.DeadlineExceeded)
+	}
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("the context of the stream should be canceled")
+	}
+	<-served
+
+	// New requests are rejected, and Shutdown returns once idle.
+	r, _ := http.NewRequest(Get, "/fast", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatal("status code. Got:", w.Code, "Wanted:", http.StatusServiceUnavailable)
+	}
+	if err := mux.Shutdown(gocontext.Background()); err != nil {
+		t.Fatal("Shutdown of an idle mux. Got:", err, "Wanted: nil")
+	}
+}
+
+func TestMuxShutdownWaits(t *testing.T) {
+	mux := NewMux()
+	started, release := make(chan struct{}), make(chan struct{})
+	mux.Handle("/slow", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		if err := r.Context().Err(); err != nil {
+			t.Error("the context should not be canceled. Got:", err)
+		}
+	}))
+
+	go func() {
+		r, _ := http.NewRequest(Get, "/slow", nil)
+		mux.ServeHTTP(httptest.NewRecorder(), r)
+	}()
+	<-started
+
+	done := make(chan error)
+	go func() { done <- mux.Shutdown(gocontext.Background()) }()
+	select {
+	case err := <-done:
+		t.Fatal("Shutdown returned before the request was served:", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal("Shutdown. Got:", err, "Wanted: nil")
+	}
+}
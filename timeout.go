@@ -0,0 +1,124 @@
+package rst
+
+import (
+	"bytes"
+	gocontext "context"
+	"net/http"
+	"sync"
+)
+
+/*
+serveWithTimeout serves r with the handler of rt in a separate goroutine, and
+writes a 503 Service Unavailable error in the response if the handler doesn't
+return within s.Timeout.
+
+The handler writes in a buffer, which is copied in the response once it
+returns, so that nothing it writes after the timeout can reach the client. The
+context of the request it receives is canceled when the timeout expires.
+
+Since the response is buffered, writes are not flushed to the client before the
+handler returns.
+*/
+func (s *Mux) serveWithTimeout(rt *route, vars RouteVars, w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := gocontext.WithTimeout(r.Context(), s.Timeout)
+	defer cancel()
+
+	// Values stored for r would be cleared before the handler returns.
+	tr := r.WithContext(ctx)
+	setMux(tr, s)
+	setVars(tr, vars)
+	setPattern(tr, rt.pattern)
+	if id := RequestID(r); id != "" {
+		setRequestID(tr, id)
+	}
+
+	tw := &timeoutWriter{header: make(http.Header)}
+	for key, values := range w.Header() {
+		tw.header[key] = append([]string(nil), values...)
+	}
+
+	done := make(chan struct{})
+	panicked := make(chan interface{}, 1)
+	go func() {
+		defer clearContext(tr)
+		defer func() {
+			if err := recover(); err != nil {
+				panicked <- err
+				return
+			}
+			close(done)
+		}()
+		rt.handler.ServeHTTP(newResponseWriter(tw), tr)
+	}()
+
+	select {
+	case err := <-panicked:
+		// Recovered by s.ServeHTTP.
+		panic(err)
+	case <-done:
+		tw.mu.Lock()
+		defer tw.mu.Unlock()
+		// The header of the response becomes the one of the handler,
+		// including the fields it deleted.
+		header := w.Header()
+		for key := range header {
+			if _, kept := tw.header[key]; !kept {
+				delete(header, key)
+			}
+		}
+		for key, values := range tw.header {
+			header[key] = values
+		}
+		if tw.code == 0 {
+			tw.code = http.StatusOK
+		}
+		w.WriteHeader(tw.code)
+		w.Write(tw.body.Bytes())
+	case <-ctx.Done():
+		tw.mu.Lock()
+		tw.timedOut = true
+		tw.mu.Unlock()
+
+		err := ServiceUnavailable(0)
+		if s.TimeoutMessage != "" {
+			err.Description = s.TimeoutMessage
+		}
+		s.writeError(err, w, r)
+	}
+}
+
+// timeoutWriter buffers the response written by a handler served with a
+// timeout.
+type timeoutWriter struct {
+	mu       sync.Mutex
+	header   http.Header
+	body     bytes.Buffer
+	code     int
+	timedOut bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+// Write fails with http.ErrHandlerTimeout once the timeout has expired.
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if tw.code == 0 {
+		tw.code = http.StatusOK
+	}
+	return tw.body.Write(b)
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.code != 0 {
+		return
+	}
+	tw.code = code
+}
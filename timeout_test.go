@@ -0,0 +1,58 @@
+package rst
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMuxTimeout(t *testing.T) {
+	mux := NewMux()
+	mux.Timeout = 50 * time.Millisecond
+	mux.TimeoutMessage = "Too slow."
+	mux.Header().Set("X-Powered-By", "rst")
+	mux.Handle("/fast/{name}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Name", getVars(r).Get("name"))
+		w.Header().Del("X-Powered-By")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("done"))
+	}))
+	mux.Handle("/slow", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		w.Write([]byte("too late"))
+	}))
+
+	var serve = func(url string) *httptest.ResponseRecorder {
+		r, _ := newRequest("GET " + url + " HTTP/1.1\nHost: www.example.com\nAccept: text/plain\n\n")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve("/fast/rst")
+	if w.Code != http.StatusAccepted {
+		t.Fatal("Status code. Got:", w.Code, "Wanted:", http.StatusAccepted)
+	}
+	if got := w.Header().Get("X-Name"); got != "rst" {
+		t.Fatal("X-Name. Got:", got, "Wanted: rst")
+	}
+	if got, ok := w.Header()["X-Powered-By"]; ok {
+		t.Fatal("X-Powered-By deleted by the handler was sent. Got:", got)
+	}
+	if got := w.Body.String(); got != "done" {
+		t.Fatal("Body. Got:", got, "Wanted: done")
+	}
+
+	w = serve("/slow")
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatal("Status code. Got:", w.Code, "Wanted:", http.StatusServiceUnavailable)
+	}
+	if body := w.Body.String(); !strings.Contains(body, "Too slow.") || strings.Contains(body, "too late") {
+		t.Fatal("Body. Got:", body)
+	}
+}
@@ -0,0 +1,45 @@
+package rst
+
+import "net/http"
+
+/*
+Tracer is implemented by types tracing the requests served by a Mux, and is
+meant to bridge rst to tracing libraries like OpenTelemetry without rst
+depending on them.
+
+	type otelTracer struct {
+		tracer trace.Tracer
+	}
+
+	// StartRequest continues the trace propagated in the headers of the
+	// request, and starts a span named after the matched route.
+	func (t *otelTracer) StartRequest(r *http.Request, pattern string) *http.Request {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, _ = t.tracer.Start(ctx, r.Method+" "+pattern, trace.WithSpanKind(trace.SpanKindServer))
+		return r.WithContext(ctx)
+	}
+
+	func (t *otelTracer) FinishRequest(r *http.Request, pattern string, status int) {
+		span := trace.SpanFromContext(r.Context())
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= 500 {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+		span.End()
+	}
+
+	mux.Tracer = &otelTracer{otel.Tracer("api")}
+
+pattern is the pattern of the route matched by the request, like
+/people/{id}, or an empty string for requests which don't match any route.
+*/
+type Tracer interface {
+	// StartRequest is called before a request is served. The request it
+	// returns, usually r with a new context, is the one passed to the
+	// handler and to FinishRequest. A nil request leaves r unchanged.
+	StartRequest(r *http.Request, pattern string) *http.Request
+
+	// FinishRequest is called once the response has been written, with its
+	// status code, including when the handler panicked.
+	FinishRequest(r *http.Request, pattern string, status int)
+}
@@ -0,0 +1,80 @@
+package rst
+
+import (
+	gocontext "context"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type traceKey struct{}
+
+// testTracer records the requests it traced.
+type testTracer struct {
+	started  []string
+	finished []string
+	statuses []int
+	spans    []interface{}
+}
+
+func (t *testTracer) StartRequest(r *http.Request, pattern string) *http.Request {
+	t.started = append(t.started, pattern)
+	return r.WithContext(gocontext.WithValue(r.Context(), traceKey{}, "span "+pattern))
+}
+
+func (t *testTracer) FinishRequest(r *http.Request, pattern string, status int) {
+	t.finished = append(t.finished, pattern)
+	t.statuses = append(t.statuses, status)
+	t.spans = append(t.spans, r.Context().Value(traceKey{}))
+}
+
+func TestTracer(t *testing.T) {
+	tracer := &testTracer{}
+	mux := NewMux()
+	mux.Tracer = tracer
+	mux.Logger = log.New(ioutil.Discard, "", log.Ltime)
+
+	var pattern interface{}
+	mux.Handle("/people/{id}", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		pattern = r.Context().Value(traceKey{})
+		if RoutePattern(r) != "/people/{id}" {
+			t.Fatal("RoutePattern. Got:", RoutePattern(r), "Wanted: /people/{id}")
+		}
+		if vars.Get("id") == "0" {
+			return nil, NotFound()
+		}
+		return Text(testCannedContent), nil
+	}))
+	mux.Handle("/panic", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		panic("boom")
+	}))
+
+	var test = func(path, pattern string, status int) {
+		tracer.started, tracer.finished, tracer.statuses, tracer.spans = nil, nil, nil, nil
+		r, _ := http.NewRequest(Get, path, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != status {
+			t.Fatal(path, "status code. Got:", w.Code, "Wanted:", status)
+		}
+		if len(tracer.started) != 1 || tracer.started[0] != pattern {
+			t.Fatal(path, "StartRequest. Got:", tracer.started, "Wanted:", pattern)
+		}
+		if len(tracer.finished) != 1 || tracer.finished[0] != pattern || tracer.statuses[0] != status {
+			t.Fatal(path, "FinishRequest. Got:", tracer.finished, tracer.statuses, "Wanted:", pattern, status)
+		}
+		if tracer.spans[0] != "span "+pattern {
+			t.Fatal(path, "context of FinishRequest. Got:", tracer.spans[0], "Wanted:", "span "+pattern)
+		}
+	}
+
+	test("/people/1", "/people/{id}", http.StatusOK)
+	if pattern != "span /people/{id}" {
+		t.Fatal("context of the handler. Got:", pattern, "Wanted: span /people/{id}")
+	}
+	test("/people/0", "/people/{id}", http.StatusNotFound)
+	test("/unknown", "", http.StatusNotFound)
+	test("/panic", "/panic", http.StatusInternalServerError)
+}
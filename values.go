@@ -0,0 +1,44 @@
+package rst
+
+import (
+	gocontext "context"
+	"net/http"
+)
+
+/*
+WithValue returns a shallow copy of r carrying value under key, which handlers
+called with the returned request can read with Value. It lets middlewares pass
+data to the endpoints they wrap, like the authenticated user, without mixing
+it with the RouteVars extracted from the path.
+
+	type principalKey struct{}
+
+	func authenticate(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, err := auth.FromToken(r.Header.Get("Authorization"))
+			if err != nil {
+				rst.Unauthorized().ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, rst.WithValue(r, principalKey{}, user))
+		})
+	}
+
+	func (ep *ProfileEP) Get(vars rst.RouteVars, r *http.Request) (rst.Resource, error) {
+		user, _ := rst.Value(r, principalKey{}).(*auth.User)
+		return database.FindProfile(user.ID), nil
+	}
+
+Values are stored in the context of the request, and key follows the rules of
+context.WithValue: it must be comparable, and should be of an unexported type
+to avoid collisions with the keys of other packages. When r is being served by
+a Mux, the returned request keeps its RouteVars, pattern and request ID.
+*/
+func WithValue(r *http.Request, key, value interface{}) *http.Request {
+	return copyContext(r, r.WithContext(gocontext.WithValue(r.Context(), key, value)))
+}
+
+// Value returns the value stored under key in r with WithValue, or nil.
+func Value(r *http.Request, key interface{}) interface{} {
+	return r.Context().Value(key)
+}
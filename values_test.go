@@ -0,0 +1,81 @@
+package rst
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type testPrincipalKey struct{}
+
+func TestValue(t *testing.T) {
+	var got interface{}
+	mux := NewMux()
+	mux.Handle("/me", getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		got = Value(r, testPrincipalKey{})
+		return Text(testCannedContent), nil
+	}))
+	authenticate := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user := r.Header.Get("X-User"); user != "" {
+			r = WithValue(r, testPrincipalKey{}, user)
+		}
+		mux.ServeHTTP(w, r)
+	})
+
+	var test = func(user string, expected interface{}) {
+		got = nil
+		r, _ := http.NewRequest(Get, "/me", nil)
+		if user != "" {
+			r.Header.Set("X-User", user)
+		}
+		authenticate.ServeHTTP(httptest.NewRecorder(), r)
+		if got != expected {
+			t.Fatal(user, "Got:", got, "Wanted:", expected)
+		}
+	}
+
+	test("alice", "alice")
+	test("", nil)
+
+	// Values survive the copy of the request served under a timeout.
+	mux.Timeout = time.Second
+	test("bob", "bob")
+}
+
+func TestValueInRoute(t *testing.T) {
+	var id, pattern, person string
+	var copied *http.Request
+	mux := NewMux()
+	mux.PropagateRequestID = true
+	endpoint := getFunc(func(vars RouteVars, r *http.Request) (Resource, error) {
+		return Text(testCannedContent), nil
+	})
+	mux.Handle("/people/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = WithValue(r, testPrincipalKey{}, "alice")
+		copied = r
+		id, pattern, person = RequestID(r), RoutePattern(r), getVars(r).Get("id")
+		endpoint.ServeHTTP(w, r)
+	}))
+
+	r, _ := http.NewRequest(Get, "/people/1", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	if id == "" || id != w.Header().Get("X-Request-ID") {
+		t.Fatal("Request ID. Got:", id, "Wanted:", w.Header().Get("X-Request-ID"))
+	}
+	if pattern != "/people/{id}" {
+		t.Fatal("Pattern. Got:", pattern, "Wanted:", "/people/{id}")
+	}
+	if person != "1" {
+		t.Fatal("Vars. Got:", person, "Wanted:", "1")
+	}
+	if w.Code != http.StatusOK {
+		t.Fatal("Status code. Got:", w.Code, "Wanted:", http.StatusOK)
+	}
+
+	// Values stored for the copy are cleared with those of the request.
+	if got := RequestID(copied); got != "" {
+		t.Fatal("Values of the copy were not cleared. Got:", got)
+	}
+}